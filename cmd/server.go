@@ -1,16 +1,44 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"project-app-bioskop/pkg/realtime"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func APiserver(route *chi.Mux) {
+// APiserver serves the routes until ctx is cancelled, then shuts down gracefully
+func APiserver(ctx context.Context, route *chi.Mux, seatHub *realtime.Hub) {
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: route,
+	}
+	// Shutdown does not cancel open requests, end the seat streams so it can finish
+	server.RegisterOnShutdown(seatHub.CloseAll)
+
+	// done is closed once Shutdown has drained in-flight requests
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("server shutdown error: %v", err)
+		}
+	}()
+
 	fmt.Println("Server running on port 8080")
-	if err := http.ListenAndServe(":8080", route); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal("can't run service")
 	}
+
+	// ListenAndServe returns as soon as Shutdown starts, wait for it to finish
+	<-done
+	fmt.Println("Server stopped")
 }
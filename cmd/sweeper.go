@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"project-app-bioskop/internal/usecase"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunBookingSweeper periodically settles payments whose confirmation never arrived and
+// expires unpaid bookings until ctx is cancelled
+func RunBookingSweeper(ctx context.Context, bookingUseCase usecase.BookingUseCaseInterface, paymentUseCase usecase.PaymentUseCaseInterface, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("booking sweeper stopped")
+			return
+		case <-ticker.C:
+			settled, err := paymentUseCase.ReconcilePendingPayments(ctx)
+			if err != nil {
+				logger.Error("failed to reconcile pending payments", zap.Error(err))
+			} else if settled > 0 {
+				logger.Info("reconciled pending payments", zap.Int("count", settled))
+			}
+
+			expired, err := bookingUseCase.ExpirePendingBookings(ctx)
+			if err != nil {
+				logger.Error("failed to expire pending bookings", zap.Error(err))
+				continue
+			}
+			if expired > 0 {
+				logger.Info("expired pending bookings", zap.Int("count", expired))
+			}
+		}
+	}
+}
@@ -0,0 +1,9 @@
+-- Pending bookings hold their seats only until expires_at.
+-- The background sweeper moves overdue pending bookings to status 'expired'.
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS expires_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_pending_expiry
+    ON public.bookings USING btree (expires_at)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_bookings_showtime ON public.bookings USING btree (showtime_id);
@@ -0,0 +1,15 @@
+-- Refunds created when a paid booking is cancelled.
+-- Lifecycle: pending -> processing -> completed | failed
+CREATE TABLE IF NOT EXISTS public.refunds (
+    id serial PRIMARY KEY,
+    payment_id integer NOT NULL REFERENCES public.payments(id) ON DELETE CASCADE,
+    booking_id integer NOT NULL REFERENCES public.bookings(id) ON DELETE CASCADE,
+    amount numeric(12,2) NOT NULL,
+    status character varying(20) NOT NULL DEFAULT 'pending',
+    reason text,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_refunds_payment ON public.refunds USING btree (payment_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_booking ON public.refunds USING btree (booking_id);
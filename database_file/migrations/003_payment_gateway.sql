@@ -0,0 +1,9 @@
+-- Each payment method is processed by a named gateway implementation.
+ALTER TABLE public.payment_methods ADD COLUMN IF NOT EXISTS gateway character varying(50) NOT NULL DEFAULT 'simulator';
+
+-- Payments follow pending -> completed | failed (-> refunded) and keep the provider transaction ID.
+ALTER TABLE public.payments ADD COLUMN IF NOT EXISTS transaction_id character varying(100);
+ALTER TABLE public.payments ADD COLUMN IF NOT EXISTS updated_at timestamp with time zone DEFAULT now();
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_transaction ON public.payments USING btree (transaction_id)
+    WHERE transaction_id IS NOT NULL;
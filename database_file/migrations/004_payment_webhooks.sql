@@ -0,0 +1,15 @@
+-- Shared secret used to verify the HMAC signature of provider callbacks for each payment method.
+ALTER TABLE public.payment_methods ADD COLUMN IF NOT EXISTS webhook_secret character varying(255);
+
+-- Every accepted provider callback is recorded once per method and transaction ID.
+CREATE TABLE IF NOT EXISTS public.payment_webhook_events (
+    id serial PRIMARY KEY,
+    payment_method_id integer NOT NULL REFERENCES public.payment_methods(id),
+    transaction_id character varying(100) NOT NULL,
+    status character varying(20) NOT NULL,
+    payload jsonb NOT NULL,
+    received_at timestamp with time zone DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payment_webhook_events_transaction
+    ON public.payment_webhook_events USING btree (payment_method_id, transaction_id);
@@ -0,0 +1,14 @@
+-- Idempotency-Key records per user for retry-safe POST endpoints.
+-- A row without status_code is still being processed; completed rows keep the response to replay.
+CREATE TABLE IF NOT EXISTS public.idempotency_keys (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    idem_key character varying(255) NOT NULL,
+    request_hash character(64) NOT NULL,
+    status_code integer,
+    response_body bytea,
+    created_at timestamp with time zone DEFAULT now(),
+    completed_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_idempotency_keys_user_key ON public.idempotency_keys USING btree (user_id, idem_key);
@@ -0,0 +1,10 @@
+-- Access roles: customer (default), cinema_staff and admin.
+-- Bootstrap the first admin manually: UPDATE public.users SET role = 'admin' WHERE username = '...';
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS role character varying(20) NOT NULL DEFAULT 'customer';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'users_role_check') THEN
+        ALTER TABLE public.users ADD CONSTRAINT users_role_check CHECK (role IN ('customer', 'cinema_staff', 'admin'));
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+-- Movies are soft deleted so past showtimes and bookings keep their movie.
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS idx_movies_active ON public.movies USING btree (created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_showtimes_movie_date ON public.showtimes USING btree (movie_id, show_date);
@@ -0,0 +1,6 @@
+-- Seats generated from a studio layout keep their row and physical column.
+-- column_number counts aisle gaps, so seats on both sides of an aisle are not adjacent.
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS row_label character varying(5);
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS column_number integer;
+
+CREATE INDEX IF NOT EXISTS idx_booking_seats_seat ON public.booking_seats USING btree (seat_id);
@@ -0,0 +1,7 @@
+-- Showtimes are cancelled instead of deleted so bookings and refunds keep their showtime.
+-- Lifecycle: scheduled -> cancelled
+ALTER TABLE public.showtimes ADD COLUMN IF NOT EXISTS status character varying(20) NOT NULL DEFAULT 'scheduled';
+ALTER TABLE public.showtimes ADD COLUMN IF NOT EXISTS cancelled_at timestamp with time zone;
+
+-- Conflict detection scans the scheduled showtimes of one studio around a date
+CREATE INDEX IF NOT EXISTS idx_showtimes_studio_date ON public.showtimes USING btree (studio_id, show_date) WHERE status = 'scheduled';
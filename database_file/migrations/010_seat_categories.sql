@@ -0,0 +1,26 @@
+-- Seat categories price a seat relative to its showtime's base price.
+CREATE TABLE IF NOT EXISTS public.seat_categories (
+    id serial PRIMARY KEY,
+    name character varying(20) NOT NULL,
+    price_multiplier numeric(5,2) NOT NULL DEFAULT 1.00,
+    CONSTRAINT seat_categories_price_multiplier_check CHECK (price_multiplier > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_seat_categories_name ON public.seat_categories USING btree (name);
+
+INSERT INTO public.seat_categories (name, price_multiplier) VALUES
+    ('regular', 1.00),
+    ('vip', 1.50),
+    ('couple', 2.00)
+ON CONFLICT (name) DO NOTHING;
+
+-- Existing seats become regular seats
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS category character varying(20) NOT NULL DEFAULT 'regular';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'seats_category_fkey') THEN
+        ALTER TABLE public.seats ADD CONSTRAINT seats_category_fkey
+            FOREIGN KEY (category) REFERENCES public.seat_categories(name) ON UPDATE CASCADE;
+    END IF;
+END $$;
@@ -0,0 +1,6 @@
+-- Seats created before layouts were generated get coordinates from their code, e.g. A10 -> row A, column 10.
+UPDATE public.seats
+SET row_label = upper(substring(seat_code FROM '^[A-Za-z]+')),
+    column_number = substring(seat_code FROM '[0-9]+$')::integer
+WHERE (row_label IS NULL OR column_number IS NULL)
+  AND seat_code ~ '^[A-Za-z]+[0-9]+$';
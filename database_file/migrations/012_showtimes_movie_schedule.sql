@@ -0,0 +1,2 @@
+-- A movie's schedule scans only its scheduled showtimes from today onwards
+CREATE INDEX IF NOT EXISTS idx_showtimes_movie_scheduled ON public.showtimes USING btree (movie_id, show_date, show_time) WHERE status = 'scheduled';
@@ -0,0 +1,35 @@
+-- Movie detail page: synopsis, classification, language and trailer.
+-- Age ratings follow the Indonesian classification: SU (all ages), 13+, 17+ and 21+.
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS synopsis text NOT NULL DEFAULT '';
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS age_rating character varying(10) NOT NULL DEFAULT 'SU';
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS language character varying(50) NOT NULL DEFAULT '';
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS trailer_url character varying(2048) NOT NULL DEFAULT '';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'movies_age_rating_check') THEN
+        ALTER TABLE public.movies ADD CONSTRAINT movies_age_rating_check
+            CHECK (age_rating IN ('SU', '13+', '17+', '21+'));
+    END IF;
+END $$;
+
+-- Cast in billing order
+CREATE TABLE IF NOT EXISTS public.movie_cast (
+    id serial PRIMARY KEY,
+    movie_id integer NOT NULL REFERENCES public.movies(id) ON DELETE CASCADE,
+    name character varying(100) NOT NULL,
+    character_name character varying(100) NOT NULL DEFAULT '',
+    billing_order integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_cast_movie ON public.movie_cast USING btree (movie_id, billing_order);
+
+-- Crew by job, e.g. Director, Writer, Producer
+CREATE TABLE IF NOT EXISTS public.movie_crew (
+    id serial PRIMARY KEY,
+    movie_id integer NOT NULL REFERENCES public.movies(id) ON DELETE CASCADE,
+    name character varying(100) NOT NULL,
+    job character varying(50) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_crew_movie ON public.movie_crew USING btree (movie_id);
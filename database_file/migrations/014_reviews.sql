@@ -0,0 +1,15 @@
+-- One rating and review per user and movie; movies.rating and review_count are
+-- recomputed from this table whenever a review changes.
+CREATE TABLE IF NOT EXISTS public.reviews (
+    id serial PRIMARY KEY,
+    movie_id integer NOT NULL REFERENCES public.movies(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    rating smallint NOT NULL,
+    comment text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT reviews_rating_check CHECK (rating BETWEEN 1 AND 5)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_reviews_movie_user ON public.reviews USING btree (movie_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_movie_created ON public.reviews USING btree (movie_id, created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+-- Date of birth for enforcing movie age classifications at booking time.
+-- Nullable: existing users add it to their profile before booking a restricted movie.
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS date_of_birth date;
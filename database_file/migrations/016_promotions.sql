@@ -0,0 +1,45 @@
+-- Promo codes applied at booking time. A code takes a percentage (optionally capped)
+-- or a fixed amount off the booking and may be limited to a movie, cinema, weekdays
+-- of the show date, payment method and minimum number of seats. Usage is counted from
+-- the active bookings that redeemed it, so cancelled and expired bookings free it again.
+CREATE TABLE IF NOT EXISTS public.promotions (
+    id serial PRIMARY KEY,
+    code character varying(50) NOT NULL,
+    description character varying(255) NOT NULL DEFAULT '',
+    discount_type character varying(20) NOT NULL,
+    discount_value numeric(12,2) NOT NULL,
+    max_discount numeric(12,2) NOT NULL DEFAULT 0,
+    movie_id integer REFERENCES public.movies(id) ON DELETE CASCADE,
+    cinema_id integer REFERENCES public.cinemas(id) ON DELETE CASCADE,
+    payment_method_id integer REFERENCES public.payment_methods(id) ON DELETE CASCADE,
+    days_of_week integer[] NOT NULL DEFAULT '{}',
+    min_seats integer NOT NULL DEFAULT 0,
+    usage_limit integer NOT NULL DEFAULT 0,
+    per_user_limit integer NOT NULL DEFAULT 0,
+    valid_from timestamp with time zone NOT NULL,
+    valid_until timestamp with time zone NOT NULL,
+    is_active boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT promotions_discount_type_check CHECK (discount_type IN ('percentage', 'fixed')),
+    CONSTRAINT promotions_discount_value_check CHECK (discount_value > 0 AND (discount_type <> 'percentage' OR discount_value <= 100)),
+    CONSTRAINT promotions_validity_check CHECK (valid_until > valid_from)
+);
+
+-- Codes are matched case-insensitively
+CREATE UNIQUE INDEX IF NOT EXISTS ux_promotions_code ON public.promotions USING btree (upper(code));
+
+-- The redeemed promotion and its discount are kept on the booking; total_amount is after discount
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS promotion_id integer;
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS promo_code character varying(50);
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS discount_amount numeric(12,2) NOT NULL DEFAULT 0;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'bookings_promotion_id_fkey') THEN
+        ALTER TABLE public.bookings ADD CONSTRAINT bookings_promotion_id_fkey
+            FOREIGN KEY (promotion_id) REFERENCES public.promotions(id) ON DELETE SET NULL;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_promotion_user ON public.bookings USING btree (promotion_id, user_id) WHERE promotion_id IS NOT NULL;
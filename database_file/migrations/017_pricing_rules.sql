@@ -0,0 +1,28 @@
+-- Pricing rules adjust a showtime's base price when it is resolved for the seat map
+-- and for a booking. A rule matches when every condition it sets holds: the cinema,
+-- the show date falling on one of its weekdays (0 = Sunday) or listed dates such as
+-- holidays, the show time within [time_from, time_until), and the studio occupancy
+-- having reached min_occupancy percent. Matching rules apply in priority order;
+-- percentage adjustments compound and negative values are discounts.
+CREATE TABLE IF NOT EXISTS public.pricing_rules (
+    id serial PRIMARY KEY,
+    name character varying(100) NOT NULL,
+    cinema_id integer REFERENCES public.cinemas(id) ON DELETE CASCADE,
+    days_of_week integer[] NOT NULL DEFAULT '{}',
+    dates date[] NOT NULL DEFAULT '{}',
+    time_from time without time zone,
+    time_until time without time zone,
+    min_occupancy numeric(5,2) NOT NULL DEFAULT 0,
+    adjustment_type character varying(20) NOT NULL,
+    adjustment_value numeric(12,2) NOT NULL,
+    priority integer NOT NULL DEFAULT 0,
+    is_active boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT pricing_rules_adjustment_type_check CHECK (adjustment_type IN ('percentage', 'fixed')),
+    CONSTRAINT pricing_rules_adjustment_value_check CHECK (adjustment_value <> 0 AND (adjustment_type <> 'percentage' OR adjustment_value > -100)),
+    CONSTRAINT pricing_rules_min_occupancy_check CHECK (min_occupancy BETWEEN 0 AND 100),
+    CONSTRAINT pricing_rules_time_window_check CHECK (time_from IS NULL OR time_until IS NULL OR time_until > time_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_active ON public.pricing_rules USING btree (priority, id) WHERE is_active;
@@ -0,0 +1,28 @@
+-- Loyalty points ledger. Every paid booking earns a lot of points ('earn') that expires;
+-- remaining tracks how much of the lot is still spendable. Redeeming points at booking
+-- consumes lots earliest expiry first, one 'redeem' row per lot consumed. Cancelling or
+-- expiring a booking writes 'reversal' rows that return its redeemed points to their lots
+-- and revoke whatever is left of the points it earned.
+CREATE TABLE IF NOT EXISTS public.loyalty_ledger (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    booking_id integer REFERENCES public.bookings(id) ON DELETE SET NULL,
+    entry_type character varying(20) NOT NULL,
+    points integer NOT NULL,
+    remaining integer NOT NULL DEFAULT 0,
+    lot_id integer REFERENCES public.loyalty_ledger(id) ON DELETE CASCADE,
+    expires_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT loyalty_ledger_entry_type_check CHECK (entry_type IN ('earn', 'redeem', 'reversal')),
+    CONSTRAINT loyalty_ledger_remaining_check CHECK (remaining >= 0 AND remaining <= GREATEST(points, 0))
+);
+
+-- A booking earns points once
+CREATE UNIQUE INDEX IF NOT EXISTS ux_loyalty_ledger_earn_booking ON public.loyalty_ledger USING btree (booking_id) WHERE entry_type = 'earn';
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_user_created ON public.loyalty_ledger USING btree (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_spendable ON public.loyalty_ledger USING btree (user_id, expires_at) WHERE entry_type = 'earn' AND remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_booking ON public.loyalty_ledger USING btree (booking_id);
+
+-- Points redeemed on a booking and the amount they took off; total_amount is after it
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS points_redeemed integer NOT NULL DEFAULT 0;
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS points_discount numeric(12,2) NOT NULL DEFAULT 0;
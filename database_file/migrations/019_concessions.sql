@@ -0,0 +1,32 @@
+-- Food and beverage sold per cinema. stock is what is left to sell; a booking takes its
+-- quantities off in the booking transaction and a cancelled or expired booking puts
+-- them back.
+CREATE TABLE IF NOT EXISTS public.concessions (
+    id serial PRIMARY KEY,
+    cinema_id integer NOT NULL REFERENCES public.cinemas(id) ON DELETE CASCADE,
+    name character varying(100) NOT NULL,
+    category character varying(20) NOT NULL,
+    description character varying(255) NOT NULL DEFAULT '',
+    price numeric(12,2) NOT NULL,
+    stock integer NOT NULL DEFAULT 0,
+    is_active boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT concessions_category_check CHECK (category IN ('popcorn', 'drink', 'snack', 'combo')),
+    CONSTRAINT concessions_price_check CHECK (price >= 0),
+    CONSTRAINT concessions_stock_check CHECK (stock >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_concessions_cinema ON public.concessions USING btree (cinema_id, category, name);
+
+-- Concession line items of a booking, priced like booking_seats at the time of booking
+CREATE TABLE IF NOT EXISTS public.booking_concessions (
+    id serial PRIMARY KEY,
+    booking_id integer NOT NULL REFERENCES public.bookings(id) ON DELETE CASCADE,
+    concession_id integer NOT NULL REFERENCES public.concessions(id),
+    quantity integer NOT NULL,
+    price_snapshot numeric(12,2) NOT NULL,
+    CONSTRAINT booking_concessions_quantity_check CHECK (quantity > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_booking_concessions_item ON public.booking_concessions USING btree (booking_id, concession_id);
@@ -0,0 +1,6 @@
+-- E-tickets. Every booked seat of a paid booking is a ticket; its QR code carries a token
+-- signed by the application, so nothing is stored to issue one. Scanning the ticket at
+-- the door sets admitted_at, and a ticket that has it set cannot be admitted again.
+ALTER TABLE public.booking_seats
+    ADD COLUMN IF NOT EXISTS admitted_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS admitted_by integer REFERENCES public.users(id);
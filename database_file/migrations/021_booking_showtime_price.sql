@@ -0,0 +1,17 @@
+-- The showtime price a booking was priced at: the base price after pricing rules, before
+-- seat categories. Existing bookings get it back from a seat snapshot and its category
+-- multiplier, or the showtime's base price when that is not available.
+ALTER TABLE public.bookings ADD COLUMN IF NOT EXISTS showtime_price numeric(12,2);
+
+UPDATE public.bookings b SET showtime_price = COALESCE(
+    (SELECT ROUND(bs.price_snapshot / c.price_multiplier, 2)
+     FROM public.booking_seats bs
+     INNER JOIN public.seats s ON s.id = bs.seat_id
+     INNER JOIN public.seat_categories c ON c.name = s.category
+     WHERE bs.booking_id = b.id
+     ORDER BY bs.id LIMIT 1),
+    (SELECT st.price FROM public.showtimes st WHERE st.id = b.showtime_id)
+)
+WHERE b.showtime_price IS NULL;
+
+ALTER TABLE public.bookings ALTER COLUMN showtime_price SET NOT NULL;
@@ -0,0 +1,13 @@
+-- Reversing a booking takes back all the points it earned. What was already spent is
+-- taken from the user's other open lots, and what those cannot cover is recorded as a
+-- 'debt' entry whose remaining is still owed. Debts count against the balance and are
+-- settled from the lots before the user's next redemption.
+ALTER TABLE public.loyalty_ledger DROP CONSTRAINT IF EXISTS loyalty_ledger_entry_type_check;
+ALTER TABLE public.loyalty_ledger ADD CONSTRAINT loyalty_ledger_entry_type_check
+    CHECK (entry_type IN ('earn', 'redeem', 'reversal', 'debt'));
+
+ALTER TABLE public.loyalty_ledger DROP CONSTRAINT IF EXISTS loyalty_ledger_remaining_check;
+ALTER TABLE public.loyalty_ledger ADD CONSTRAINT loyalty_ledger_remaining_check
+    CHECK (remaining >= 0 AND remaining <= ABS(points));
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_debt ON public.loyalty_ledger USING btree (user_id) WHERE entry_type = 'debt' AND remaining > 0;
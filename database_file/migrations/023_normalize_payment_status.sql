@@ -0,0 +1,8 @@
+-- Payments written before the gateway rework carry legacy statuses such as 'PAID' or
+-- 'paid'. Settled payments are 'completed' since 003, and cancellations only refund
+-- completed payments, so bring the legacy rows onto the current statuses.
+UPDATE public.payments SET status = 'completed', updated_at = NOW()
+WHERE LOWER(status) IN ('paid', 'success', 'settled', 'completed') AND status <> 'completed';
+
+UPDATE public.payments SET status = LOWER(status), updated_at = NOW()
+WHERE LOWER(status) IN ('pending', 'failed', 'refunded') AND status <> LOWER(status);
@@ -0,0 +1,14 @@
+-- A booking may have at most one payment that is awaiting confirmation or has completed.
+-- The check in ProcessPayment alone lets two concurrent requests both create a pending
+-- attempt, so enforce it here. Pending attempts that were superseded by a later pending
+-- or a completed payment of the same booking are marked failed first.
+UPDATE public.payments p SET status = 'failed', updated_at = NOW()
+WHERE p.status = 'pending'
+  AND EXISTS (
+      SELECT 1 FROM public.payments o
+      WHERE o.booking_id = p.booking_id AND o.id <> p.id
+        AND (o.status = 'completed' OR (o.status = 'pending' AND o.id > p.id))
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_active_booking
+    ON public.payments (booking_id) WHERE status IN ('pending', 'completed');
@@ -3,33 +3,61 @@ package adaptor
 import (
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
+
+	"go.uber.org/zap"
 )
 
 type Adaptor struct {
-	AuthAdaptor    *AuthAdaptor
-	CinemaAdaptor  *CinemaAdaptor
-	SeatAdaptor    *SeatAdaptor
-	BookingAdaptor *BookingAdaptor
-	PaymentAdaptor *PaymentAdaptor
-	MovieAdaptor   *MovieAdaptor
+	AuthAdaptor       *AuthAdaptor
+	CinemaAdaptor     *CinemaAdaptor
+	SeatAdaptor       *SeatAdaptor
+	BookingAdaptor    *BookingAdaptor
+	PaymentAdaptor    *PaymentAdaptor
+	MovieAdaptor      *MovieAdaptor
+	ShowtimeAdaptor   *ShowtimeAdaptor
+	ReviewAdaptor     *ReviewAdaptor
+	UserAdaptor       *UserAdaptor
+	PromotionAdaptor  *PromotionAdaptor
+	PricingAdaptor    *PricingAdaptor
+	LoyaltyAdaptor    *LoyaltyAdaptor
+	ConcessionAdaptor *ConcessionAdaptor
+	TicketAdaptor     *TicketAdaptor
 }
 
-func NewAdaptor(repo *repository.Repository, config utils.Configuration) *Adaptor {
+func NewAdaptor(repo *repository.Repository, config utils.Configuration, gateways *gateway.Registry, seatHub *realtime.Hub, logger *zap.Logger) *Adaptor {
 	// Initialize all usecases
 	authUseCase := usecase.NewAuthUseCase(repo)
 	cinemaUseCase := usecase.NewCinemaUseCase(repo)
-	seatUseCase := usecase.NewSeatUseCase(repo)
-	bookingUseCase := usecase.NewBookingUseCase(repo)
-	paymentUseCase := usecase.NewPaymentUseCase(repo)
+	seatUseCase := usecase.NewSeatUseCase(repo, seatHub)
+	bookingUseCase := usecase.NewBookingUseCase(repo, config, gateways, seatHub)
+	paymentUseCase := usecase.NewPaymentUseCase(repo, config, gateways, seatHub, logger)
 	movieUseCase := usecase.NewMovieUseCase(repo)
+	showtimeUseCase := usecase.NewShowtimeUseCase(repo, config, gateways, seatHub)
+	reviewUseCase := usecase.NewReviewUseCase(repo)
+	userUseCase := usecase.NewUserUseCase(repo)
+	promotionUseCase := usecase.NewPromotionUseCase(repo)
+	pricingUseCase := usecase.NewPricingUseCase(repo)
+	loyaltyUseCase := usecase.NewLoyaltyUseCase(repo, config)
+	concessionUseCase := usecase.NewConcessionUseCase(repo)
+	ticketUseCase := usecase.NewTicketUseCase(repo, config)
 
 	return &Adaptor{
-		AuthAdaptor:    NewAuthAdaptor(authUseCase),
-		CinemaAdaptor:  NewCinemaAdaptor(cinemaUseCase, config),
-		SeatAdaptor:    NewSeatAdaptor(seatUseCase),
-		BookingAdaptor: NewBookingAdaptor(bookingUseCase),
-		PaymentAdaptor: NewPaymentAdaptor(paymentUseCase),
-		MovieAdaptor:   NewMovieAdaptor(movieUseCase, config),
+		AuthAdaptor:       NewAuthAdaptor(authUseCase),
+		CinemaAdaptor:     NewCinemaAdaptor(cinemaUseCase, config),
+		SeatAdaptor:       NewSeatAdaptor(seatUseCase),
+		BookingAdaptor:    NewBookingAdaptor(bookingUseCase),
+		PaymentAdaptor:    NewPaymentAdaptor(paymentUseCase),
+		MovieAdaptor:      NewMovieAdaptor(movieUseCase, config),
+		ShowtimeAdaptor:   NewShowtimeAdaptor(showtimeUseCase),
+		ReviewAdaptor:     NewReviewAdaptor(reviewUseCase, config),
+		UserAdaptor:       NewUserAdaptor(userUseCase),
+		PromotionAdaptor:  NewPromotionAdaptor(promotionUseCase, config),
+		PricingAdaptor:    NewPricingAdaptor(pricingUseCase),
+		LoyaltyAdaptor:    NewLoyaltyAdaptor(loyaltyUseCase, config),
+		ConcessionAdaptor: NewConcessionAdaptor(concessionUseCase),
+		TicketAdaptor:     NewTicketAdaptor(ticketUseCase),
 	}
 }
@@ -2,11 +2,14 @@ package adaptor
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"project-app-bioskop/internal/dto"
 	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/pkg/utils"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -128,3 +131,35 @@ func (a *AuthAdaptor) Logout(w http.ResponseWriter, r *http.Request) {
 
 	utils.ResponseOK(w, "logout successful", nil)
 }
+
+// UpdateRole handles assigning an access role to a user
+func (a *AuthAdaptor) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid user id", nil)
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	user, err := a.UseCase.UpdateUserRole(r.Context(), userID, req)
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to update user role")
+		return
+	}
+
+	utils.ResponseOK(w, "user role updated", user)
+}
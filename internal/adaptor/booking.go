@@ -2,14 +2,23 @@ package adaptor
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"project-app-bioskop/internal/dto"
 	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/pkg/utils"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 )
 
+// Error codes that let clients tell age-classification refusals apart
+const (
+	ErrCodeAgeRestricted     = "age_restricted"
+	ErrCodeBirthDateRequired = "birth_date_required"
+)
+
 type BookingAdaptor struct {
 	UseCase  usecase.BookingUseCaseInterface
 	Validate *validator.Validate
@@ -42,6 +51,26 @@ func (a *BookingAdaptor) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	booking, err := a.UseCase.CreateBooking(r.Context(), userID, req)
+	if errors.Is(err, usecase.ErrSeatTaken) || errors.Is(err, usecase.ErrNotEnoughSeats) {
+		utils.ResponseConflict(w, err.Error())
+		return
+	}
+	if errors.Is(err, usecase.ErrConcessionUnavailable) {
+		utils.ResponseConflict(w, err.Error())
+		return
+	}
+	if errors.Is(err, usecase.ErrPromoExhausted) || errors.Is(err, usecase.ErrPromoUserLimit) {
+		utils.ResponseConflict(w, err.Error())
+		return
+	}
+	if errors.Is(err, usecase.ErrAgeRestricted) {
+		utils.ResponseError(w, http.StatusForbidden, err.Error(), map[string]string{"code": ErrCodeAgeRestricted})
+		return
+	}
+	if errors.Is(err, usecase.ErrBirthDateRequired) {
+		utils.ResponseError(w, http.StatusForbidden, err.Error(), map[string]string{"code": ErrCodeBirthDateRequired})
+		return
+	}
 	if err != nil {
 		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -66,3 +95,50 @@ func (a *BookingAdaptor) GetUserBookings(w http.ResponseWriter, r *http.Request)
 
 	utils.ResponseOK(w, "success get booking history", bookings)
 }
+
+// Cancel handles booking cancellation by its owner
+func (a *BookingAdaptor) Cancel(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	bookingID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid booking id", nil)
+		return
+	}
+
+	// Body is optional, it only carries the cancellation reason
+	var req dto.CancelBookingRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+			return
+		}
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	result, err := a.UseCase.CancelBooking(r.Context(), userID, bookingID, req)
+	switch {
+	case errors.Is(err, usecase.ErrBookingNotFound):
+		utils.ResponseNotFound(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrBookingNotOwned):
+		utils.ResponseForbidden(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrBookingNotCancellable), errors.Is(err, usecase.ErrCancellationClosed):
+		utils.ResponseConflict(w, err.Error())
+		return
+	case err != nil:
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.ResponseOK(w, "booking cancelled successfully", result)
+}
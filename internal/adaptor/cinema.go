@@ -1,23 +1,29 @@
 package adaptor
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"project-app-bioskop/internal/dto"
 	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/pkg/utils"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 )
 
 type CinemaAdaptor struct {
-	UseCase usecase.CinemaUseCaseInterface
-	Config  utils.Configuration
+	UseCase  usecase.CinemaUseCaseInterface
+	Config   utils.Configuration
+	Validate *validator.Validate
 }
 
 func NewCinemaAdaptor(useCase usecase.CinemaUseCaseInterface, config utils.Configuration) *CinemaAdaptor {
 	return &CinemaAdaptor{
-		UseCase: useCase,
-		Config:  config,
+		UseCase:  useCase,
+		Config:   config,
+		Validate: validator.New(),
 	}
 }
 
@@ -60,3 +66,156 @@ func (a *CinemaAdaptor) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	utils.ResponseOK(w, "success get cinema", cinema)
 }
+
+// Create handles adding a new cinema
+func (a *CinemaAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CinemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	cinema, err := a.UseCase.CreateCinema(r.Context(), req)
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to create cinema")
+		return
+	}
+
+	utils.ResponseCreated(w, "cinema created successfully", cinema)
+}
+
+// Update handles changing a cinema's details
+func (a *CinemaAdaptor) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "cinemaId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid cinema id", nil)
+		return
+	}
+
+	var req dto.CinemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	cinema, err := a.UseCase.UpdateCinema(r.Context(), id, req)
+	if errors.Is(err, usecase.ErrCinemaNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to update cinema")
+		return
+	}
+
+	utils.ResponseOK(w, "cinema updated successfully", cinema)
+}
+
+// CreateStudio handles adding a studio to a cinema
+func (a *CinemaAdaptor) CreateStudio(w http.ResponseWriter, r *http.Request) {
+	cinemaID, err := strconv.Atoi(chi.URLParam(r, "cinemaId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid cinema id", nil)
+		return
+	}
+
+	var req dto.StudioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	studio, err := a.UseCase.CreateStudio(r.Context(), cinemaID, req)
+	if errors.Is(err, usecase.ErrCinemaNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to create studio")
+		return
+	}
+
+	utils.ResponseCreated(w, "studio created successfully", studio)
+}
+
+// UpdateStudio handles renaming a studio
+func (a *CinemaAdaptor) UpdateStudio(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "studioId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid studio id", nil)
+		return
+	}
+
+	var req dto.StudioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	studio, err := a.UseCase.UpdateStudio(r.Context(), id, req)
+	if errors.Is(err, usecase.ErrStudioNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to update studio")
+		return
+	}
+
+	utils.ResponseOK(w, "studio updated successfully", studio)
+}
+
+// GenerateSeats handles replacing a studio's seats from a layout definition
+func (a *CinemaAdaptor) GenerateSeats(w http.ResponseWriter, r *http.Request) {
+	studioID, err := strconv.Atoi(chi.URLParam(r, "studioId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid studio id", nil)
+		return
+	}
+
+	var req dto.SeatLayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	layout, err := a.UseCase.GenerateSeatLayout(r.Context(), studioID, req)
+	switch {
+	case errors.Is(err, usecase.ErrStudioNotFound):
+		utils.ResponseNotFound(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrSeatsInUse):
+		utils.ResponseConflict(w, err.Error())
+		return
+	case err != nil:
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.ResponseOK(w, "seat layout generated successfully", layout)
+}
@@ -0,0 +1,120 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ConcessionAdaptor struct {
+	UseCase  usecase.ConcessionUseCaseInterface
+	Validate *validator.Validate
+}
+
+func NewConcessionAdaptor(useCase usecase.ConcessionUseCaseInterface) *ConcessionAdaptor {
+	return &ConcessionAdaptor{
+		UseCase:  useCase,
+		Validate: validator.New(),
+	}
+}
+
+// GetByCinema handles listing the concessions on sale at a cinema
+func (a *ConcessionAdaptor) GetByCinema(w http.ResponseWriter, r *http.Request) {
+	a.list(w, r, false)
+}
+
+// GetAllByCinema handles listing every concession of a cinema, including those off sale
+func (a *ConcessionAdaptor) GetAllByCinema(w http.ResponseWriter, r *http.Request) {
+	a.list(w, r, true)
+}
+
+func (a *ConcessionAdaptor) list(w http.ResponseWriter, r *http.Request, includeInactive bool) {
+	cinemaID, err := strconv.Atoi(chi.URLParam(r, "cinemaId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid cinema id", nil)
+		return
+	}
+
+	concessions, err := a.UseCase.GetConcessions(r.Context(), cinemaID, includeInactive)
+	if err != nil {
+		writeConcessionError(w, err, "failed to get concessions")
+		return
+	}
+
+	utils.ResponseOK(w, "success get concessions", concessions)
+}
+
+// Create handles adding a concession to a cinema
+func (a *ConcessionAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	cinemaID, err := strconv.Atoi(chi.URLParam(r, "cinemaId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid cinema id", nil)
+		return
+	}
+
+	req, ok := a.decodeConcession(w, r)
+	if !ok {
+		return
+	}
+
+	concession, err := a.UseCase.CreateConcession(r.Context(), cinemaID, req)
+	if err != nil {
+		writeConcessionError(w, err, "failed to create concession")
+		return
+	}
+
+	utils.ResponseCreated(w, "concession created successfully", concession)
+}
+
+// Update handles replacing a concession's details and stock
+func (a *ConcessionAdaptor) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "concessionId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid concession id", nil)
+		return
+	}
+
+	req, ok := a.decodeConcession(w, r)
+	if !ok {
+		return
+	}
+
+	concession, err := a.UseCase.UpdateConcession(r.Context(), id, req)
+	if err != nil {
+		writeConcessionError(w, err, "failed to update concession")
+		return
+	}
+
+	utils.ResponseOK(w, "concession updated successfully", concession)
+}
+
+func (a *ConcessionAdaptor) decodeConcession(w http.ResponseWriter, r *http.Request) (dto.ConcessionRequest, bool) {
+	var req dto.ConcessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return req, false
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return req, false
+	}
+
+	return req, true
+}
+
+func writeConcessionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrCinemaNotFound), errors.Is(err, usecase.ErrConcessionNotFound):
+		utils.ResponseNotFound(w, err.Error())
+	default:
+		utils.ResponseInternalError(w, fallback)
+	}
+}
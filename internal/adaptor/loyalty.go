@@ -0,0 +1,65 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+)
+
+type LoyaltyAdaptor struct {
+	UseCase usecase.LoyaltyUseCaseInterface
+	Config  utils.Configuration
+}
+
+func NewLoyaltyAdaptor(useCase usecase.LoyaltyUseCaseInterface, config utils.Configuration) *LoyaltyAdaptor {
+	return &LoyaltyAdaptor{
+		UseCase: useCase,
+		Config:  config,
+	}
+}
+
+// GetBalance handles get the caller's spendable loyalty points
+func (a *LoyaltyAdaptor) GetBalance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	balance, err := a.UseCase.GetBalance(r.Context(), userID)
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get points balance")
+		return
+	}
+
+	utils.ResponseOK(w, "success get points balance", balance)
+}
+
+// GetHistory handles listing the caller's loyalty ledger with pagination
+func (a *LoyaltyAdaptor) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Get limit from config
+	limit := a.Config.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	entries, pagination, err := a.UseCase.GetHistory(r.Context(), userID, page, limit)
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get points history")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get points history", entries, pagination)
+}
@@ -1,27 +1,34 @@
 package adaptor
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"project-app-bioskop/internal/dto"
 	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/pkg/utils"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 )
 
 type MovieAdaptor struct {
-	UseCase usecase.MovieUseCaseInterface
-	Config  utils.Configuration
+	UseCase  usecase.MovieUseCaseInterface
+	Config   utils.Configuration
+	Validate *validator.Validate
 }
 
 func NewMovieAdaptor(useCase usecase.MovieUseCaseInterface, config utils.Configuration) *MovieAdaptor {
 	return &MovieAdaptor{
-		UseCase: useCase,
-		Config:  config,
+		UseCase:  useCase,
+		Config:   config,
+		Validate: validator.New(),
 	}
 }
 
-// GetAll handles get all movies with pagination
+// GetAll handles get movies with search, filters, sorting and pagination
 func (a *MovieAdaptor) GetAll(w http.ResponseWriter, r *http.Request) {
 	page, err := strconv.Atoi(r.URL.Query().Get("page"))
 	if err != nil || page < 1 {
@@ -34,7 +41,35 @@ func (a *MovieAdaptor) GetAll(w http.ResponseWriter, r *http.Request) {
 		limit = 10
 	}
 
-	movies, pagination, err := a.UseCase.GetAllMovies(r.Context(), page, limit)
+	query := r.URL.Query()
+	req := dto.MovieQueryRequest{
+		Search:        query.Get("search"),
+		ReleaseStatus: query.Get("release_status"),
+		Sort:          query.Get("sort"),
+	}
+	// Genres may be repeated or comma separated: ?genre=Action&genre=Sci-Fi or ?genre=Action,Sci-Fi
+	for _, value := range query["genre"] {
+		for _, genre := range strings.Split(value, ",") {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				req.Genres = append(req.Genres, genre)
+			}
+		}
+	}
+	if value := query.Get("min_rating"); value != "" {
+		minRating, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid min_rating", nil)
+			return
+		}
+		req.MinRating = minRating
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	movies, pagination, err := a.UseCase.GetAllMovies(r.Context(), req, page, limit)
 	if err != nil {
 		utils.ResponseInternalError(w, "failed to get movies")
 		return
@@ -53,10 +88,127 @@ func (a *MovieAdaptor) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	movie, err := a.UseCase.GetMovieByID(r.Context(), id)
-	if err != nil {
+	if errors.Is(err, usecase.ErrMovieNotFound) {
 		utils.ResponseNotFound(w, "movie not found")
 		return
 	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get movie")
+		return
+	}
 
 	utils.ResponseOK(w, "success get movie", movie)
 }
+
+// GetSchedule handles listing where and when a movie is playing
+func (a *MovieAdaptor) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "movieId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid movie id", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := dto.MovieScheduleRequest{
+		DateFrom: query.Get("date_from"),
+		DateTo:   query.Get("date_to"),
+		Location: query.Get("location"),
+	}
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	schedule, err := a.UseCase.GetMovieSchedule(r.Context(), id, req)
+	switch {
+	case errors.Is(err, usecase.ErrMovieNotFound):
+		utils.ResponseNotFound(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrInvalidDateRange):
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	case err != nil:
+		utils.ResponseInternalError(w, "failed to get movie schedule")
+		return
+	}
+
+	utils.ResponseOK(w, "success get movie schedule", schedule)
+}
+
+// Create handles adding a new movie
+func (a *MovieAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.MovieRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	movie, err := a.UseCase.CreateMovie(r.Context(), req)
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.ResponseCreated(w, "movie created successfully", movie)
+}
+
+// Update handles replacing the details of a movie
+func (a *MovieAdaptor) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "movieId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid movie id", nil)
+		return
+	}
+
+	var req dto.MovieRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	movie, err := a.UseCase.UpdateMovie(r.Context(), id, req)
+	if errors.Is(err, usecase.ErrMovieNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.ResponseOK(w, "movie updated successfully", movie)
+}
+
+// Delete handles soft deleting a movie
+func (a *MovieAdaptor) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "movieId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid movie id", nil)
+		return
+	}
+
+	err = a.UseCase.DeleteMovie(r.Context(), id)
+	switch {
+	case errors.Is(err, usecase.ErrMovieNotFound):
+		utils.ResponseNotFound(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrMovieHasShowtimes):
+		utils.ResponseConflict(w, err.Error())
+		return
+	case err != nil:
+		utils.ResponseInternalError(w, "failed to delete movie")
+		return
+	}
+
+	utils.ResponseOK(w, "movie deleted successfully", nil)
+}
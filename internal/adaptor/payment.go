@@ -2,14 +2,22 @@ package adaptor
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"project-app-bioskop/internal/dto"
 	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/gateway"
 	"project-app-bioskop/pkg/utils"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 )
 
+// maxWebhookBodySize bounds the callback body read before its signature is checked
+const maxWebhookBodySize = 1 << 20
+
 type PaymentAdaptor struct {
 	UseCase  usecase.PaymentUseCaseInterface
 	Validate *validator.Validate
@@ -53,10 +61,67 @@ func (a *PaymentAdaptor) ProcessPayment(w http.ResponseWriter, r *http.Request)
 	}
 
 	payment, err := a.UseCase.ProcessPayment(r.Context(), userID, req)
+	if errors.Is(err, usecase.ErrPaymentInProgress) {
+		utils.ResponseConflict(w, err.Error())
+		return
+	}
 	if err != nil {
 		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
 
-	utils.ResponseOK(w, "payment successful", payment)
+	switch payment.Status {
+	case gateway.StatusPending:
+		utils.ResponseSuccess(w, http.StatusAccepted, "payment is awaiting confirmation", payment)
+	case gateway.StatusFailed:
+		utils.ResponseError(w, http.StatusPaymentRequired, "payment failed", payment)
+	default:
+		utils.ResponseOK(w, "payment successful", payment)
+	}
+}
+
+// Webhook handles asynchronous payment callbacks from the provider of a payment method
+func (a *PaymentAdaptor) Webhook(w http.ResponseWriter, r *http.Request) {
+	methodID, err := strconv.Atoi(chi.URLParam(r, "methodId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid payment method id", nil)
+		return
+	}
+
+	// The signature covers the raw body, so keep it before decoding
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	var req dto.PaymentWebhookRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	result, err := a.UseCase.HandleWebhook(r.Context(), methodID, r.Header.Get(gateway.SignatureHeader), payload, req)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidWebhookSignature):
+		utils.ResponseUnauthorized(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrPaymentNotFound):
+		utils.ResponseNotFound(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrWebhookDuplicate):
+		// Acknowledge redeliveries so the provider stops retrying
+		utils.ResponseOK(w, err.Error(), nil)
+		return
+	case err != nil:
+		utils.ResponseInternalError(w, "failed to process webhook")
+		return
+	}
+
+	utils.ResponseOK(w, "webhook processed", result)
 }
@@ -0,0 +1,117 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type PricingAdaptor struct {
+	UseCase  usecase.PricingUseCaseInterface
+	Validate *validator.Validate
+}
+
+func NewPricingAdaptor(useCase usecase.PricingUseCaseInterface) *PricingAdaptor {
+	return &PricingAdaptor{
+		UseCase:  useCase,
+		Validate: validator.New(),
+	}
+}
+
+// GetAll handles listing pricing rules in the order they are applied
+func (a *PricingAdaptor) GetAll(w http.ResponseWriter, r *http.Request) {
+	rules, err := a.UseCase.GetPricingRules(r.Context())
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get pricing rules")
+		return
+	}
+
+	utils.ResponseOK(w, "success get pricing rules", rules)
+}
+
+// Create handles adding a pricing rule
+func (a *PricingAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	req, ok := a.decodePricingRule(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := a.UseCase.CreatePricingRule(r.Context(), req)
+	if err != nil {
+		writePricingError(w, err, "failed to create pricing rule")
+		return
+	}
+
+	utils.ResponseCreated(w, "pricing rule created successfully", rule)
+}
+
+// Update handles replacing a pricing rule
+func (a *PricingAdaptor) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "ruleId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid pricing rule id", nil)
+		return
+	}
+
+	req, ok := a.decodePricingRule(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := a.UseCase.UpdatePricingRule(r.Context(), id, req)
+	if err != nil {
+		writePricingError(w, err, "failed to update pricing rule")
+		return
+	}
+
+	utils.ResponseOK(w, "pricing rule updated successfully", rule)
+}
+
+// Delete handles removing a pricing rule
+func (a *PricingAdaptor) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "ruleId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid pricing rule id", nil)
+		return
+	}
+
+	if err := a.UseCase.DeletePricingRule(r.Context(), id); err != nil {
+		writePricingError(w, err, "failed to delete pricing rule")
+		return
+	}
+
+	utils.ResponseOK(w, "pricing rule deleted successfully", nil)
+}
+
+func (a *PricingAdaptor) decodePricingRule(w http.ResponseWriter, r *http.Request) (dto.PricingRuleRequest, bool) {
+	var req dto.PricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return req, false
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return req, false
+	}
+
+	return req, true
+}
+
+func writePricingError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrPricingRuleNotFound), errors.Is(err, usecase.ErrCinemaNotFound):
+		utils.ResponseNotFound(w, err.Error())
+	case errors.Is(err, usecase.ErrInvalidPricingRule):
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.ResponseInternalError(w, fallback)
+	}
+}
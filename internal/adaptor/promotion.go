@@ -0,0 +1,116 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type PromotionAdaptor struct {
+	UseCase  usecase.PromotionUseCaseInterface
+	Config   utils.Configuration
+	Validate *validator.Validate
+}
+
+func NewPromotionAdaptor(useCase usecase.PromotionUseCaseInterface, config utils.Configuration) *PromotionAdaptor {
+	return &PromotionAdaptor{
+		UseCase:  useCase,
+		Config:   config,
+		Validate: validator.New(),
+	}
+}
+
+// GetAll handles listing promo codes with pagination
+func (a *PromotionAdaptor) GetAll(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Get limit from config
+	limit := a.Config.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	promotions, pagination, err := a.UseCase.GetPromotions(r.Context(), page, limit)
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get promotions")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get promotions", promotions, pagination)
+}
+
+// Create handles adding a promo code
+func (a *PromotionAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	req, ok := a.decodePromotion(w, r)
+	if !ok {
+		return
+	}
+
+	promotion, err := a.UseCase.CreatePromotion(r.Context(), req)
+	if err != nil {
+		writePromotionError(w, err, "failed to create promotion")
+		return
+	}
+
+	utils.ResponseCreated(w, "promotion created successfully", promotion)
+}
+
+// Update handles changing a promo code's settings
+func (a *PromotionAdaptor) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "promotionId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid promotion id", nil)
+		return
+	}
+
+	req, ok := a.decodePromotion(w, r)
+	if !ok {
+		return
+	}
+
+	promotion, err := a.UseCase.UpdatePromotion(r.Context(), id, req)
+	if err != nil {
+		writePromotionError(w, err, "failed to update promotion")
+		return
+	}
+
+	utils.ResponseOK(w, "promotion updated successfully", promotion)
+}
+
+func (a *PromotionAdaptor) decodePromotion(w http.ResponseWriter, r *http.Request) (dto.PromotionRequest, bool) {
+	var req dto.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return req, false
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return req, false
+	}
+
+	return req, true
+}
+
+func writePromotionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrPromoNotFound):
+		utils.ResponseNotFound(w, err.Error())
+	case errors.Is(err, usecase.ErrPromoCodeExists):
+		utils.ResponseConflict(w, err.Error())
+	case errors.Is(err, usecase.ErrInvalidPromotion):
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.ResponseInternalError(w, fallback)
+	}
+}
@@ -0,0 +1,168 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ReviewAdaptor struct {
+	UseCase  usecase.ReviewUseCaseInterface
+	Config   utils.Configuration
+	Validate *validator.Validate
+}
+
+func NewReviewAdaptor(useCase usecase.ReviewUseCaseInterface, config utils.Configuration) *ReviewAdaptor {
+	return &ReviewAdaptor{
+		UseCase:  useCase,
+		Config:   config,
+		Validate: validator.New(),
+	}
+}
+
+// GetByMovie handles listing the reviews of a movie with pagination
+func (a *ReviewAdaptor) GetByMovie(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid movie id", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Get limit from config
+	limit := a.Config.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	reviews, pagination, err := a.UseCase.GetMovieReviews(r.Context(), movieID, page, limit)
+	if errors.Is(err, usecase.ErrMovieNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get reviews")
+		return
+	}
+
+	utils.ResponsePagination(w, http.StatusOK, "success get reviews", reviews, pagination)
+}
+
+// Create handles posting a review of a watched movie
+func (a *ReviewAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	movieID, err := strconv.Atoi(chi.URLParam(r, "movieId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid movie id", nil)
+		return
+	}
+
+	req, ok := a.decodeReview(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := a.UseCase.CreateReview(r.Context(), userID, movieID, req)
+	if err != nil {
+		writeReviewError(w, err, "failed to create review")
+		return
+	}
+
+	utils.ResponseCreated(w, "review created successfully", review)
+}
+
+// Update handles editing the caller's own review
+func (a *ReviewAdaptor) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "reviewId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid review id", nil)
+		return
+	}
+
+	req, ok := a.decodeReview(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := a.UseCase.UpdateReview(r.Context(), userID, reviewID, req)
+	if err != nil {
+		writeReviewError(w, err, "failed to update review")
+		return
+	}
+
+	utils.ResponseOK(w, "review updated successfully", review)
+}
+
+// Delete handles removing the caller's own review
+func (a *ReviewAdaptor) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "reviewId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid review id", nil)
+		return
+	}
+
+	if err := a.UseCase.DeleteReview(r.Context(), userID, reviewID); err != nil {
+		writeReviewError(w, err, "failed to delete review")
+		return
+	}
+
+	utils.ResponseOK(w, "review deleted successfully", nil)
+}
+
+// decodeReview reads and validates a review body, writing the error response itself
+func (a *ReviewAdaptor) decodeReview(w http.ResponseWriter, r *http.Request) (dto.ReviewRequest, bool) {
+	var req dto.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return req, false
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return req, false
+	}
+
+	return req, true
+}
+
+// writeReviewError maps review usecase errors to HTTP responses
+func writeReviewError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrMovieNotFound), errors.Is(err, usecase.ErrReviewNotFound):
+		utils.ResponseNotFound(w, err.Error())
+	case errors.Is(err, usecase.ErrReviewNotOwned), errors.Is(err, usecase.ErrReviewNotAllowed):
+		utils.ResponseForbidden(w, err.Error())
+	case errors.Is(err, usecase.ErrReviewExists):
+		utils.ResponseConflict(w, err.Error())
+	default:
+		utils.ResponseInternalError(w, fallback)
+	}
+}
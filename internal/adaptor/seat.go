@@ -1,16 +1,23 @@
 package adaptor
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"project-app-bioskop/internal/dto"
 	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/pkg/utils"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 )
 
+// streamHeartbeat keeps idle seat streams from being closed by proxies
+const streamHeartbeat = 15 * time.Second
+
 type SeatAdaptor struct {
 	UseCase  usecase.SeatUseCaseInterface
 	Validate *validator.Validate
@@ -23,7 +30,7 @@ func NewSeatAdaptor(useCase usecase.SeatUseCaseInterface) *SeatAdaptor {
 	}
 }
 
-// GetAvailability handles get seat availability for a cinema showtime
+// GetAvailability handles get the seat map of a cinema showtime
 func (a *SeatAdaptor) GetAvailability(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "cinemaId")
 	cinemaID, err := strconv.Atoi(idStr)
@@ -44,13 +51,34 @@ func (a *SeatAdaptor) GetAvailability(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	seats, err := a.UseCase.GetSeatAvailability(r.Context(), cinemaID, req.Date, req.Time)
-	if err != nil {
+	seatMap, err := a.UseCase.GetSeatAvailability(r.Context(), cinemaID, req.Date, req.Time)
+	if errors.Is(err, usecase.ErrShowtimeNotFound) {
 		utils.ResponseNotFound(w, "no showtime found for given parameters")
 		return
 	}
+	if err != nil {
+		writeShowtimeError(w, err, "failed to get seat availability")
+		return
+	}
+
+	utils.ResponseOK(w, "success get seat availability", seatMap)
+}
+
+// GetSeatMap handles get the seat map of a showtime
+func (a *SeatAdaptor) GetSeatMap(w http.ResponseWriter, r *http.Request) {
+	showtimeID, err := strconv.Atoi(chi.URLParam(r, "showtimeId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid showtime id", nil)
+		return
+	}
+
+	seatMap, err := a.UseCase.GetSeatMap(r.Context(), showtimeID)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to get seat map")
+		return
+	}
 
-	utils.ResponseOK(w, "success get seat availability", seats)
+	utils.ResponseOK(w, "success get seat map", seatMap)
 }
 
 // GetShowtimes handles get all showtimes for a cinema
@@ -70,3 +98,98 @@ func (a *SeatAdaptor) GetShowtimes(w http.ResponseWriter, r *http.Request) {
 
 	utils.ResponseOK(w, "success get showtimes", showtimes)
 }
+
+// SuggestSeats handles get the best available seats of a showtime for a group
+func (a *SeatAdaptor) SuggestSeats(w http.ResponseWriter, r *http.Request) {
+	showtimeID, err := strconv.Atoi(chi.URLParam(r, "showtimeId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid showtime id", nil)
+		return
+	}
+
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid seat count", nil)
+		return
+	}
+
+	req := dto.BestSeatsQueryRequest{Count: count}
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	seats, err := a.UseCase.SuggestSeats(r.Context(), showtimeID, req.Count)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to suggest seats")
+		return
+	}
+
+	utils.ResponseOK(w, "success get best available seats", seats)
+}
+
+// StreamSeats handles a Server-Sent Events stream of a showtime's seat map.
+// It sends the current map as a "snapshot" event, then a "seat" event per change.
+// The stream ends when the client disconnects or falls too far behind.
+func (a *SeatAdaptor) StreamSeats(w http.ResponseWriter, r *http.Request) {
+	showtimeID, err := strconv.Atoi(chi.URLParam(r, "showtimeId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid showtime id", nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.ResponseInternalError(w, "streaming not supported")
+		return
+	}
+
+	seatMap, sub, err := a.UseCase.StreamSeats(r.Context(), showtimeID)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to get seat map")
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeEvent(w, "snapshot", seatMap); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-sub.C:
+			if !open {
+				return
+			}
+			if err := writeEvent(w, "seat", event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes one Server-Sent Event with a JSON payload
+func writeEvent(w http.ResponseWriter, name string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload)
+	return err
+}
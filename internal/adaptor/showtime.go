@@ -0,0 +1,171 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ShowtimeAdaptor struct {
+	UseCase  usecase.ShowtimeUseCaseInterface
+	Validate *validator.Validate
+}
+
+func NewShowtimeAdaptor(useCase usecase.ShowtimeUseCaseInterface) *ShowtimeAdaptor {
+	return &ShowtimeAdaptor{
+		UseCase:  useCase,
+		Validate: validator.New(),
+	}
+}
+
+// Create handles scheduling a new showtime
+func (a *ShowtimeAdaptor) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.ShowtimeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	showtime, err := a.UseCase.CreateShowtime(r.Context(), req)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to create showtime")
+		return
+	}
+
+	utils.ResponseCreated(w, "showtime created successfully", showtime)
+}
+
+// Reschedule handles moving a showtime to a new date and time
+func (a *ShowtimeAdaptor) Reschedule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "showtimeId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid showtime id", nil)
+		return
+	}
+
+	var req dto.RescheduleShowtimeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	showtime, err := a.UseCase.RescheduleShowtime(r.Context(), id, req)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to reschedule showtime")
+		return
+	}
+
+	utils.ResponseOK(w, "showtime rescheduled successfully", showtime)
+}
+
+// Cancel handles cancelling a showtime together with its bookings
+func (a *ShowtimeAdaptor) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "showtimeId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid showtime id", nil)
+		return
+	}
+
+	// Body is optional
+	var req dto.CancelShowtimeRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+			return
+		}
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	result, err := a.UseCase.CancelShowtime(r.Context(), id, req)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to cancel showtime")
+		return
+	}
+
+	utils.ResponseOK(w, "showtime cancelled successfully", result)
+}
+
+// Search handles listing upcoming showtimes across cinemas and studios
+func (a *ShowtimeAdaptor) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.ShowtimeSearchRequest{
+		Date:     query.Get("date"),
+		Time:     query.Get("time"),
+		TimeFrom: query.Get("time_from"),
+		TimeTo:   query.Get("time_to"),
+	}
+
+	for _, param := range []struct {
+		name string
+		dest *int
+	}{
+		{"cinema_id", &req.CinemaID},
+		{"movie_id", &req.MovieID},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid "+param.name, nil)
+			return
+		}
+		*param.dest = id
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	showtimes, err := a.UseCase.SearchShowtimes(r.Context(), req)
+	if err != nil {
+		writeShowtimeError(w, err, "failed to search showtimes")
+		return
+	}
+
+	utils.ResponseOK(w, "success search showtimes", showtimes)
+}
+
+// writeShowtimeError maps scheduling errors to HTTP responses
+func writeShowtimeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrShowtimeNotFound),
+		errors.Is(err, usecase.ErrMovieNotFound),
+		errors.Is(err, usecase.ErrStudioNotFound):
+		utils.ResponseNotFound(w, err.Error())
+	case errors.Is(err, usecase.ErrShowtimeConflict),
+		errors.Is(err, usecase.ErrShowtimeCancelled),
+		errors.Is(err, usecase.ErrAmbiguousShowtime),
+		errors.Is(err, usecase.ErrNotEnoughSeats):
+		utils.ResponseConflict(w, err.Error())
+	case errors.Is(err, usecase.ErrShowtimeInPast),
+		errors.Is(err, usecase.ErrInvalidShowDate),
+		errors.Is(err, usecase.ErrInvalidShowTime):
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.ResponseInternalError(w, fallback)
+	}
+}
@@ -0,0 +1,121 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type TicketAdaptor struct {
+	UseCase  usecase.TicketUseCaseInterface
+	Validate *validator.Validate
+}
+
+func NewTicketAdaptor(useCase usecase.TicketUseCaseInterface) *TicketAdaptor {
+	return &TicketAdaptor{
+		UseCase:  useCase,
+		Validate: validator.New(),
+	}
+}
+
+// GetByBooking handles listing the e-tickets of the caller's paid booking
+func (a *TicketAdaptor) GetByBooking(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	bookingID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid booking id", nil)
+		return
+	}
+
+	tickets, err := a.UseCase.GetTickets(r.Context(), userID, bookingID)
+	if err != nil {
+		writeTicketError(w, err, "failed to get tickets")
+		return
+	}
+
+	utils.ResponseOK(w, "success get tickets", tickets)
+}
+
+// GetQR handles serving one of the caller's tickets as a QR code PNG
+func (a *TicketAdaptor) GetQR(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	ticketID, err := strconv.Atoi(chi.URLParam(r, "ticketId"))
+	if err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid ticket id", nil)
+		return
+	}
+
+	png, err := a.UseCase.GetTicketQR(r.Context(), userID, ticketID)
+	if err != nil {
+		writeTicketError(w, err, "failed to generate ticket qr code")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// Scan handles admitting a ticket at the door, for cinema staff
+func (a *TicketAdaptor) Scan(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	var req dto.TicketScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	result, err := a.UseCase.ScanTicket(r.Context(), staffID, req)
+	if err != nil {
+		writeTicketError(w, err, "failed to scan ticket")
+		return
+	}
+
+	utils.ResponseOK(w, "ticket admitted", result)
+}
+
+func writeTicketError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrTicketsDisabled):
+		utils.ResponseError(w, http.StatusServiceUnavailable, err.Error(), nil)
+	case errors.Is(err, usecase.ErrBookingNotFound), errors.Is(err, usecase.ErrTicketNotFound):
+		utils.ResponseNotFound(w, err.Error())
+	case errors.Is(err, usecase.ErrBookingNotOwned):
+		utils.ResponseForbidden(w, err.Error())
+	case errors.Is(err, usecase.ErrInvalidTicket):
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, usecase.ErrTicketsNotIssued), errors.Is(err, usecase.ErrTicketVoid),
+		errors.Is(err, usecase.ErrTicketOutsideEntryWindow), errors.Is(err, usecase.ErrTicketAlreadyAdmitted):
+		utils.ResponseConflict(w, err.Error())
+	default:
+		utils.ResponseInternalError(w, fallback)
+	}
+}
@@ -0,0 +1,80 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type UserAdaptor struct {
+	UseCase  usecase.UserUseCaseInterface
+	Validate *validator.Validate
+}
+
+func NewUserAdaptor(useCase usecase.UserUseCaseInterface) *UserAdaptor {
+	return &UserAdaptor{
+		UseCase:  useCase,
+		Validate: validator.New(),
+	}
+}
+
+// GetProfile handles get the caller's profile
+func (a *UserAdaptor) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	profile, err := a.UseCase.GetUserProfile(r.Context(), userID)
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		utils.ResponseNotFound(w, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ResponseInternalError(w, "failed to get profile")
+		return
+	}
+
+	utils.ResponseOK(w, "success get profile", profile)
+}
+
+// UpdateProfile handles setting the caller's date of birth
+func (a *UserAdaptor) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int)
+	if !ok {
+		utils.ResponseUnauthorized(w, "unauthorized")
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := a.Validate.Struct(req); err != nil {
+		utils.ResponseValidationError(w, err.Error())
+		return
+	}
+
+	profile, err := a.UseCase.UpdateProfile(r.Context(), userID, req)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBirthDate):
+		utils.ResponseBadRequest(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	case errors.Is(err, usecase.ErrUserNotFound):
+		utils.ResponseNotFound(w, err.Error())
+		return
+	case err != nil:
+		utils.ResponseInternalError(w, "failed to update profile")
+		return
+	}
+
+	utils.ResponseOK(w, "profile updated successfully", profile)
+}
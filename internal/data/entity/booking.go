@@ -4,12 +4,31 @@ import "time"
 
 // Booking represents a ticket booking
 type Booking struct {
-	ID          int       `json:"id"`
-	UserID      int       `json:"user_id"`
-	ShowtimeID  int       `json:"showtime_id"`
-	Status      string    `json:"status"`
-	TotalAmount float64   `json:"total_amount"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	ShowtimeID  int        `json:"showtime_id"`
+	Status      string     `json:"status"`
+	TotalAmount float64    `json:"total_amount"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Showtime price the seats were priced from, after pricing rules and before seat
+	// categories. It is resolved when the booking is created.
+	ShowtimePrice float64 `json:"showtime_price"`
+
+	// Promotion redeemed by the booking; TotalAmount is already discounted
+	PromotionID    *int    `json:"promotion_id,omitempty"`
+	PromoCode      string  `json:"promo_code,omitempty"`
+	DiscountAmount float64 `json:"discount_amount"`
+
+	// Loyalty points redeemed on the booking and the amount they took off. When creating
+	// a booking these carry the points requested and what they are worth in full.
+	PointsRedeemed int     `json:"points_redeemed"`
+	PointsDiscount float64 `json:"points_discount"`
+
+	// Concessions ordered with the booking. When creating a booking only the
+	// concession and quantity of each line are read, prices come from the catalogue.
+	Concessions []BookingConcession `json:"concessions,omitempty"`
 }
 
 // BookingSeat represents a booked seat in a booking
@@ -22,8 +41,20 @@ type BookingSeat struct {
 
 // PaymentMethod represents available payment methods
 type PaymentMethod struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Gateway       string `json:"gateway"`
+	WebhookSecret string `json:"-"`
+}
+
+// PaymentWebhookEvent represents a verified provider callback for a payment transaction
+type PaymentWebhookEvent struct {
+	ID              int       `json:"id"`
+	PaymentMethodID int       `json:"payment_method_id"`
+	TransactionID   string    `json:"transaction_id"`
+	Status          string    `json:"status"`
+	Payload         string    `json:"payload"`
+	ReceivedAt      time.Time `json:"received_at"`
 }
 
 // Payment represents a payment record
@@ -32,6 +63,19 @@ type Payment struct {
 	BookingID       int        `json:"booking_id"`
 	PaymentMethodID int        `json:"payment_method_id"`
 	Status          string     `json:"status"`
+	TransactionID   string     `json:"transaction_id"`
 	PaymentDetails  string     `json:"payment_details"`
 	PaidAt          *time.Time `json:"paid_at"`
 }
+
+// Refund represents a refund issued for a cancelled paid booking
+type Refund struct {
+	ID        int       `json:"id"`
+	PaymentID int       `json:"payment_id"`
+	BookingID int       `json:"booking_id"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -20,17 +20,42 @@ type Studio struct {
 
 // Seat represents a seat inside a studio
 type Seat struct {
-	ID       int    `json:"id"`
-	StudioID int    `json:"studio_id"`
-	SeatCode string `json:"seat_code"`
+	ID           int    `json:"id"`
+	StudioID     int    `json:"studio_id"`
+	SeatCode     string `json:"seat_code"`
+	RowLabel     string `json:"row_label"`
+	ColumnNumber int    `json:"column_number"`
+	Category     string `json:"category"`
 }
 
-// SeatAvailability represents seat status for a showtime
+// SeatCategoryRegular is the category of seats without a surcharge
+const SeatCategoryRegular = "regular"
+
+// SeatCategory prices a seat as a multiple of the showtime price
+type SeatCategory struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	PriceMultiplier float64 `json:"price_multiplier"`
+}
+
+// Seat states for a showtime: held seats belong to a pending booking, booked seats to a paid one
+const (
+	SeatAvailable = "available"
+	SeatHeld      = "held"
+	SeatBooked    = "booked"
+)
+
+// SeatAvailability represents seat position, status and effective price for a showtime
 type SeatAvailability struct {
-	ID       int    `json:"id"`
-	SeatCode string `json:"seat_code"`
-	StudioID int    `json:"studio_id"`
-	IsBooked bool   `json:"is_booked"`
+	ID           int     `json:"id"`
+	SeatCode     string  `json:"seat_code"`
+	StudioID     int     `json:"studio_id"`
+	RowLabel     string  `json:"row_label"`
+	ColumnNumber int     `json:"column_number"`
+	Category     string  `json:"category"`
+	Price        float64 `json:"price"`
+	State        string  `json:"state"`
+	IsBooked     bool    `json:"is_booked"`
 }
 
 // Movie represents a movie entity
@@ -44,10 +69,78 @@ type Movie struct {
 	ReleaseDate     time.Time `json:"release_date"`
 	DurationMinutes int       `json:"duration_minutes"`
 	ReleaseStatus   string    `json:"release_status"`
+	Synopsis        string    `json:"synopsis"`
+	AgeRating       string    `json:"age_rating"`
+	Language        string    `json:"language"`
+	TrailerURL      string    `json:"trailer_url"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Credits are only loaded for the movie detail and written with the movie
+	Cast []MovieCastMember `json:"cast,omitempty"`
+	Crew []MovieCrewMember `json:"crew,omitempty"`
+}
+
+// Age ratings of the Indonesian film classification
+const (
+	AgeRatingAll = "SU"
+	AgeRating13  = "13+"
+	AgeRating17  = "17+"
+	AgeRating21  = "21+"
+)
+
+// AgeRatingMinimums holds the minimum viewer age of each restricted classification
+var AgeRatingMinimums = map[string]int{
+	AgeRating13: 13,
+	AgeRating17: 17,
+	AgeRating21: 21,
+}
+
+// CrewJobDirector is the crew job shown as the movie's director
+const CrewJobDirector = "Director"
+
+// MovieCastMember is an actor in a movie, ordered by billing
+type MovieCastMember struct {
+	ID            int    `json:"id"`
+	MovieID       int    `json:"movie_id"`
+	Name          string `json:"name"`
+	CharacterName string `json:"character_name"`
+	BillingOrder  int    `json:"billing_order"`
 }
 
+// MovieCrewMember is a person who worked on a movie in a job such as Director
+type MovieCrewMember struct {
+	ID      int    `json:"id"`
+	MovieID int    `json:"movie_id"`
+	Name    string `json:"name"`
+	Job     string `json:"job"`
+}
+
+// Movie list sort orders
+const (
+	MovieSortNewest      = "newest"
+	MovieSortReleaseDate = "release_date"
+	MovieSortRating      = "rating"
+	MovieSortPopular     = "popular"
+	MovieSortTitle       = "title"
+)
+
+// MovieFilter narrows and orders the movie list. Zero values are ignored; a movie
+// must have every listed genre. An empty Sort lists the newest movies first.
+type MovieFilter struct {
+	Search        string
+	Genres        []string
+	ReleaseStatus string
+	MinRating     float64
+	Sort          string
+}
+
+// Showtime statuses
+const (
+	ShowtimeScheduled = "scheduled"
+	ShowtimeCancelled = "cancelled"
+)
+
 // Showtime represents a movie showtime
 type Showtime struct {
 	ID       int     `json:"id"`
@@ -57,6 +150,33 @@ type Showtime struct {
 	ShowDate string  `json:"show_date"`
 	ShowTime string  `json:"show_time"`
 	Price    float64 `json:"price"`
+	Status   string  `json:"status"`
 	Movie    *Movie  `json:"movie,omitempty"`
 	Studio   *Studio `json:"studio,omitempty"`
 }
+
+// MovieScheduleFilter narrows a movie's upcoming showtimes. Dates are YYYY-MM-DD and
+// Location matches part of the cinema location, ignoring case. Zero values are ignored.
+type MovieScheduleFilter struct {
+	DateFrom string
+	DateTo   string
+	Location string
+}
+
+// ShowtimeListing is a showtime with its cinema and the seats still open for booking
+type ShowtimeListing struct {
+	Showtime       Showtime
+	Cinema         Cinema
+	RemainingSeats int
+}
+
+// ShowtimeFilter narrows a showtime search. Zero values are ignored; dates are
+// YYYY-MM-DD and times HH:MM:SS. Without a date only upcoming days are searched.
+type ShowtimeFilter struct {
+	CinemaID int
+	MovieID  int
+	ShowDate string
+	ShowTime string
+	TimeFrom string
+	TimeTo   string
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Concession categories
+const (
+	ConcessionPopcorn = "popcorn"
+	ConcessionDrink   = "drink"
+	ConcessionSnack   = "snack"
+	ConcessionCombo   = "combo"
+)
+
+// Concession is a food or beverage item sold by a cinema, Stock is what is left to sell
+type Concession struct {
+	ID          int       `json:"id"`
+	CinemaID    int       `json:"cinema_id"`
+	Name        string    `json:"name"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	Stock       int       `json:"stock"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BookingConcession represents a concession line item in a booking
+type BookingConcession struct {
+	ID            int     `json:"id"`
+	BookingID     int     `json:"booking_id"`
+	ConcessionID  int     `json:"concession_id"`
+	Name          string  `json:"name"`
+	Quantity      int     `json:"quantity"`
+	PriceSnapshot float64 `json:"price_snapshot"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// IdempotencyKey represents a client supplied Idempotency-Key and the response stored for it
+type IdempotencyKey struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"user_id"`
+	Key          string     `json:"key"`
+	RequestHash  string     `json:"request_hash"`
+	StatusCode   int        `json:"status_code"`
+	ResponseBody []byte     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Loyalty ledger entry types
+const (
+	LoyaltyEarn     = "earn"
+	LoyaltyRedeem   = "redeem"
+	LoyaltyReversal = "reversal"
+	LoyaltyDebt     = "debt"
+)
+
+// LoyaltyEntry is one movement in a user's loyalty points ledger. Points is the signed
+// change; for earned lots Remaining is what is still spendable until ExpiresAt, and
+// redemptions and reversals point at the lot they moved points from in LotID. For debts
+// Remaining is what is still owed.
+type LoyaltyEntry struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	BookingID *int       `json:"booking_id"`
+	EntryType string     `json:"entry_type"`
+	Points    int        `json:"points"`
+	Remaining int        `json:"remaining"`
+	LotID     *int       `json:"lot_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Pricing rule adjustment types
+const (
+	AdjustmentPercentage = "percentage"
+	AdjustmentFixed      = "fixed"
+)
+
+// PricingRule adjusts a showtime's base price. Each condition is optional; a rule
+// matches when all of the conditions it sets hold. Dates are YYYY-MM-DD and times
+// HH:MM:SS. A negative AdjustmentValue is a discount.
+type PricingRule struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	CinemaID        *int      `json:"cinema_id"`
+	DaysOfWeek      []int     `json:"days_of_week"`
+	Dates           []string  `json:"dates"`
+	TimeFrom        string    `json:"time_from"`
+	TimeUntil       string    `json:"time_until"`
+	MinOccupancy    float64   `json:"min_occupancy"`
+	AdjustmentType  string    `json:"adjustment_type"`
+	AdjustmentValue float64   `json:"adjustment_value"`
+	Priority        int       `json:"priority"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PricingContext describes the showtime a price is resolved for. Occupancy is the
+// percentage of the studio's seats already held or sold.
+type PricingContext struct {
+	CinemaID  int
+	ShowDate  string
+	ShowTime  string
+	Occupancy float64
+}
+
+// Matches reports whether the rule applies to the showtime. A rule with both weekdays
+// and dates matches a show on either, so one rule can cover weekends and holidays.
+func (r PricingRule) Matches(c PricingContext) bool {
+	if r.CinemaID != nil && *r.CinemaID != c.CinemaID {
+		return false
+	}
+	if len(r.DaysOfWeek) > 0 || len(r.Dates) > 0 {
+		showDate, err := time.Parse("2006-01-02", c.ShowDate)
+		if err != nil {
+			return false
+		}
+		if !slices.Contains(r.DaysOfWeek, int(showDate.Weekday())) && !slices.Contains(r.Dates, c.ShowDate) {
+			return false
+		}
+	}
+	if r.TimeFrom != "" && c.ShowTime < r.TimeFrom {
+		return false
+	}
+	if r.TimeUntil != "" && c.ShowTime >= r.TimeUntil {
+		return false
+	}
+	return c.Occupancy >= r.MinOccupancy
+}
+
+// ResolvePrice applies the matching rules, in the order given, to a base price.
+// The result is rounded to cents and never negative.
+func ResolvePrice(base float64, rules []PricingRule, c PricingContext) float64 {
+	price := base
+	for _, r := range rules {
+		if !r.Matches(c) {
+			continue
+		}
+		if r.AdjustmentType == AdjustmentPercentage {
+			price += price * r.AdjustmentValue / 100
+		} else {
+			price += r.AdjustmentValue
+		}
+	}
+	return max(math.Round(price*100)/100, 0)
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Promotion discount types
+const (
+	PromotionPercentage = "percentage"
+	PromotionFixed      = "fixed"
+)
+
+// Promotion is a promo code that discounts a booking. Zero-valued limits and nil
+// restrictions mean the promotion is not limited in that way.
+type Promotion struct {
+	ID              int       `json:"id"`
+	Code            string    `json:"code"`
+	Description     string    `json:"description"`
+	DiscountType    string    `json:"discount_type"`
+	DiscountValue   float64   `json:"discount_value"`
+	MaxDiscount     float64   `json:"max_discount"`
+	MovieID         *int      `json:"movie_id"`
+	CinemaID        *int      `json:"cinema_id"`
+	PaymentMethodID *int      `json:"payment_method_id"`
+	DaysOfWeek      []int     `json:"days_of_week"`
+	MinSeats        int       `json:"min_seats"`
+	UsageLimit      int       `json:"usage_limit"`
+	PerUserLimit    int       `json:"per_user_limit"`
+	UsedCount       int       `json:"used_count"`
+	ValidFrom       time.Time `json:"valid_from"`
+	ValidUntil      time.Time `json:"valid_until"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Discount returns the amount the promotion takes off a subtotal, rounded to cents
+// and never more than the subtotal itself
+func (p Promotion) Discount(subtotal float64) float64 {
+	discount := p.DiscountValue
+	if p.DiscountType == PromotionPercentage {
+		discount = math.Round(subtotal*p.DiscountValue) / 100
+		if p.MaxDiscount > 0 {
+			discount = min(discount, p.MaxDiscount)
+		}
+	}
+	return min(discount, subtotal)
+}
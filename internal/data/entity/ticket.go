@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Ticket is a booked seat as presented at the door, with the showtime it admits to
+type Ticket struct {
+	ID            int        `json:"id"`
+	BookingID     int        `json:"booking_id"`
+	UserID        int        `json:"user_id"`
+	BookingStatus string     `json:"booking_status"`
+	SeatID        int        `json:"seat_id"`
+	SeatCode      string     `json:"seat_code"`
+	AdmittedAt    *time.Time `json:"admitted_at"`
+	Showtime      Showtime   `json:"showtime"`
+}
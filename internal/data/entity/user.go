@@ -2,13 +2,22 @@ package entity
 
 import "time"
 
-// User represents a registered customer
+// User roles
+const (
+	RoleCustomer    = "customer"
+	RoleCinemaStaff = "cinema_staff"
+	RoleAdmin       = "admin"
+)
+
+// User represents a registered customer, cinema staff member or admin
 type User struct {
-	ID           int       `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	IsVerified   bool      `json:"is_verified"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	IsVerified   bool       `json:"is_verified"`
+	Role         string     `json:"role"`
+	DateOfBirth  *time.Time `json:"date_of_birth,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
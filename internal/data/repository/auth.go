@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"project-app-bioskop/internal/data/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type AuthRepoInterface interface {
@@ -11,6 +14,8 @@ type AuthRepoInterface interface {
 	GetUserByEmail(ctx context.Context, email string) (entity.User, error)
 	GetUserByID(ctx context.Context, id int) (entity.User, error)
 	UpdateUserVerified(ctx context.Context, userID int) error
+	UpdateUserRole(ctx context.Context, userID int, role string) error
+	UpdateUserBirthDate(ctx context.Context, userID int, dateOfBirth time.Time) error
 	CreateSession(ctx context.Context, session entity.Session) error
 	GetSessionByToken(ctx context.Context, token string) (entity.Session, error)
 	RevokeSession(ctx context.Context, token string) error
@@ -31,9 +36,9 @@ func NewAuthRepo(db DBPool) AuthRepoInterface {
 
 // CreateUser inserts a new user into database
 func (r *AuthRepo) CreateUser(ctx context.Context, user entity.User) (int, error) {
-	query := `INSERT INTO users (username, email, password_hash) VALUES ($1, $2, $3) RETURNING id`
+	query := `INSERT INTO users (username, email, password_hash, date_of_birth) VALUES ($1, $2, $3, $4) RETURNING id`
 	var id int
-	err := r.DB.QueryRow(ctx, query, user.Username, user.Email, user.PasswordHash).Scan(&id)
+	err := r.DB.QueryRow(ctx, query, user.Username, user.Email, user.PasswordHash, user.DateOfBirth).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -42,12 +47,12 @@ func (r *AuthRepo) CreateUser(ctx context.Context, user entity.User) (int, error
 
 // GetUserByUsername retrieves user by username
 func (r *AuthRepo) GetUserByUsername(ctx context.Context, username string) (entity.User, error) {
-	query := `SELECT id, username, email, password_hash, is_verified, created_at, updated_at 
+	query := `SELECT id, username, email, password_hash, is_verified, role, date_of_birth, created_at, updated_at 
 			  FROM users WHERE username = $1`
 	var user entity.User
 	err := r.DB.QueryRow(ctx, query, username).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
-		&user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsVerified, &user.Role, &user.DateOfBirth, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return user, err
@@ -57,12 +62,12 @@ func (r *AuthRepo) GetUserByUsername(ctx context.Context, username string) (enti
 
 // GetUserByID retrieves user by ID
 func (r *AuthRepo) GetUserByID(ctx context.Context, id int) (entity.User, error) {
-	query := `SELECT id, username, email, password_hash, is_verified, created_at, updated_at 
+	query := `SELECT id, username, email, password_hash, is_verified, role, date_of_birth, created_at, updated_at 
 			  FROM users WHERE id = $1`
 	var user entity.User
 	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
-		&user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsVerified, &user.Role, &user.DateOfBirth, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return user, err
@@ -101,12 +106,12 @@ func (r *AuthRepo) RevokeSession(ctx context.Context, token string) error {
 
 // GetUserByEmail retrieves user by email
 func (r *AuthRepo) GetUserByEmail(ctx context.Context, email string) (entity.User, error) {
-	query := `SELECT id, username, email, password_hash, is_verified, created_at, updated_at 
+	query := `SELECT id, username, email, password_hash, is_verified, role, date_of_birth, created_at, updated_at 
 			  FROM users WHERE email = $1`
 	var user entity.User
 	err := r.DB.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash,
-		&user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsVerified, &user.Role, &user.DateOfBirth, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return user, err
@@ -121,6 +126,32 @@ func (r *AuthRepo) UpdateUserVerified(ctx context.Context, userID int) error {
 	return err
 }
 
+// UpdateUserRole changes the access role of a user
+func (r *AuthRepo) UpdateUserRole(ctx context.Context, userID int, role string) error {
+	query := `UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2`
+	tag, err := r.DB.Exec(ctx, query, role, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateUserBirthDate sets the date of birth used for age-restricted bookings
+func (r *AuthRepo) UpdateUserBirthDate(ctx context.Context, userID int, dateOfBirth time.Time) error {
+	query := `UPDATE users SET date_of_birth = $1, updated_at = NOW() WHERE id = $2`
+	tag, err := r.DB.Exec(ctx, query, dateOfBirth, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
 // CreateOTP creates a new OTP record
 func (r *AuthRepo) CreateOTP(ctx context.Context, otp entity.OTP) error {
 	query := `INSERT INTO otps (user_id, otp_code, expired_at) VALUES ($1, $2, $3)`
@@ -31,7 +31,7 @@ func TestAuthRepo_CreateUser(t *testing.T) {
 		rows := pgxmock.NewRows([]string{"id"}).AddRow(1)
 
 		mock.ExpectQuery("INSERT INTO users").
-			WithArgs(user.Username, user.Email, user.PasswordHash).
+			WithArgs(user.Username, user.Email, user.PasswordHash, user.DateOfBirth).
 			WillReturnRows(rows)
 
 		id, err := repo.CreateUser(context.Background(), user)
@@ -42,7 +42,7 @@ func TestAuthRepo_CreateUser(t *testing.T) {
 
 	t.Run("Error - Database Error", func(t *testing.T) {
 		mock.ExpectQuery("INSERT INTO users").
-			WithArgs(user.Username, user.Email, user.PasswordHash).
+			WithArgs(user.Username, user.Email, user.PasswordHash, user.DateOfBirth).
 			WillReturnError(errors.New("database error"))
 
 		id, err := repo.CreateUser(context.Background(), user)
@@ -65,8 +65,8 @@ func TestAuthRepo_GetUserByUsername(t *testing.T) {
 	t.Run("Success - User Found", func(t *testing.T) {
 		now := time.Now()
 		rows := pgxmock.NewRows([]string{
-			"id", "username", "email", "password_hash", "is_verified", "created_at", "updated_at",
-		}).AddRow(1, "testuser", "test@example.com", "hashedpwd", true, now, now)
+			"id", "username", "email", "password_hash", "is_verified", "role", "date_of_birth", "created_at", "updated_at",
+		}).AddRow(1, "testuser", "test@example.com", "hashedpwd", true, "customer", nil, now, now)
 
 		mock.ExpectQuery("SELECT (.+) FROM users WHERE username").
 			WithArgs("testuser").
@@ -103,8 +103,8 @@ func TestAuthRepo_GetUserByEmail(t *testing.T) {
 	t.Run("Success - User Found", func(t *testing.T) {
 		now := time.Now()
 		rows := pgxmock.NewRows([]string{
-			"id", "username", "email", "password_hash", "is_verified", "created_at", "updated_at",
-		}).AddRow(1, "testuser", "test@example.com", "hashedpwd", true, now, now)
+			"id", "username", "email", "password_hash", "is_verified", "role", "date_of_birth", "created_at", "updated_at",
+		}).AddRow(1, "testuser", "test@example.com", "hashedpwd", true, "customer", nil, now, now)
 
 		mock.ExpectQuery("SELECT (.+) FROM users WHERE email").
 			WithArgs("test@example.com").
@@ -139,8 +139,8 @@ func TestAuthRepo_GetUserByID(t *testing.T) {
 	t.Run("Success - User Found", func(t *testing.T) {
 		now := time.Now()
 		rows := pgxmock.NewRows([]string{
-			"id", "username", "email", "password_hash", "is_verified", "created_at", "updated_at",
-		}).AddRow(1, "testuser", "test@example.com", "hashedpwd", true, now, now)
+			"id", "username", "email", "password_hash", "is_verified", "role", "date_of_birth", "created_at", "updated_at",
+		}).AddRow(1, "testuser", "test@example.com", "hashedpwd", true, "customer", nil, now, now)
 
 		mock.ExpectQuery("SELECT (.+) FROM users WHERE id").
 			WithArgs(1).
@@ -239,6 +239,36 @@ func TestAuthRepo_UpdateUserVerified(t *testing.T) {
 	})
 }
 
+func TestAuthRepo_UpdateUserRole(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewAuthRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET role").
+			WithArgs("admin", 1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := repo.UpdateUserRole(context.Background(), 1, "admin")
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET role").
+			WithArgs("admin", 999).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err := repo.UpdateUserRole(context.Background(), 999, "admin")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestAuthRepo_CreateOTP(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -314,3 +344,34 @@ func TestAuthRepo_InvalidateUserOTPs(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestAuthRepo_UpdateUserBirthDate(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewAuthRepo(mock)
+	dob := time.Date(2000, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET date_of_birth").
+			WithArgs(dob, 1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err := repo.UpdateUserBirthDate(context.Background(), 1, dob)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET date_of_birth").
+			WithArgs(dob, 999).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err := repo.UpdateUserBirthDate(context.Background(), 999, dob)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,17 +2,22 @@ package repository
 
 import (
 	"context"
+	"math"
 	"project-app-bioskop/internal/data/entity"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type BookingRepoInterface interface {
-	CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int, price float64) (int, error)
+	CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int) (int, error)
 	GetBookingByID(ctx context.Context, id int) (entity.Booking, error)
 	GetBookingsByUserID(ctx context.Context, userID int) ([]entity.Booking, error)
 	GetBookingSeats(ctx context.Context, bookingID int) ([]entity.BookingSeat, error)
+	GetBookingConcessions(ctx context.Context, bookingID int) ([]entity.BookingConcession, error)
 	UpdateBookingStatus(ctx context.Context, bookingID int, status string) error
+	ExpirePendingBookings(ctx context.Context, paymentGrace time.Duration) ([]entity.Booking, error)
+	CancelBooking(ctx context.Context, bookingID int, fromStatus string, refund *entity.Refund) (int, error)
 }
 
 type BookingRepo struct {
@@ -23,28 +28,152 @@ func NewBookingRepo(db DBPool) BookingRepoInterface {
 	return &BookingRepo{DB: db}
 }
 
-// CreateBooking creates a booking with seats in a transaction
-func (r *BookingRepo) CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int, price float64) (int, error) {
+// CreateBooking creates a booking with seats in a transaction.
+// The showtime row is locked for the duration of the transaction so the availability
+// check and the insert are atomic; a conflicting seat returns ErrSeatTaken.
+// Each seat is charged the showtime price resolved by the pricing rules times its
+// category multiplier, frozen into the seat's price snapshot, and a
+// promotion on the booking is redeemed against that subtotal in the same transaction.
+// Concessions ordered with the booking are taken off stock and added at their current
+// price, and loyalty points requested on the booking are then spent against what is
+// left, capped so they never take the total below zero.
+func (r *BookingRepo) CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int) (int, error) {
+	// A seat listed twice would be priced twice before its second insert fails
+	seen := make(map[int]bool, len(seatIDs))
+	for _, seatID := range seatIDs {
+		if seen[seatID] {
+			return 0, ErrDuplicateSeat
+		}
+		seen[seatID] = true
+	}
+
 	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback(ctx)
 
+	// Serialize bookings for the same showtime and refuse cancelled ones
+	var lockedID int
+	lockQuery := `SELECT id FROM showtimes WHERE id = $1 AND status = 'scheduled' FOR UPDATE`
+	if err = tx.QueryRow(ctx, lockQuery, booking.ShowtimeID).Scan(&lockedID); err != nil {
+		return 0, err
+	}
+
+	// Re-check availability while holding the lock
+	var taken int
+	checkQuery := `
+		SELECT COUNT(*) FROM booking_seats bs
+		INNER JOIN bookings b ON b.id = bs.booking_id
+		WHERE b.showtime_id = $1 AND bs.seat_id = ANY($2) AND b.status NOT IN ('cancelled', 'expired')`
+	if err = tx.QueryRow(ctx, checkQuery, booking.ShowtimeID, seatIDs).Scan(&taken); err != nil {
+		return 0, err
+	}
+	if taken > 0 {
+		return 0, ErrSeatTaken
+	}
+
+	// Price each seat from the resolved showtime price and the seat's category
+	showtimePrice, err := resolveShowtimePrice(ctx, tx, booking.ShowtimeID)
+	if err != nil {
+		return 0, err
+	}
+	priceQuery := `
+		SELECT s.id, ROUND($3 * c.price_multiplier, 2)
+		FROM seats s
+		INNER JOIN showtimes st ON st.studio_id = s.studio_id
+		INNER JOIN seat_categories c ON c.name = s.category
+		WHERE st.id = $1 AND s.id = ANY($2)`
+	rows, err := tx.Query(ctx, priceQuery, booking.ShowtimeID, seatIDs, showtimePrice)
+	if err != nil {
+		return 0, err
+	}
+	prices := make(map[int]float64, len(seatIDs))
+	for rows.Next() {
+		var seatID int
+		var seatPrice float64
+		if err := rows.Scan(&seatID, &seatPrice); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		prices[seatID] = seatPrice
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var totalAmount float64
+	for _, seatID := range seatIDs {
+		seatPrice, ok := prices[seatID]
+		if !ok {
+			return 0, ErrSeatNotInStudio
+		}
+		totalAmount += seatPrice
+	}
+
+	var discount float64
+	if booking.PromotionID != nil {
+		discount, err = redeemPromotion(ctx, tx, *booking.PromotionID, booking.UserID, totalAmount)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var concessions []entity.BookingConcession
+	var concessionAmount float64
+	if len(booking.Concessions) > 0 {
+		concessions, err = takeConcessions(ctx, tx, booking.ShowtimeID, booking.Concessions)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range concessions {
+			concessionAmount += c.PriceSnapshot * float64(c.Quantity)
+		}
+	}
+	totalAmount += concessionAmount - discount
+
+	var points int
+	var pointsDiscount float64
+	if booking.PointsRedeemed > 0 && booking.PointsDiscount > 0 {
+		pointValue := booking.PointsDiscount / float64(booking.PointsRedeemed)
+		points = min(booking.PointsRedeemed, int(math.Floor(totalAmount/pointValue)))
+		pointsDiscount = math.Round(float64(points)*pointValue*100) / 100
+	}
+
 	// Insert booking
 	var bookingID int
-	totalAmount := price * float64(len(seatIDs))
-	query := `INSERT INTO bookings (user_id, showtime_id, status, total_amount) 
-			  VALUES ($1, $2, $3, $4) RETURNING id`
-	err = tx.QueryRow(ctx, query, booking.UserID, booking.ShowtimeID, "pending", totalAmount).Scan(&bookingID)
+	query := `INSERT INTO bookings (user_id, showtime_id, status, total_amount, expires_at, 
+			  promotion_id, promo_code, discount_amount, points_redeemed, points_discount, showtime_price) 
+			  VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11) RETURNING id`
+	err = tx.QueryRow(ctx, query,
+		booking.UserID, booking.ShowtimeID, "pending", totalAmount-pointsDiscount, booking.ExpiresAt,
+		booking.PromotionID, booking.PromoCode, discount, points, pointsDiscount, showtimePrice,
+	).Scan(&bookingID)
 	if err != nil {
 		return 0, err
 	}
 
+	if points > 0 {
+		if err = redeemPoints(ctx, tx, booking.UserID, bookingID, points); err != nil {
+			return 0, err
+		}
+	}
+
 	// Insert booking seats
 	for _, seatID := range seatIDs {
 		seatQuery := `INSERT INTO booking_seats (booking_id, seat_id, price_snapshot) VALUES ($1, $2, $3)`
-		_, err = tx.Exec(ctx, seatQuery, bookingID, seatID, price)
+		_, err = tx.Exec(ctx, seatQuery, bookingID, seatID, prices[seatID])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Insert booking concessions
+	for _, c := range concessions {
+		concessionQuery := `INSERT INTO booking_concessions (booking_id, concession_id, quantity, price_snapshot) 
+							VALUES ($1, $2, $3, $4)`
+		_, err = tx.Exec(ctx, concessionQuery, bookingID, c.ConcessionID, c.Quantity, c.PriceSnapshot)
 		if err != nil {
 			return 0, err
 		}
@@ -58,11 +187,13 @@ func (r *BookingRepo) CreateBooking(ctx context.Context, booking entity.Booking,
 
 // GetBookingByID retrieves booking by ID
 func (r *BookingRepo) GetBookingByID(ctx context.Context, id int) (entity.Booking, error) {
-	query := `SELECT id, user_id, showtime_id, status, total_amount, created_at 
+	query := `SELECT id, user_id, showtime_id, status, total_amount, expires_at, created_at, 
+			  promotion_id, COALESCE(promo_code, ''), discount_amount, points_redeemed, points_discount, showtime_price 
 			  FROM bookings WHERE id = $1`
 	var b entity.Booking
 	err := r.DB.QueryRow(ctx, query, id).Scan(
-		&b.ID, &b.UserID, &b.ShowtimeID, &b.Status, &b.TotalAmount, &b.CreatedAt,
+		&b.ID, &b.UserID, &b.ShowtimeID, &b.Status, &b.TotalAmount, &b.ExpiresAt, &b.CreatedAt,
+		&b.PromotionID, &b.PromoCode, &b.DiscountAmount, &b.PointsRedeemed, &b.PointsDiscount, &b.ShowtimePrice,
 	)
 	if err != nil {
 		return b, err
@@ -72,7 +203,8 @@ func (r *BookingRepo) GetBookingByID(ctx context.Context, id int) (entity.Bookin
 
 // GetBookingsByUserID retrieves all bookings for a user
 func (r *BookingRepo) GetBookingsByUserID(ctx context.Context, userID int) ([]entity.Booking, error) {
-	query := `SELECT id, user_id, showtime_id, status, total_amount, created_at 
+	query := `SELECT id, user_id, showtime_id, status, total_amount, expires_at, created_at, 
+			  promotion_id, COALESCE(promo_code, ''), discount_amount, points_redeemed, points_discount, showtime_price 
 			  FROM bookings WHERE user_id = $1 ORDER BY created_at DESC`
 	rows, err := r.DB.Query(ctx, query, userID)
 	if err != nil {
@@ -83,7 +215,11 @@ func (r *BookingRepo) GetBookingsByUserID(ctx context.Context, userID int) ([]en
 	var bookings []entity.Booking
 	for rows.Next() {
 		var b entity.Booking
-		if err := rows.Scan(&b.ID, &b.UserID, &b.ShowtimeID, &b.Status, &b.TotalAmount, &b.CreatedAt); err != nil {
+		err := rows.Scan(
+			&b.ID, &b.UserID, &b.ShowtimeID, &b.Status, &b.TotalAmount, &b.ExpiresAt, &b.CreatedAt,
+			&b.PromotionID, &b.PromoCode, &b.DiscountAmount, &b.PointsRedeemed, &b.PointsDiscount, &b.ShowtimePrice,
+		)
+		if err != nil {
 			return nil, err
 		}
 		bookings = append(bookings, b)
@@ -111,9 +247,125 @@ func (r *BookingRepo) GetBookingSeats(ctx context.Context, bookingID int) ([]ent
 	return seats, nil
 }
 
+// GetBookingConcessions retrieves the concession line items of a booking
+func (r *BookingRepo) GetBookingConcessions(ctx context.Context, bookingID int) ([]entity.BookingConcession, error) {
+	query := `SELECT bc.id, bc.booking_id, bc.concession_id, c.name, bc.quantity, bc.price_snapshot 
+			  FROM booking_concessions bc 
+			  INNER JOIN concessions c ON c.id = bc.concession_id 
+			  WHERE bc.booking_id = $1 
+			  ORDER BY bc.id`
+	rows, err := r.DB.Query(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var concessions []entity.BookingConcession
+	for rows.Next() {
+		var c entity.BookingConcession
+		if err := rows.Scan(&c.ID, &c.BookingID, &c.ConcessionID, &c.Name, &c.Quantity, &c.PriceSnapshot); err != nil {
+			return nil, err
+		}
+		concessions = append(concessions, c)
+	}
+	return concessions, rows.Err()
+}
+
 // UpdateBookingStatus updates booking status
 func (r *BookingRepo) UpdateBookingStatus(ctx context.Context, bookingID int, status string) error {
 	query := `UPDATE bookings SET status = $1 WHERE id = $2`
 	_, err := r.DB.Exec(ctx, query, status, bookingID)
 	return err
 }
+
+// ExpirePendingBookings marks every pending booking whose hold has passed as expired,
+// returns the loyalty points and concessions they took and returns the bookings that
+// were released. A booking whose payment the provider has not confirmed yet keeps its
+// seats for paymentGrace past its hold, so a late confirmation can still settle it; a
+// charge that completes after that is refunded when its confirmation arrives.
+func (r *BookingRepo) ExpirePendingBookings(ctx context.Context, paymentGrace time.Duration) ([]entity.Booking, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE bookings SET status = 'expired' 
+			  WHERE status = 'pending' AND expires_at IS NOT NULL AND expires_at <= NOW()
+			  AND (
+			  		expires_at <= NOW() - make_interval(secs => $1)
+			  		OR NOT EXISTS (
+			  			SELECT 1 FROM payments p WHERE p.booking_id = bookings.id AND p.status = 'pending'
+			  		)
+			  )
+			  RETURNING id, user_id, showtime_id, status, total_amount, expires_at, created_at`
+	rows, err := tx.Query(ctx, query, paymentGrace.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := scanBookings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingIDs := make([]int, 0, len(bookings))
+	for _, b := range bookings {
+		bookingIDs = append(bookingIDs, b.ID)
+	}
+	if err = reverseLoyalty(ctx, tx, bookingIDs); err != nil {
+		return nil, err
+	}
+	if err = releaseConcessions(ctx, tx, bookingIDs); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// CancelBooking cancels a booking that still has fromStatus and, when refund is set,
+// records the refund in the same transaction together with the reversal of the
+// booking's loyalty points and the return of its concessions to stock. It returns the
+// new refund ID (0 when none).
+func (r *BookingRepo) CancelBooking(ctx context.Context, bookingID int, fromStatus string, refund *entity.Refund) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE bookings SET status = 'cancelled' WHERE id = $1 AND status = $2`
+	tag, err := tx.Exec(ctx, query, bookingID, fromStatus)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, ErrBookingStatusChanged
+	}
+
+	var refundID int
+	if refund != nil {
+		refundQuery := `INSERT INTO refunds (payment_id, booking_id, amount, status, reason) 
+						VALUES ($1, $2, $3, $4, $5) RETURNING id`
+		err = tx.QueryRow(ctx, refundQuery,
+			refund.PaymentID, bookingID, refund.Amount, refund.Status, refund.Reason,
+		).Scan(&refundID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = reverseLoyalty(ctx, tx, []int{bookingID}); err != nil {
+		return 0, err
+	}
+	if err = releaseConcessions(ctx, tx, []int{bookingID}); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return refundID, nil
+}
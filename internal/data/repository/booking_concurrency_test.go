@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"project-app-bioskop/internal/data/entity"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBookingRepo_CreateBooking_Concurrent fires parallel bookings at a single seat
+// against a real PostgreSQL database. Set TEST_DATABASE_URL to run it.
+func TestBookingRepo_CreateBooking_Concurrent(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	suffix := time.Now().UnixNano()
+
+	// Fixtures: one user, one cinema/studio/seat, one movie and one showtime
+	var userID, cinemaID, studioID, seatID, movieID, showtimeID int
+
+	// Remove the bookings made by the test and the fixtures in dependency order, so the
+	// next run starts clean. IDs of fixtures that were never created are 0 and match nothing.
+	t.Cleanup(func() {
+		cleanup := []struct {
+			query string
+			id    *int
+		}{
+			{`DELETE FROM booking_seats WHERE booking_id IN (SELECT id FROM bookings WHERE showtime_id = $1)`, &showtimeID},
+			{`DELETE FROM bookings WHERE showtime_id = $1`, &showtimeID},
+			{`DELETE FROM showtimes WHERE id = $1`, &showtimeID},
+			{`DELETE FROM seats WHERE id = $1`, &seatID},
+			{`DELETE FROM studios WHERE id = $1`, &studioID},
+			{`DELETE FROM cinemas WHERE id = $1`, &cinemaID},
+			{`DELETE FROM movies WHERE id = $1`, &movieID},
+			{`DELETE FROM users WHERE id = $1`, &userID},
+		}
+		for _, c := range cleanup {
+			_, err := pool.Exec(ctx, c.query, *c.id)
+			assert.NoError(t, err, c.query)
+		}
+	})
+
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO users (username, email, password_hash) VALUES ($1, $2, 'x') RETURNING id`,
+		fmt.Sprintf("race-%d", suffix), fmt.Sprintf("race-%d@mail.com", suffix),
+	).Scan(&userID))
+
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO cinemas (name, location) VALUES ('Race Cinema', 'Test') RETURNING id`,
+	).Scan(&cinemaID))
+
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO studios (cinema_id, name, total_seats) VALUES ($1, 'Race Studio', 1) RETURNING id`, cinemaID,
+	).Scan(&studioID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO seats (studio_id, seat_code) VALUES ($1, 'A1') RETURNING id`, studioID,
+	).Scan(&seatID))
+
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO movies (title, genres, duration_in_minutes, release_status)
+		 VALUES ('Race Movie', '{Action}', 120, 'now_playing') RETURNING id`,
+	).Scan(&movieID))
+
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO showtimes (cinema_id, studio_id, movie_id, show_date, show_time, price)
+		 VALUES ($1, $2, $3, CURRENT_DATE + 1, '19:00', 50000) RETURNING id`,
+		cinemaID, studioID, movieID,
+	).Scan(&showtimeID))
+
+	repo := NewBookingRepo(pool)
+
+	const attempts = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+		conflicts int
+		failures  []error
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			booking := entity.Booking{UserID: userID, ShowtimeID: showtimeID}
+			_, err := repo.CreateBooking(ctx, booking, []int{seatID})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				successes++
+			case errors.Is(err, ErrSeatTaken):
+				conflicts++
+			default:
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Empty(t, failures)
+	assert.Equal(t, 1, successes)
+	assert.Equal(t, attempts-1, conflicts)
+
+	var booked int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM booking_seats bs
+		 INNER JOIN bookings b ON b.id = bs.booking_id
+		 WHERE b.showtime_id = $1 AND bs.seat_id = $2`, showtimeID, seatID,
+	).Scan(&booked))
+	assert.Equal(t, 1, booked)
+}
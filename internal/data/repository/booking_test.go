@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"project-app-bioskop/internal/data/entity"
 	"testing"
 	"time"
 
@@ -22,10 +23,12 @@ func TestBookingRepo_GetBookingByID(t *testing.T) {
 
 	t.Run("Success - Booking Found", func(t *testing.T) {
 		now := time.Now()
+		promotionID := 3
 
 		rows := pgxmock.NewRows([]string{
-			"id", "user_id", "showtime_id", "status", "total_amount", "created_at",
-		}).AddRow(1, 1, 1, "confirmed", 100000.0, now)
+			"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at",
+			"promotion_id", "promo_code", "discount_amount", "points_redeemed", "points_discount", "showtime_price",
+		}).AddRow(1, 1, 1, "confirmed", 90000.0, nil, now, &promotionID, "HEMAT10", 10000.0, 20, 20.0, 55000.0)
 
 		mock.ExpectQuery("SELECT (.+) FROM bookings WHERE id").
 			WithArgs(1).
@@ -35,8 +38,14 @@ func TestBookingRepo_GetBookingByID(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 1, booking.ID)
 		assert.Equal(t, 1, booking.UserID)
-		assert.Equal(t, 100000.0, booking.TotalAmount)
+		assert.Equal(t, 90000.0, booking.TotalAmount)
 		assert.Equal(t, "confirmed", booking.Status)
+		assert.Equal(t, &promotionID, booking.PromotionID)
+		assert.Equal(t, "HEMAT10", booking.PromoCode)
+		assert.Equal(t, 10000.0, booking.DiscountAmount)
+		assert.Equal(t, 20, booking.PointsRedeemed)
+		assert.Equal(t, 20.0, booking.PointsDiscount)
+		assert.Equal(t, 55000.0, booking.ShowtimePrice)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -76,10 +85,11 @@ func TestBookingRepo_GetBookingsByUserID(t *testing.T) {
 		now := time.Now()
 
 		rows := pgxmock.NewRows([]string{
-			"id", "user_id", "showtime_id", "status", "total_amount", "created_at",
+			"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at",
+			"promotion_id", "promo_code", "discount_amount", "points_redeemed", "points_discount", "showtime_price",
 		}).
-			AddRow(1, 1, 1, "confirmed", 100000.0, now).
-			AddRow(2, 1, 2, "pending", 150000.0, now)
+			AddRow(1, 1, 1, "confirmed", 100000.0, nil, now, nil, "", 0.0, 0, 0.0, 50000.0).
+			AddRow(2, 1, 2, "pending", 150000.0, &now, now, nil, "", 0.0, 0, 0.0, 50000.0)
 
 		mock.ExpectQuery("SELECT (.+) FROM bookings WHERE user_id").
 			WithArgs(1).
@@ -95,7 +105,8 @@ func TestBookingRepo_GetBookingsByUserID(t *testing.T) {
 
 	t.Run("Empty Result", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "user_id", "showtime_id", "status", "total_amount", "created_at",
+			"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at",
+			"promotion_id", "promo_code", "discount_amount", "points_redeemed", "points_discount", "showtime_price",
 		})
 
 		mock.ExpectQuery("SELECT (.+) FROM bookings WHERE user_id").
@@ -174,6 +185,42 @@ func TestBookingRepo_GetBookingSeats(t *testing.T) {
 	})
 }
 
+func TestBookingRepo_GetBookingConcessions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewBookingRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM booking_concessions bc INNER JOIN concessions c (.+) WHERE bc.booking_id").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "booking_id", "concession_id", "name", "quantity", "price_snapshot",
+			}).AddRow(1, 1, 2, "Popcorn Combo", 2, 65000.0))
+
+		concessions, err := repo.GetBookingConcessions(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Len(t, concessions, 1)
+		assert.Equal(t, "Popcorn Combo", concessions[0].Name)
+		assert.Equal(t, 2, concessions[0].Quantity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM booking_concessions").
+			WithArgs(1).
+			WillReturnError(errors.New("database error"))
+
+		concessions, err := repo.GetBookingConcessions(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, concessions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestBookingRepo_UpdateBookingStatus(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -203,3 +250,437 @@ func TestBookingRepo_UpdateBookingStatus(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestBookingRepo_CreateBooking(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewBookingRepo(mock)
+	expiresAt := time.Now().Add(15 * time.Minute)
+	booking := entity.Booking{UserID: 1, ShowtimeID: 1, ExpiresAt: &expiresAt}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM showtimes WHERE id = (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT COUNT").
+			WithArgs(1, []int{10, 11}).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+		expectShowtimePrice(mock, 1, 50000)
+		mock.ExpectQuery("SELECT s.id, ROUND(.+) FROM seats s").
+			WithArgs(1, []int{10, 11}, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "price"}).AddRow(10, 50000.0).AddRow(11, 75000.0))
+		mock.ExpectQuery("INSERT INTO bookings").
+			WithArgs(1, 1, "pending", 125000.0, booking.ExpiresAt, booking.PromotionID, "", 0.0, 0, 0.0, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(5, 10, 50000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(5, 11, 75000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateBooking(context.Background(), booking, []int{10, 11})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Seat Taken", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM showtimes WHERE id = (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT COUNT").
+			WithArgs(1, []int{10}).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		id, err := repo.CreateBooking(context.Background(), booking, []int{10})
+		assert.ErrorIs(t, err, ErrSeatTaken)
+		assert.Equal(t, 0, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Duplicate Seat", func(t *testing.T) {
+		id, err := repo.CreateBooking(context.Background(), booking, []int{5, 5})
+		assert.ErrorIs(t, err, ErrDuplicateSeat)
+		assert.Equal(t, 0, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Seat Not In Studio", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM showtimes WHERE id = (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT COUNT").
+			WithArgs(1, []int{10, 99}).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+		expectShowtimePrice(mock, 1, 50000)
+		mock.ExpectQuery("SELECT s.id, ROUND(.+) FROM seats s").
+			WithArgs(1, []int{10, 99}, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "price"}).AddRow(10, 50000.0))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), booking, []int{10, 99})
+		assert.ErrorIs(t, err, ErrSeatNotInStudio)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Showtime Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM showtimes WHERE id = (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), booking, []int{10})
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	promotionID := 3
+	promoBooking := booking
+	promoBooking.PromotionID = &promotionID
+	promoBooking.PromoCode = "HEMAT10"
+	expectPromoSubtotal := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM showtimes WHERE id = (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT COUNT").
+			WithArgs(1, []int{10, 11}).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+		expectShowtimePrice(mock, 1, 50000)
+		mock.ExpectQuery("SELECT s.id, ROUND(.+) FROM seats s").
+			WithArgs(1, []int{10, 11}, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "price"}).AddRow(10, 50000.0).AddRow(11, 75000.0))
+	}
+	promoColumns := []string{"discount_type", "discount_value", "max_discount", "usage_limit", "per_user_limit", "usable"}
+
+	t.Run("Success - With Promotion", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("SELECT discount_type(.+) FROM promotions WHERE id = (.+) FOR UPDATE").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows(promoColumns).AddRow("percentage", 10.0, 0.0, 100, 1, true))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(\\*\\) FILTER").
+			WithArgs(3, 1).
+			WillReturnRows(pgxmock.NewRows([]string{"used", "used_by_user"}).AddRow(99, 0))
+		mock.ExpectQuery("INSERT INTO bookings").
+			WithArgs(1, 1, "pending", 112500.0, booking.ExpiresAt, &promotionID, "HEMAT10", 12500.0, 0, 0.0, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(6))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(6, 10, 50000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(6, 11, 75000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateBooking(context.Background(), promoBooking, []int{10, 11})
+		assert.NoError(t, err)
+		assert.Equal(t, 6, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Promotion Unavailable", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("SELECT discount_type(.+) FROM promotions").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows(promoColumns).AddRow("fixed", 10000.0, 0.0, 0, 0, false))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), promoBooking, []int{10, 11})
+		assert.ErrorIs(t, err, ErrPromoUnavailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Promotion Exhausted", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("SELECT discount_type(.+) FROM promotions").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows(promoColumns).AddRow("fixed", 10000.0, 0.0, 100, 0, true))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(\\*\\) FILTER").
+			WithArgs(3, 1).
+			WillReturnRows(pgxmock.NewRows([]string{"used", "used_by_user"}).AddRow(100, 0))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), promoBooking, []int{10, 11})
+		assert.ErrorIs(t, err, ErrPromoExhausted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Promotion User Limit", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("SELECT discount_type(.+) FROM promotions").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows(promoColumns).AddRow("fixed", 10000.0, 0.0, 0, 1, true))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(\\*\\) FILTER").
+			WithArgs(3, 1).
+			WillReturnRows(pgxmock.NewRows([]string{"used", "used_by_user"}).AddRow(5, 1))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), promoBooking, []int{10, 11})
+		assert.ErrorIs(t, err, ErrPromoUserLimit)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	pointsBooking := booking
+	pointsBooking.PointsRedeemed = 300
+	pointsBooking.PointsDiscount = 300
+	lotColumns := []string{"id", "remaining"}
+
+	t.Run("Success - Redeems Points From Earliest Lots", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("INSERT INTO bookings").
+			WithArgs(1, 1, "pending", 124700.0, booking.ExpiresAt, booking.PromotionID, "", 0.0, 300, 300.0, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger (.+) entry_type = 'debt'").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger(.+) ORDER BY expires_at, id FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(2, 100).AddRow(5, 500))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = remaining - (.+) INSERT INTO loyalty_ledger").
+			WithArgs(1, 7, 100, 2, "redeem").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = remaining - (.+) INSERT INTO loyalty_ledger").
+			WithArgs(1, 7, 200, 5, "redeem").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(7, 10, 50000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(7, 11, 75000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateBooking(context.Background(), pointsBooking, []int{10, 11})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Points Capped At Total", func(t *testing.T) {
+		capped := booking
+		capped.PointsRedeemed = 200000
+		capped.PointsDiscount = 200000
+
+		expectPromoSubtotal()
+		mock.ExpectQuery("INSERT INTO bookings").
+			WithArgs(1, 1, "pending", 0.0, booking.ExpiresAt, booking.PromotionID, "", 0.0, 125000, 125000.0, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(8))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger (.+) entry_type = 'debt'").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger(.+) ORDER BY expires_at").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(2, 200000))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = remaining - (.+) INSERT INTO loyalty_ledger").
+			WithArgs(1, 8, 125000, 2, "redeem").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(8, 10, 50000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(8, 11, 75000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateBooking(context.Background(), capped, []int{10, 11})
+		assert.NoError(t, err)
+		assert.Equal(t, 8, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	concessionBooking := booking
+	concessionBooking.Concessions = []entity.BookingConcession{
+		{ConcessionID: 2, Quantity: 2},
+		{ConcessionID: 4, Quantity: 1},
+	}
+
+	t.Run("Success - With Concessions", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("UPDATE concessions c SET stock = c.stock - i.quantity (.+) c.stock >= i.quantity RETURNING").
+			WithArgs(1, []int{2, 4}, []int{2, 1}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "price"}).
+				AddRow(4, "Mineral Water", 10000.0).
+				AddRow(2, "Popcorn Combo", 65000.0))
+		mock.ExpectQuery("INSERT INTO bookings").
+			WithArgs(1, 1, "pending", 265000.0, booking.ExpiresAt, booking.PromotionID, "", 0.0, 0, 0.0, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(10, 10, 50000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_seats").
+			WithArgs(10, 11, 75000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_concessions").
+			WithArgs(10, 2, 2, 65000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO booking_concessions").
+			WithArgs(10, 4, 1, 10000.0).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateBooking(context.Background(), concessionBooking, []int{10, 11})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Concession Out Of Stock", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("UPDATE concessions c SET stock = c.stock - i.quantity").
+			WithArgs(1, []int{2, 4}, []int{2, 1}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name", "price"}).AddRow(4, "Mineral Water", 10000.0))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), concessionBooking, []int{10, 11})
+		assert.ErrorIs(t, err, ErrConcessionUnavailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Insufficient Points", func(t *testing.T) {
+		expectPromoSubtotal()
+		mock.ExpectQuery("INSERT INTO bookings").
+			WithArgs(1, 1, "pending", 124700.0, booking.ExpiresAt, booking.PromotionID, "", 0.0, 300, 300.0, 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger (.+) entry_type = 'debt'").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger(.+) ORDER BY expires_at").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(2, 100))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateBooking(context.Background(), pointsBooking, []int{10, 11})
+		assert.ErrorIs(t, err, ErrInsufficientPoints)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBookingRepo_ExpirePendingBookings(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewBookingRepo(mock)
+
+	t.Run("Success - Bookings Expired", func(t *testing.T) {
+		now := time.Now()
+		rows := pgxmock.NewRows([]string{
+			"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at",
+		}).
+			AddRow(3, 1, 2, "expired", 100000.0, &now, now).
+			AddRow(4, 2, 2, "expired", 50000.0, &now, now)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE bookings SET status = 'expired'").
+			WithArgs(1800.0).
+			WillReturnRows(rows)
+		expectReverseLoyalty(mock, []int{3, 4})
+		expectReleaseConcessions(mock, []int{3, 4})
+		mock.ExpectCommit()
+
+		bookings, err := repo.ExpirePendingBookings(context.Background(), 30*time.Minute)
+		assert.NoError(t, err)
+		assert.Len(t, bookings, 2)
+		assert.Equal(t, "expired", bookings[0].Status)
+		assert.Equal(t, 2, bookings[1].ShowtimeID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Nothing To Expire", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE bookings SET status = 'expired'").
+			WithArgs(1800.0).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at",
+			}))
+		mock.ExpectCommit()
+
+		bookings, err := repo.ExpirePendingBookings(context.Background(), 30*time.Minute)
+		assert.NoError(t, err)
+		assert.Empty(t, bookings)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE bookings SET status = 'expired'").
+			WithArgs(1800.0).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		bookings, err := repo.ExpirePendingBookings(context.Background(), 30*time.Minute)
+		assert.Error(t, err)
+		assert.Nil(t, bookings)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBookingRepo_CancelBooking(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewBookingRepo(mock)
+
+	t.Run("Success - Pending Booking Without Refund", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE bookings SET status = 'cancelled'").
+			WithArgs(1, "pending").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		expectReverseLoyalty(mock, []int{1})
+		expectReleaseConcessions(mock, []int{1})
+		mock.ExpectCommit()
+
+		refundID, err := repo.CancelBooking(context.Background(), 1, "pending", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, refundID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Paid Booking With Refund", func(t *testing.T) {
+		refund := &entity.Refund{PaymentID: 7, Amount: 100000, Status: "pending", Reason: "sick"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE bookings SET status = 'cancelled'").
+			WithArgs(1, "paid").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery("INSERT INTO refunds").
+			WithArgs(7, 1, 100000.0, "pending", "sick").
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(3))
+		expectReverseLoyalty(mock, []int{1})
+		expectReleaseConcessions(mock, []int{1})
+		mock.ExpectCommit()
+
+		refundID, err := repo.CancelBooking(context.Background(), 1, "paid", refund)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, refundID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Status Changed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE bookings SET status = 'cancelled'").
+			WithArgs(1, "pending").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectRollback()
+
+		_, err := repo.CancelBooking(context.Background(), 1, "pending", nil)
+		assert.ErrorIs(t, err, ErrBookingStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"project-app-bioskop/internal/data/entity"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type CinemaRepoInterface interface {
@@ -10,6 +14,13 @@ type CinemaRepoInterface interface {
 	GetCinemaByID(ctx context.Context, id int) (entity.Cinema, error)
 	GetStudiosByCinemaID(ctx context.Context, cinemaID int) ([]entity.Studio, error)
 	CountCinemas(ctx context.Context) (int, error)
+	CreateCinema(ctx context.Context, cinema entity.Cinema) (entity.Cinema, error)
+	UpdateCinema(ctx context.Context, cinema entity.Cinema) (entity.Cinema, error)
+	GetStudioByID(ctx context.Context, id int) (entity.Studio, error)
+	CreateStudio(ctx context.Context, studio entity.Studio) (entity.Studio, error)
+	UpdateStudio(ctx context.Context, studio entity.Studio) (entity.Studio, error)
+	GetSeatCategories(ctx context.Context) ([]entity.SeatCategory, error)
+	ReplaceStudioSeats(ctx context.Context, studioID int, seats []entity.Seat) (int, error)
 }
 
 type CinemaRepo struct {
@@ -78,3 +89,152 @@ func (r *CinemaRepo) CountCinemas(ctx context.Context) (int, error) {
 	err := r.DB.QueryRow(ctx, query).Scan(&count)
 	return count, err
 }
+
+// CreateCinema inserts a new cinema
+func (r *CinemaRepo) CreateCinema(ctx context.Context, cinema entity.Cinema) (entity.Cinema, error) {
+	query := `INSERT INTO cinemas (name, location) VALUES ($1, $2) RETURNING id, created_at`
+	err := r.DB.QueryRow(ctx, query, cinema.Name, cinema.Location).Scan(&cinema.ID, &cinema.CreatedAt)
+	if err != nil {
+		return cinema, err
+	}
+	return cinema, nil
+}
+
+// UpdateCinema changes the name and location of a cinema
+func (r *CinemaRepo) UpdateCinema(ctx context.Context, cinema entity.Cinema) (entity.Cinema, error) {
+	query := `UPDATE cinemas SET name = $1, location = $2 WHERE id = $3 RETURNING created_at`
+	err := r.DB.QueryRow(ctx, query, cinema.Name, cinema.Location, cinema.ID).Scan(&cinema.CreatedAt)
+	if err != nil {
+		return cinema, err
+	}
+	return cinema, nil
+}
+
+// GetStudioByID retrieves studio by ID
+func (r *CinemaRepo) GetStudioByID(ctx context.Context, id int) (entity.Studio, error) {
+	query := `SELECT id, cinema_id, name, total_seats FROM studios WHERE id = $1`
+	var s entity.Studio
+	err := r.DB.QueryRow(ctx, query, id).Scan(&s.ID, &s.CinemaID, &s.Name, &s.TotalSeats)
+	if err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// CreateStudio inserts an empty studio, seats are added by ReplaceStudioSeats
+func (r *CinemaRepo) CreateStudio(ctx context.Context, studio entity.Studio) (entity.Studio, error) {
+	query := `INSERT INTO studios (cinema_id, name, total_seats) VALUES ($1, $2, 0) RETURNING id, total_seats`
+	err := r.DB.QueryRow(ctx, query, studio.CinemaID, studio.Name).Scan(&studio.ID, &studio.TotalSeats)
+	if err != nil {
+		return studio, err
+	}
+	return studio, nil
+}
+
+// UpdateStudio renames a studio
+func (r *CinemaRepo) UpdateStudio(ctx context.Context, studio entity.Studio) (entity.Studio, error) {
+	query := `UPDATE studios SET name = $1 WHERE id = $2 RETURNING cinema_id, total_seats`
+	err := r.DB.QueryRow(ctx, query, studio.Name, studio.ID).Scan(&studio.CinemaID, &studio.TotalSeats)
+	if err != nil {
+		return studio, err
+	}
+	return studio, nil
+}
+
+// GetSeatCategories retrieves all seat categories ordered by price
+func (r *CinemaRepo) GetSeatCategories(ctx context.Context) ([]entity.SeatCategory, error) {
+	query := `SELECT id, name, price_multiplier FROM seat_categories ORDER BY price_multiplier, name`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []entity.SeatCategory
+	for rows.Next() {
+		var c entity.SeatCategory
+		if err := rows.Scan(&c.ID, &c.Name, &c.PriceMultiplier); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
+
+// ReplaceStudioSeats makes the studio's seats match the given layout and returns the new
+// total_seats. Seats that would be removed while referenced by booking_seats abort the change.
+func (r *CinemaRepo) ReplaceStudioSeats(ctx context.Context, studioID int, seats []entity.Seat) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize layout changes per studio
+	var lockedID int
+	lockQuery := `SELECT id FROM studios WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, lockQuery, studioID).Scan(&lockedID); err != nil {
+		return 0, err
+	}
+
+	codes := make([]string, len(seats))
+	rowLabels := make([]string, len(seats))
+	columns := make([]int, len(seats))
+	categories := make([]string, len(seats))
+	for i, seat := range seats {
+		codes[i] = seat.SeatCode
+		rowLabels[i] = seat.RowLabel
+		columns[i] = seat.ColumnNumber
+		categories[i] = seat.Category
+		if categories[i] == "" {
+			categories[i] = entity.SeatCategoryRegular
+		}
+	}
+
+	inUseQuery := `SELECT DISTINCT s.seat_code FROM seats s 
+				   INNER JOIN booking_seats bs ON bs.seat_id = s.id 
+				   WHERE s.studio_id = $1 AND NOT (s.seat_code = ANY($2)) 
+				   ORDER BY s.seat_code`
+	rows, err := tx.Query(ctx, inUseQuery, studioID, codes)
+	if err != nil {
+		return 0, err
+	}
+	var inUse []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		inUse = append(inUse, code)
+	}
+	rows.Close()
+	if len(inUse) > 0 {
+		return 0, fmt.Errorf("%w: %s", ErrSeatsInUse, strings.Join(inUse, ", "))
+	}
+
+	deleteQuery := `DELETE FROM seats WHERE studio_id = $1 AND NOT (seat_code = ANY($2))`
+	if _, err := tx.Exec(ctx, deleteQuery, studioID, codes); err != nil {
+		return 0, err
+	}
+
+	// Existing seats keep their IDs so booking history stays attached
+	upsertQuery := `INSERT INTO seats (studio_id, seat_code, row_label, column_number, category) 
+					SELECT $1, code, row_label, column_number, category 
+					FROM unnest($2::text[], $3::text[], $4::int[], $5::text[]) AS l(code, row_label, column_number, category) 
+					ON CONFLICT (studio_id, seat_code) 
+					DO UPDATE SET row_label = EXCLUDED.row_label, column_number = EXCLUDED.column_number, 
+					category = EXCLUDED.category`
+	if _, err := tx.Exec(ctx, upsertQuery, studioID, codes, rowLabels, columns, categories); err != nil {
+		return 0, err
+	}
+
+	var total int
+	totalQuery := `UPDATE studios SET total_seats = (SELECT COUNT(*) FROM seats WHERE studio_id = $1) 
+				   WHERE id = $1 RETURNING total_seats`
+	if err := tx.QueryRow(ctx, totalQuery, studioID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, tx.Commit(ctx)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"project-app-bioskop/internal/data/entity"
 	"testing"
 	"time"
 
@@ -202,3 +203,158 @@ func TestCinemaRepo_CountCinemas(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestCinemaRepo_CreateCinema(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCinemaRepo(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO cinemas").
+		WithArgs("Cinema Baru", "Bandung").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(4, now))
+
+	cinema, err := repo.CreateCinema(context.Background(), entity.Cinema{Name: "Cinema Baru", Location: "Bandung"})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, cinema.ID)
+	assert.Equal(t, now, cinema.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCinemaRepo_UpdateStudio(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCinemaRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE studios SET name").
+			WithArgs("Studio IMAX", 1).
+			WillReturnRows(pgxmock.NewRows([]string{"cinema_id", "total_seats"}).AddRow(1, 50))
+
+		studio, err := repo.UpdateStudio(context.Background(), entity.Studio{ID: 1, Name: "Studio IMAX"})
+		assert.NoError(t, err)
+		assert.Equal(t, 50, studio.TotalSeats)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE studios SET name").
+			WithArgs("Studio IMAX", 999).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.UpdateStudio(context.Background(), entity.Studio{ID: 999, Name: "Studio IMAX"})
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCinemaRepo_ReplaceStudioSeats(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCinemaRepo(mock)
+	seats := []entity.Seat{
+		{SeatCode: "A1", RowLabel: "A", ColumnNumber: 1},
+		{SeatCode: "A2", RowLabel: "A", ColumnNumber: 3, Category: "vip"},
+	}
+	codes := []string{"A1", "A2"}
+
+	t.Run("Success - Seats Replaced And Total Synced", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT DISTINCT s.seat_code FROM seats").
+			WithArgs(1, codes).
+			WillReturnRows(pgxmock.NewRows([]string{"seat_code"}))
+		mock.ExpectExec("DELETE FROM seats").
+			WithArgs(1, codes).
+			WillReturnResult(pgxmock.NewResult("DELETE", 48))
+		mock.ExpectExec("INSERT INTO seats").
+			WithArgs(1, codes, []string{"A", "A"}, []int{1, 3}, []string{"regular", "vip"}).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+		mock.ExpectQuery("UPDATE studios SET total_seats").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"total_seats"}).AddRow(2))
+		mock.ExpectCommit()
+
+		total, err := repo.ReplaceStudioSeats(context.Background(), 1, seats)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Removed Seats Have Bookings", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT DISTINCT s.seat_code FROM seats").
+			WithArgs(1, codes).
+			WillReturnRows(pgxmock.NewRows([]string{"seat_code"}).AddRow("E9").AddRow("E10"))
+		mock.ExpectRollback()
+
+		_, err := repo.ReplaceStudioSeats(context.Background(), 1, seats)
+		assert.ErrorIs(t, err, ErrSeatsInUse)
+		assert.Contains(t, err.Error(), "E9, E10")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(999).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.ReplaceStudioSeats(context.Background(), 999, seats)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCinemaRepo_GetSeatCategories(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCinemaRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := pgxmock.NewRows([]string{"id", "name", "price_multiplier"}).
+			AddRow(1, "regular", 1.0).
+			AddRow(2, "vip", 1.5)
+
+		mock.ExpectQuery("SELECT id, name, price_multiplier FROM seat_categories").
+			WillReturnRows(rows)
+
+		categories, err := repo.GetSeatCategories(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, categories, 2)
+		assert.Equal(t, 1.5, categories[1].PriceMultiplier)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, name, price_multiplier FROM seat_categories").
+			WillReturnError(errors.New("database error"))
+
+		categories, err := repo.GetSeatCategories(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ConcessionRepoInterface interface {
+	GetConcessionsByCinema(ctx context.Context, cinemaID int, activeOnly bool) ([]entity.Concession, error)
+	GetConcessionByID(ctx context.Context, id int) (entity.Concession, error)
+	CreateConcession(ctx context.Context, concession entity.Concession) (entity.Concession, error)
+	UpdateConcession(ctx context.Context, concession entity.Concession) (entity.Concession, error)
+}
+
+type ConcessionRepo struct {
+	DB DBPool
+}
+
+func NewConcessionRepo(db DBPool) ConcessionRepoInterface {
+	return &ConcessionRepo{DB: db}
+}
+
+// concessionColumns lists the concession columns in the order scanConcession reads them
+const concessionColumns = `id, cinema_id, name, category, description, price, stock, is_active, created_at, updated_at`
+
+func scanConcession(row pgx.Row) (entity.Concession, error) {
+	var c entity.Concession
+	err := row.Scan(
+		&c.ID, &c.CinemaID, &c.Name, &c.Category, &c.Description, &c.Price, &c.Stock, &c.IsActive,
+		&c.CreatedAt, &c.UpdatedAt,
+	)
+	return c, err
+}
+
+// GetConcessionsByCinema retrieves the concessions of a cinema by category and name,
+// only those on sale when activeOnly is set
+func (r *ConcessionRepo) GetConcessionsByCinema(ctx context.Context, cinemaID int, activeOnly bool) ([]entity.Concession, error) {
+	query := `SELECT ` + concessionColumns + ` FROM concessions
+			  WHERE cinema_id = $1 AND (is_active OR NOT $2)
+			  ORDER BY category, name, id`
+	rows, err := r.DB.Query(ctx, query, cinemaID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var concessions []entity.Concession
+	for rows.Next() {
+		c, err := scanConcession(rows)
+		if err != nil {
+			return nil, err
+		}
+		concessions = append(concessions, c)
+	}
+	return concessions, rows.Err()
+}
+
+// GetConcessionByID retrieves a concession by ID
+func (r *ConcessionRepo) GetConcessionByID(ctx context.Context, id int) (entity.Concession, error) {
+	query := `SELECT ` + concessionColumns + ` FROM concessions WHERE id = $1`
+	return scanConcession(r.DB.QueryRow(ctx, query, id))
+}
+
+// CreateConcession adds a concession to a cinema's catalogue
+func (r *ConcessionRepo) CreateConcession(ctx context.Context, c entity.Concession) (entity.Concession, error) {
+	query := `INSERT INTO concessions (cinema_id, name, category, description, price, stock, is_active)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING id, created_at, updated_at`
+	err := r.DB.QueryRow(ctx, query,
+		c.CinemaID, c.Name, c.Category, c.Description, c.Price, c.Stock, c.IsActive,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// UpdateConcession replaces a concession's details and stock. Bookings that already
+// ordered it keep their price.
+func (r *ConcessionRepo) UpdateConcession(ctx context.Context, c entity.Concession) (entity.Concession, error) {
+	query := `UPDATE concessions SET name = $1, category = $2, description = $3, price = $4, stock = $5,
+			  is_active = $6, updated_at = NOW()
+			  WHERE id = $7
+			  RETURNING cinema_id, created_at, updated_at`
+	err := r.DB.QueryRow(ctx, query,
+		c.Name, c.Category, c.Description, c.Price, c.Stock, c.IsActive, c.ID,
+	).Scan(&c.CinemaID, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// takeConcessions takes the ordered quantities off the stock of concessions sold by the
+// showtime's cinema and returns the items with their name and current price. The
+// conditional update makes racing bookings wait on each other, so stock never goes
+// negative; it returns ErrConcessionUnavailable when an item is not on sale there or
+// does not have enough left.
+func takeConcessions(ctx context.Context, tx pgx.Tx, showtimeID int, items []entity.BookingConcession) ([]entity.BookingConcession, error) {
+	ids := make([]int, len(items))
+	quantities := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ConcessionID
+		quantities[i] = item.Quantity
+	}
+
+	query := `UPDATE concessions c SET stock = c.stock - i.quantity
+			  FROM unnest($2::int[], $3::int[]) AS i(id, quantity), showtimes st
+			  WHERE st.id = $1 AND c.id = i.id AND c.cinema_id = st.cinema_id
+			  AND c.is_active AND c.stock >= i.quantity
+			  RETURNING c.id, c.name, c.price`
+	rows, err := tx.Query(ctx, query, showtimeID, ids, quantities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type priced struct {
+		name  string
+		price float64
+	}
+	taken := make(map[int]priced, len(items))
+	for rows.Next() {
+		var id int
+		var p priced
+		if err := rows.Scan(&id, &p.name, &p.price); err != nil {
+			return nil, err
+		}
+		taken[id] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.BookingConcession, len(items))
+	for i, item := range items {
+		p, ok := taken[item.ConcessionID]
+		if !ok {
+			return nil, ErrConcessionUnavailable
+		}
+		item.Name = p.name
+		item.PriceSnapshot = p.price
+		result[i] = item
+	}
+	return result, nil
+}
+
+// releaseConcessions puts the concessions ordered by cancelled or expired bookings back in stock
+func releaseConcessions(ctx context.Context, tx pgx.Tx, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+
+	query := `UPDATE concessions c SET stock = c.stock + bc.quantity
+			  FROM (
+			  		SELECT concession_id, SUM(quantity) AS quantity FROM booking_concessions
+			  		WHERE booking_id = ANY($1) GROUP BY concession_id
+			  ) bc
+			  WHERE c.id = bc.concession_id`
+	_, err := tx.Exec(ctx, query, bookingIDs)
+	return err
+}
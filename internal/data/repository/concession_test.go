@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var concessionTestColumns = []string{
+	"id", "cinema_id", "name", "category", "description", "price", "stock", "is_active", "created_at", "updated_at",
+}
+
+// expectReleaseConcessions expects the statement that puts the concessions of cancelled
+// bookings back in stock
+func expectReleaseConcessions(mock pgxmock.PgxPoolIface, bookingIDs []int) {
+	mock.ExpectExec("UPDATE concessions c SET stock = c.stock \\+ bc.quantity").
+		WithArgs(bookingIDs).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+}
+
+func TestConcessionRepo_GetConcessionsByCinema(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConcessionRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("SELECT (.+) FROM concessions WHERE cinema_id = (.+) ORDER BY category, name, id").
+			WithArgs(1, true).
+			WillReturnRows(pgxmock.NewRows(concessionTestColumns).
+				AddRow(2, 1, "Popcorn Combo", "combo", "Large popcorn and a soda", 65000.0, 40, true, now, now).
+				AddRow(1, 1, "Caramel Popcorn", "popcorn", "", 45000.0, 0, true, now, now))
+
+		concessions, err := repo.GetConcessionsByCinema(context.Background(), 1, true)
+		assert.NoError(t, err)
+		assert.Len(t, concessions, 2)
+		assert.Equal(t, "Popcorn Combo", concessions[0].Name)
+		assert.Equal(t, 0, concessions[1].Stock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM concessions").
+			WithArgs(1, false).
+			WillReturnError(errors.New("database error"))
+
+		concessions, err := repo.GetConcessionsByCinema(context.Background(), 1, false)
+		assert.Error(t, err)
+		assert.Nil(t, concessions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConcessionRepo_CreateConcession(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConcessionRepo(mock)
+	now := time.Now()
+	concession := entity.Concession{
+		CinemaID: 1, Name: "Mineral Water", Category: entity.ConcessionDrink, Price: 10000, Stock: 100, IsActive: true,
+	}
+
+	mock.ExpectQuery("INSERT INTO concessions (.+) RETURNING id, created_at, updated_at").
+		WithArgs(1, "Mineral Water", "drink", "", 10000.0, 100, true).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(5, now, now))
+
+	created, err := repo.CreateConcession(context.Background(), concession)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, created.ID)
+	assert.Equal(t, now, created.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConcessionRepo_UpdateConcession(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConcessionRepo(mock)
+	concession := entity.Concession{
+		ID: 5, Name: "Mineral Water", Category: entity.ConcessionDrink, Price: 12000, Stock: 80, IsActive: false,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("UPDATE concessions SET name = (.+) WHERE id = (.+) RETURNING cinema_id").
+			WithArgs("Mineral Water", "drink", "", 12000.0, 80, false, 5).
+			WillReturnRows(pgxmock.NewRows([]string{"cinema_id", "created_at", "updated_at"}).AddRow(1, now, now))
+
+		updated, err := repo.UpdateConcession(context.Background(), concession)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, updated.CinemaID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE concessions SET name").
+			WithArgs("Mineral Water", "drink", "", 12000.0, 80, false, 5).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.UpdateConcession(context.Background(), concession)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
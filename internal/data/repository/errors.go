@@ -0,0 +1,59 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrSeatTaken is returned when a seat is already held by another active booking for the same showtime
+	ErrSeatTaken = errors.New("one or more seats are not available")
+
+	// ErrBookingStatusChanged is returned when a booking no longer has the status an update expected
+	ErrBookingStatusChanged = errors.New("booking status has changed")
+
+	// ErrPaymentNotFound is returned when no payment matches a provider transaction
+	ErrPaymentNotFound = errors.New("payment not found")
+
+	// ErrPaymentInProgress is returned when a booking already has a pending or completed payment
+	ErrPaymentInProgress = errors.New("payment already in progress")
+
+	// ErrWebhookDuplicate is returned when a provider transaction has already been settled
+	ErrWebhookDuplicate = errors.New("webhook already processed")
+
+	// ErrMovieHasShowtimes is returned when deleting a movie that still has upcoming showtimes
+	ErrMovieHasShowtimes = errors.New("movie still has upcoming showtimes")
+
+	// ErrSeatsInUse is returned when a layout change would remove seats referenced by bookings
+	ErrSeatsInUse = errors.New("seats are referenced by existing bookings")
+
+	// ErrSeatNotInStudio is returned when a requested seat does not belong to the showtime's studio
+	ErrSeatNotInStudio = errors.New("one or more seats do not belong to the showtime's studio")
+
+	// ErrDuplicateSeat is returned when a booking lists the same seat more than once
+	ErrDuplicateSeat = errors.New("seat_ids must not contain the same seat twice")
+
+	// ErrShowtimeConflict is returned when a showtime overlaps another one in the same studio
+	ErrShowtimeConflict = errors.New("studio is already booked for another showtime")
+
+	// ErrPromoUnavailable is returned when a promotion is inactive or outside its validity window
+	ErrPromoUnavailable = errors.New("promo code is not active")
+
+	// ErrPromoExhausted is returned when a promotion has been redeemed as often as it allows
+	ErrPromoExhausted = errors.New("promo code has reached its usage limit")
+
+	// ErrPromoUserLimit is returned when a user has redeemed a promotion as often as it allows per user
+	ErrPromoUserLimit = errors.New("promo code has already been used the maximum number of times by this user")
+
+	// ErrPromoCodeExists is returned when creating or renaming a promotion to a code that is taken
+	ErrPromoCodeExists = errors.New("promo code already exists")
+
+	// ErrConcessionUnavailable is returned when an ordered concession is not sold by the showtime's cinema or is out of stock
+	ErrConcessionUnavailable = errors.New("one or more concessions are unavailable or out of stock")
+
+	// ErrTicketAlreadyAdmitted is returned when admitting a ticket that was already scanned or whose booking is no longer paid
+	ErrTicketAlreadyAdmitted = errors.New("ticket has already been admitted")
+
+	// ErrInsufficientPoints is returned when a user redeems more loyalty points than they have spendable
+	ErrInsufficientPoints = errors.New("not enough loyalty points")
+
+	// ErrReviewExists is returned when a user reviews a movie they have already reviewed
+	ErrReviewExists = errors.New("movie has already been reviewed by this user")
+)
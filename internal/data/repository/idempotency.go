@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyRepoInterface interface {
+	ReserveIdempotencyKey(ctx context.Context, key entity.IdempotencyKey, expiredBefore time.Time) (entity.IdempotencyKey, bool, error)
+	SaveIdempotencyResponse(ctx context.Context, id, statusCode int, body []byte) error
+	DeleteIdempotencyKey(ctx context.Context, id int) error
+}
+
+type IdempotencyRepo struct {
+	DB DBPool
+}
+
+func NewIdempotencyRepo(db DBPool) IdempotencyRepoInterface {
+	return &IdempotencyRepo{DB: db}
+}
+
+// ReserveIdempotencyKey claims a key for the user. A row created before expiredBefore is
+// taken over as if it did not exist. When the key is already held, the stored row is
+// returned with reserved set to false.
+func (r *IdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, key entity.IdempotencyKey, expiredBefore time.Time) (entity.IdempotencyKey, bool, error) {
+	query := `INSERT INTO idempotency_keys (user_id, idem_key, request_hash) 
+			  VALUES ($1, $2, $3) 
+			  ON CONFLICT (user_id, idem_key) DO UPDATE SET request_hash = EXCLUDED.request_hash, 
+			  status_code = NULL, response_body = NULL, created_at = NOW(), completed_at = NULL 
+			  WHERE idempotency_keys.created_at < $4 
+			  RETURNING id, created_at`
+	err := r.DB.QueryRow(ctx, query, key.UserID, key.Key, key.RequestHash, expiredBefore).Scan(&key.ID, &key.CreatedAt)
+	if err == nil {
+		return key, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return key, false, err
+	}
+
+	// Key is held by an earlier request, load it for comparison or replay
+	existingQuery := `SELECT id, user_id, idem_key, request_hash, COALESCE(status_code, 0), response_body, created_at, completed_at 
+					  FROM idempotency_keys WHERE user_id = $1 AND idem_key = $2`
+	var existing entity.IdempotencyKey
+	err = r.DB.QueryRow(ctx, existingQuery, key.UserID, key.Key).Scan(
+		&existing.ID, &existing.UserID, &existing.Key, &existing.RequestHash, &existing.StatusCode,
+		&existing.ResponseBody, &existing.CreatedAt, &existing.CompletedAt,
+	)
+	if err != nil {
+		return existing, false, err
+	}
+	return existing, false, nil
+}
+
+// SaveIdempotencyResponse stores the response produced for a reserved key
+func (r *IdempotencyRepo) SaveIdempotencyResponse(ctx context.Context, id, statusCode int, body []byte) error {
+	query := `UPDATE idempotency_keys SET status_code = $1, response_body = $2, completed_at = NOW() WHERE id = $3`
+	_, err := r.DB.Exec(ctx, query, statusCode, body, id)
+	return err
+}
+
+// DeleteIdempotencyKey releases a reserved key so the request can be retried
+func (r *IdempotencyRepo) DeleteIdempotencyKey(ctx context.Context, id int) error {
+	query := `DELETE FROM idempotency_keys WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepo_ReserveIdempotencyKey(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewIdempotencyRepo(mock)
+	expiredBefore := time.Now().Add(-24 * time.Hour)
+	key := entity.IdempotencyKey{UserID: 1, Key: "key-1", RequestHash: "hash-1"}
+
+	t.Run("Success - New Key Reserved", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_keys").
+			WithArgs(1, "key-1", "hash-1", expiredBefore).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(5, time.Now()))
+
+		record, reserved, err := repo.ReserveIdempotencyKey(context.Background(), key, expiredBefore)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.Equal(t, 5, record.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Existing Key Returned", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("INSERT INTO idempotency_keys").
+			WithArgs(1, "key-1", "hash-1", expiredBefore).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("SELECT (.+) FROM idempotency_keys WHERE user_id").
+			WithArgs(1, "key-1").
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "user_id", "idem_key", "request_hash", "status_code", "response_body", "created_at", "completed_at",
+			}).AddRow(5, 1, "key-1", "hash-1", 201, []byte(`{"status":true}`), now, &now))
+
+		record, reserved, err := repo.ReserveIdempotencyKey(context.Background(), key, expiredBefore)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, 201, record.StatusCode)
+		assert.Equal(t, `{"status":true}`, string(record.ResponseBody))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_keys").
+			WithArgs(1, "key-1", "hash-1", expiredBefore).
+			WillReturnError(errors.New("database error"))
+
+		_, reserved, err := repo.ReserveIdempotencyKey(context.Background(), key, expiredBefore)
+		assert.Error(t, err)
+		assert.False(t, reserved)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestIdempotencyRepo_SaveIdempotencyResponse(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewIdempotencyRepo(mock)
+	body := []byte(`{"status":true}`)
+
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code").
+		WithArgs(201, body, 5).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.SaveIdempotencyResponse(context.Background(), 5, 201, body)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepo_DeleteIdempotencyKey(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewIdempotencyRepo(mock)
+
+	mock.ExpectExec("DELETE FROM idempotency_keys").
+		WithArgs(5).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	err = repo.DeleteIdempotencyKey(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
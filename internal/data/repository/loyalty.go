@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type LoyaltyRepoInterface interface {
+	GetPointsBalance(ctx context.Context, userID int) (int, error)
+	GetLedger(ctx context.Context, userID, page, limit int) ([]entity.LoyaltyEntry, dto.Pagination, error)
+	EarnPoints(ctx context.Context, bookingID int, amountPerPoint float64, expiryDays int) (int, error)
+}
+
+type LoyaltyRepo struct {
+	DB DBPool
+}
+
+func NewLoyaltyRepo(db DBPool) LoyaltyRepoInterface {
+	return &LoyaltyRepo{DB: db}
+}
+
+// GetPointsBalance sums what is left of a user's earned points that have not expired,
+// less the points still owed from reversed bookings, so it can be negative
+func (r *LoyaltyRepo) GetPointsBalance(ctx context.Context, userID int) (int, error) {
+	query := `SELECT COALESCE(SUM(CASE WHEN entry_type = 'debt' THEN -remaining ELSE remaining END), 0) FROM loyalty_ledger
+			  WHERE user_id = $1 AND ((entry_type = 'earn' AND expires_at > NOW()) OR entry_type = 'debt')`
+
+	var balance int
+	if err := r.DB.QueryRow(ctx, query, userID).Scan(&balance); err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// GetLedger retrieves a user's loyalty ledger, newest first, with pagination
+func (r *LoyaltyRepo) GetLedger(ctx context.Context, userID, page, limit int) ([]entity.LoyaltyEntry, dto.Pagination, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM loyalty_ledger WHERE user_id = $1`
+	if err := r.DB.QueryRow(ctx, countQuery, userID).Scan(&total); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	query := `SELECT id, user_id, booking_id, entry_type, points, remaining, lot_id, expires_at, created_at
+			  FROM loyalty_ledger
+			  WHERE user_id = $1
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $2 OFFSET $3`
+	rows, err := r.DB.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	defer rows.Close()
+
+	var entries []entity.LoyaltyEntry
+	for rows.Next() {
+		var e entity.LoyaltyEntry
+		if err := rows.Scan(
+			&e.ID, &e.UserID, &e.BookingID, &e.EntryType, &e.Points, &e.Remaining, &e.LotID, &e.ExpiresAt, &e.CreatedAt,
+		); err != nil {
+			return nil, dto.Pagination{}, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	pagination := dto.Pagination{
+		CurrentPage:  page,
+		TotalPages:   (total + limit - 1) / limit,
+		TotalRecords: total,
+		Limit:        limit,
+	}
+
+	return entries, pagination, nil
+}
+
+// EarnPoints credits a paid booking's user one point per amountPerPoint paid, expiring
+// after expiryDays. A booking earns once; it returns the points credited, 0 when the
+// booking is not paid, earns nothing or was already credited.
+func (r *LoyaltyRepo) EarnPoints(ctx context.Context, bookingID int, amountPerPoint float64, expiryDays int) (int, error) {
+	// FOR SHARE keeps a concurrent cancellation from reversing before the points exist
+	query := `WITH b AS (
+				SELECT id, user_id, FLOOR(total_amount / $2)::int AS points
+				FROM bookings WHERE id = $1 AND status = 'paid'
+				FOR SHARE
+			  )
+			  INSERT INTO loyalty_ledger (user_id, booking_id, entry_type, points, remaining, expires_at)
+			  SELECT user_id, id, 'earn', points, points, NOW() + make_interval(days => $3)
+			  FROM b WHERE points > 0
+			  ON CONFLICT (booking_id) WHERE entry_type = 'earn' DO NOTHING
+			  RETURNING points`
+
+	var points int
+	err := r.DB.QueryRow(ctx, query, bookingID, amountPerPoint, expiryDays).Scan(&points)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+// lotTake is how many points to take from one earned lot
+type lotTake struct {
+	lotID  int
+	points int
+}
+
+// takeLots picks up to points from a user's unexpired lots, earliest expiry first, and
+// locks them. It returns the picks and how many points they fall short by.
+func takeLots(ctx context.Context, tx pgx.Tx, userID, points int) ([]lotTake, int, error) {
+	lotQuery := `SELECT id, remaining FROM loyalty_ledger
+				 WHERE user_id = $1 AND entry_type = 'earn' AND remaining > 0 AND expires_at > NOW()
+				 ORDER BY expires_at, id
+				 FOR UPDATE`
+	rows, err := tx.Query(ctx, lotQuery, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var takes []lotTake
+	needed := points
+	for rows.Next() && needed > 0 {
+		var lotID, remaining int
+		if err := rows.Scan(&lotID, &remaining); err != nil {
+			return nil, 0, err
+		}
+		take := min(remaining, needed)
+		takes = append(takes, lotTake{lotID: lotID, points: take})
+		needed -= take
+	}
+	rows.Close()
+	return takes, needed, rows.Err()
+}
+
+// takeFromLot moves points out of a lot, writing an entry of entryType for the booking
+func takeFromLot(ctx context.Context, tx pgx.Tx, entryType string, userID, bookingID int, take lotTake) error {
+	query := `WITH lot AS (
+				UPDATE loyalty_ledger SET remaining = remaining - $3 WHERE id = $4 RETURNING id
+			  )
+			  INSERT INTO loyalty_ledger (user_id, booking_id, entry_type, points, lot_id)
+			  SELECT $1, $2, $5, -$3::int, id FROM lot`
+	_, err := tx.Exec(ctx, query, userID, bookingID, take.points, take.lotID, entryType)
+	return err
+}
+
+// redeemPoints spends points for a booking from the user's unexpired lots, earliest
+// expiry first, writing one redeem entry per lot. Points the user still owes from
+// reversed bookings are settled from the same lots first. It returns
+// ErrInsufficientPoints when the lots do not cover both.
+func redeemPoints(ctx context.Context, tx pgx.Tx, userID, bookingID, points int) error {
+	debtQuery := `SELECT id, remaining FROM loyalty_ledger
+				  WHERE user_id = $1 AND entry_type = 'debt' AND remaining > 0
+				  FOR UPDATE`
+	rows, err := tx.Query(ctx, debtQuery, userID)
+	if err != nil {
+		return err
+	}
+	var debtIDs []int
+	var owed int
+	for rows.Next() {
+		var debtID, remaining int
+		if err := rows.Scan(&debtID, &remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		debtIDs = append(debtIDs, debtID)
+		owed += remaining
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	takes, short, err := takeLots(ctx, tx, userID, owed+points)
+	if err != nil {
+		return err
+	}
+	if short > 0 {
+		return ErrInsufficientPoints
+	}
+
+	settleQuery := `UPDATE loyalty_ledger SET remaining = remaining - $2 WHERE id = $1`
+	for _, take := range takes {
+		if settled := min(take.points, owed); settled > 0 {
+			if _, err := tx.Exec(ctx, settleQuery, take.lotID, settled); err != nil {
+				return err
+			}
+			owed -= settled
+			take.points -= settled
+		}
+		if take.points == 0 {
+			continue
+		}
+		if err := takeFromLot(ctx, tx, "redeem", userID, bookingID, take); err != nil {
+			return err
+		}
+	}
+
+	if len(debtIDs) > 0 {
+		clearQuery := `UPDATE loyalty_ledger SET remaining = 0 WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, clearQuery, debtIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reverseLoyalty undoes the loyalty movements of cancelled or expired bookings. Points
+// they redeemed go back to their lots; a lot that has expired meanwhile keeps only the
+// returned points, spendable for the lot's original validity counted from their return.
+// All of the points they earned are taken back: what is left of each earned lot is
+// revoked and what was already spent is taken from the user's other unexpired lots,
+// earliest expiry first. Whatever those do not cover is recorded as a debt against the
+// balance, settled from the next points redeemed.
+func reverseLoyalty(ctx context.Context, tx pgx.Tx, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+
+	restoreQuery := `WITH redeemed AS (
+						SELECT user_id, booking_id, lot_id, -SUM(points)::int AS points
+						FROM loyalty_ledger
+						WHERE booking_id = ANY($1) AND entry_type = 'redeem'
+						GROUP BY user_id, booking_id, lot_id
+					 ), restored AS (
+						UPDATE loyalty_ledger l SET
+							remaining = CASE WHEN l.expires_at > NOW() THEN l.remaining + r.points ELSE r.points END,
+							expires_at = CASE WHEN l.expires_at > NOW() THEN l.expires_at ELSE NOW() + (l.expires_at - l.created_at) END
+						FROM (SELECT lot_id, SUM(points) AS points FROM redeemed GROUP BY lot_id) r
+						WHERE l.id = r.lot_id
+					 )
+					 INSERT INTO loyalty_ledger (user_id, booking_id, entry_type, points, lot_id)
+					 SELECT user_id, booking_id, 'reversal', points, lot_id FROM redeemed`
+	if _, err := tx.Exec(ctx, restoreQuery, bookingIDs); err != nil {
+		return err
+	}
+
+	// Revoked lots are left with nothing, so the spent part is never taken from them
+	revokeQuery := `WITH lots AS (
+						SELECT id, user_id, booking_id, points, remaining FROM loyalty_ledger
+						WHERE booking_id = ANY($1) AND entry_type = 'earn'
+						FOR UPDATE
+					), revoked AS (
+						UPDATE loyalty_ledger l SET remaining = 0
+						FROM lots
+						WHERE l.id = lots.id
+						RETURNING lots.id, lots.user_id, lots.booking_id, lots.points, lots.remaining
+					), logged AS (
+						INSERT INTO loyalty_ledger (user_id, booking_id, entry_type, points, lot_id)
+						SELECT user_id, booking_id, 'reversal', -remaining, id FROM revoked WHERE remaining > 0
+					)
+					SELECT id, user_id, booking_id, points - remaining FROM revoked
+					WHERE points > remaining ORDER BY id`
+	rows, err := tx.Query(ctx, revokeQuery, bookingIDs)
+	if err != nil {
+		return err
+	}
+	type spentLot struct {
+		lotID, userID, bookingID, spent int
+	}
+	var spentLots []spentLot
+	for rows.Next() {
+		var sl spentLot
+		if err := rows.Scan(&sl.lotID, &sl.userID, &sl.bookingID, &sl.spent); err != nil {
+			rows.Close()
+			return err
+		}
+		spentLots = append(spentLots, sl)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	debtQuery := `INSERT INTO loyalty_ledger (user_id, booking_id, entry_type, points, remaining, lot_id)
+				  VALUES ($1, $2, 'debt', -$3::int, $3, $4)`
+	for _, sl := range spentLots {
+		takes, short, err := takeLots(ctx, tx, sl.userID, sl.spent)
+		if err != nil {
+			return err
+		}
+		for _, take := range takes {
+			if err := takeFromLot(ctx, tx, "reversal", sl.userID, sl.bookingID, take); err != nil {
+				return err
+			}
+		}
+		if short > 0 {
+			if _, err := tx.Exec(ctx, debtQuery, sl.userID, sl.bookingID, short, sl.lotID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
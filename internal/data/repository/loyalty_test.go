@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectReverseLoyalty expects the statements that return redeemed points and revoke
+// earned points of cancelled bookings
+func expectReverseLoyalty(mock pgxmock.PgxPoolIface, bookingIDs []int) {
+	mock.ExpectExec("WITH redeemed AS (.+) entry_type = 'redeem'").
+		WithArgs(bookingIDs).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectQuery("WITH lots AS (.+) entry_type = 'earn'").
+		WithArgs(bookingIDs).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "booking_id", "spent"}))
+}
+
+func TestLoyaltyRepo_GetPointsBalance(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewLoyaltyRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN entry_type = 'debt' THEN -remaining ELSE remaining END\\), 0\\) FROM loyalty_ledger (.+) expires_at > NOW\\(\\)\\) OR entry_type = 'debt'").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"balance"}).AddRow(450))
+
+		balance, err := repo.GetPointsBalance(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 450, balance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COALESCE").
+			WithArgs(1).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.GetPointsBalance(context.Background(), 1)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLoyaltyRepo_GetLedger(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewLoyaltyRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		expires := now.AddDate(1, 0, 0)
+		bookingID, lotID := 7, 2
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM loyalty_ledger WHERE user_id").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery("SELECT (.+) FROM loyalty_ledger WHERE user_id = (.+) ORDER BY created_at DESC").
+			WithArgs(1, 10, 0).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "user_id", "booking_id", "entry_type", "points", "remaining", "lot_id", "expires_at", "created_at",
+			}).
+				AddRow(3, 1, &bookingID, "redeem", -100, 0, &lotID, nil, now).
+				AddRow(2, 1, nil, "earn", 250, 150, nil, &expires, now))
+
+		entries, pagination, err := repo.GetLedger(context.Background(), 1, 1, 10)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, -100, entries[0].Points)
+		assert.Equal(t, &lotID, entries[0].LotID)
+		assert.Equal(t, 150, entries[1].Remaining)
+		assert.Equal(t, 2, pagination.TotalRecords)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLoyaltyRepo_EarnPoints(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewLoyaltyRepo(mock)
+
+	t.Run("Success - Points Credited", func(t *testing.T) {
+		mock.ExpectQuery("WITH b AS (.+) status = 'paid' FOR SHARE (.+) INSERT INTO loyalty_ledger (.+) ON CONFLICT").
+			WithArgs(5, 1000.0, 365).
+			WillReturnRows(pgxmock.NewRows([]string{"points"}).AddRow(125))
+
+		points, err := repo.EarnPoints(context.Background(), 5, 1000, 365)
+		assert.NoError(t, err)
+		assert.Equal(t, 125, points)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Already Credited Or Not Paid", func(t *testing.T) {
+		mock.ExpectQuery("WITH b AS").
+			WithArgs(5, 1000.0, 365).
+			WillReturnError(pgx.ErrNoRows)
+
+		points, err := repo.EarnPoints(context.Background(), 5, 1000, 365)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, points)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("WITH b AS").
+			WithArgs(5, 1000.0, 365).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.EarnPoints(context.Background(), 5, 1000, 365)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReverseLoyalty(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	lotColumns := []string{"id", "remaining"}
+
+	t.Run("Success - Spent Points Are Taken Back From Other Lots", func(t *testing.T) {
+		// Booking 3 earned 100 points on lot 2 and 60 of them were spent elsewhere; the
+		// user's other lot 5 covers 40 of those and the last 20 are owed
+		mock.ExpectBegin()
+		mock.ExpectExec("WITH redeemed AS (.+) entry_type = 'redeem'").
+			WithArgs([]int{3}).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectQuery("WITH lots AS (.+) UPDATE loyalty_ledger l SET remaining = 0 (.+) SELECT id, user_id, booking_id, points - remaining FROM revoked").
+			WithArgs([]int{3}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "booking_id", "spent"}).AddRow(2, 1, 3, 60))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger(.+) ORDER BY expires_at, id FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(5, 40))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = remaining - (.+) INSERT INTO loyalty_ledger").
+			WithArgs(1, 3, 40, 5, "reversal").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO loyalty_ledger (.+) VALUES (.+) 'debt'").
+			WithArgs(1, 3, 20, 2).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectRollback()
+
+		tx, err := mock.BeginTx(context.Background(), pgx.TxOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = reverseLoyalty(context.Background(), tx, []int{3})
+		assert.NoError(t, err)
+		tx.Rollback(context.Background())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Debt Is Settled Before Redeeming", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger (.+) entry_type = 'debt' (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(9, 20))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger(.+) ORDER BY expires_at, id FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(5, 100))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = remaining - \\$2 WHERE id = \\$1").
+			WithArgs(5, 20).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = remaining - (.+) INSERT INTO loyalty_ledger").
+			WithArgs(1, 7, 50, 5, "redeem").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("UPDATE loyalty_ledger SET remaining = 0 WHERE id = ANY").
+			WithArgs([]int{9}).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectRollback()
+
+		tx, err := mock.BeginTx(context.Background(), pgx.TxOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = redeemPoints(context.Background(), tx, 1, 7, 50)
+		assert.NoError(t, err)
+		tx.Rollback(context.Background())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Debt Counts Against Redemption", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger (.+) entry_type = 'debt'").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(9, 60))
+		mock.ExpectQuery("SELECT id, remaining FROM loyalty_ledger(.+) ORDER BY expires_at, id FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(lotColumns).AddRow(5, 100))
+		mock.ExpectRollback()
+
+		tx, err := mock.BeginTx(context.Background(), pgx.TxOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = redeemPoints(context.Background(), tx, 1, 7, 50)
+		assert.ErrorIs(t, err, ErrInsufficientPoints)
+		tx.Rollback(context.Background())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
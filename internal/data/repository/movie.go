@@ -2,13 +2,21 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type MovieRepoInterface interface {
-	GetAllMovies(ctx context.Context, page, limit int) ([]entity.Movie, dto.Pagination, error)
+	GetAllMovies(ctx context.Context, filter entity.MovieFilter, page, limit int) ([]entity.Movie, dto.Pagination, error)
 	GetMovieByID(ctx context.Context, id int) (entity.Movie, error)
+	CreateMovie(ctx context.Context, movie entity.Movie) (entity.Movie, error)
+	UpdateMovie(ctx context.Context, movie entity.Movie) (entity.Movie, error)
+	DeleteMovie(ctx context.Context, id int) error
+	GetMovieCredits(ctx context.Context, movieID int) ([]entity.MovieCastMember, []entity.MovieCrewMember, error)
 }
 
 type MovieRepo struct {
@@ -19,25 +27,64 @@ func NewMovieRepo(db DBPool) MovieRepoInterface {
 	return &MovieRepo{DB: db}
 }
 
-// GetAllMovies retrieves all movies with pagination
-func (r *MovieRepo) GetAllMovies(ctx context.Context, page, limit int) ([]entity.Movie, dto.Pagination, error) {
+// movieSortOrders maps each sort option to its ORDER BY clause; id keeps pages stable on ties
+var movieSortOrders = map[string]string{
+	entity.MovieSortNewest:      "created_at DESC, id DESC",
+	entity.MovieSortReleaseDate: "release_date DESC, id DESC",
+	entity.MovieSortRating:      "rating DESC, review_count DESC, id DESC",
+	entity.MovieSortPopular:     "review_count DESC, id DESC",
+	entity.MovieSortTitle:       "title ASC, id ASC",
+}
+
+// likeEscaper escapes LIKE wildcards so a search term matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetAllMovies retrieves movies matching the filter with pagination
+func (r *MovieRepo) GetAllMovies(ctx context.Context, filter entity.MovieFilter, page, limit int) ([]entity.Movie, dto.Pagination, error) {
 	offset := (page - 1) * limit
 
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Search != "" {
+		add("title ILIKE '%%' || $%d || '%%'", likeEscaper.Replace(filter.Search))
+	}
+	if len(filter.Genres) > 0 {
+		add("genres @> $%d::text[]", filter.Genres)
+	}
+	if filter.ReleaseStatus != "" {
+		add("release_status = $%d", filter.ReleaseStatus)
+	}
+	if filter.MinRating > 0 {
+		add("rating >= $%d", filter.MinRating)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	orderBy, ok := movieSortOrders[filter.Sort]
+	if !ok {
+		orderBy = movieSortOrders[entity.MovieSortNewest]
+	}
+
 	// Get total count
 	var total int
-	countQuery := `SELECT COUNT(*) FROM movies`
-	if err := r.DB.QueryRow(ctx, countQuery).Scan(&total); err != nil {
+	countQuery := `SELECT COUNT(*) FROM movies WHERE ` + where
+	if err := r.DB.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, dto.Pagination{}, err
 	}
 
 	// Get paginated movies
-	query := `SELECT id, title, poster_url, genres, rating, review_count, 
-			  release_date, duration_in_minutes, release_status, created_at, updated_at
+	query := fmt.Sprintf(`SELECT id, title, poster_url, genres, rating, review_count, 
+			  release_date, duration_in_minutes, release_status, synopsis, age_rating, 
+			  language, trailer_url, created_at, updated_at
 			  FROM movies 
-			  ORDER BY created_at DESC
-			  LIMIT $1 OFFSET $2`
+			  WHERE %s
+			  ORDER BY %s
+			  LIMIT $%d OFFSET $%d`, where, orderBy, len(args)+1, len(args)+2)
 
-	rows, err := r.DB.Query(ctx, query, limit, offset)
+	rows, err := r.DB.Query(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, dto.Pagination{}, err
 	}
@@ -48,7 +95,8 @@ func (r *MovieRepo) GetAllMovies(ctx context.Context, page, limit int) ([]entity
 		var m entity.Movie
 		if err := rows.Scan(
 			&m.ID, &m.Title, &m.PosterURL, &m.Genres, &m.Rating, &m.ReviewCount,
-			&m.ReleaseDate, &m.DurationMinutes, &m.ReleaseStatus, &m.CreatedAt, &m.UpdatedAt,
+			&m.ReleaseDate, &m.DurationMinutes, &m.ReleaseStatus, &m.Synopsis, &m.AgeRating,
+			&m.Language, &m.TrailerURL, &m.CreatedAt, &m.UpdatedAt,
 		); err != nil {
 			return nil, dto.Pagination{}, err
 		}
@@ -68,16 +116,187 @@ func (r *MovieRepo) GetAllMovies(ctx context.Context, page, limit int) ([]entity
 // GetMovieByID retrieves a movie by ID
 func (r *MovieRepo) GetMovieByID(ctx context.Context, id int) (entity.Movie, error) {
 	query := `SELECT id, title, poster_url, genres, rating, review_count, 
-			  release_date, duration_in_minutes, release_status, created_at, updated_at
-			  FROM movies WHERE id = $1`
+			  release_date, duration_in_minutes, release_status, synopsis, age_rating, 
+			  language, trailer_url, created_at, updated_at
+			  FROM movies WHERE id = $1 AND deleted_at IS NULL`
 
 	var m entity.Movie
 	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&m.ID, &m.Title, &m.PosterURL, &m.Genres, &m.Rating, &m.ReviewCount,
-		&m.ReleaseDate, &m.DurationMinutes, &m.ReleaseStatus, &m.CreatedAt, &m.UpdatedAt,
+		&m.ReleaseDate, &m.DurationMinutes, &m.ReleaseStatus, &m.Synopsis, &m.AgeRating,
+		&m.Language, &m.TrailerURL, &m.CreatedAt, &m.UpdatedAt,
 	)
 	if err != nil {
 		return m, err
 	}
 	return m, nil
 }
+
+// CreateMovie inserts a new movie together with its cast and crew
+func (r *MovieRepo) CreateMovie(ctx context.Context, movie entity.Movie) (entity.Movie, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return movie, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO movies (title, poster_url, genres, release_date, duration_in_minutes, release_status, 
+			  synopsis, age_rating, language, trailer_url) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+			  RETURNING id, rating, review_count, created_at, updated_at`
+	err = tx.QueryRow(ctx, query,
+		movie.Title, movie.PosterURL, movie.Genres, movie.ReleaseDate, movie.DurationMinutes, movie.ReleaseStatus,
+		movie.Synopsis, movie.AgeRating, movie.Language, movie.TrailerURL,
+	).Scan(&movie.ID, &movie.Rating, &movie.ReviewCount, &movie.CreatedAt, &movie.UpdatedAt)
+	if err != nil {
+		return movie, err
+	}
+
+	if err := replaceMovieCredits(ctx, tx, movie); err != nil {
+		return movie, err
+	}
+
+	return movie, tx.Commit(ctx)
+}
+
+// UpdateMovie replaces the editable fields and credits of a movie and refreshes updated_at
+func (r *MovieRepo) UpdateMovie(ctx context.Context, movie entity.Movie) (entity.Movie, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return movie, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE movies SET title = $1, poster_url = $2, genres = $3, release_date = $4, 
+			  duration_in_minutes = $5, release_status = $6, synopsis = $7, age_rating = $8, 
+			  language = $9, trailer_url = $10, updated_at = NOW() 
+			  WHERE id = $11 AND deleted_at IS NULL 
+			  RETURNING rating, review_count, created_at, updated_at`
+	err = tx.QueryRow(ctx, query,
+		movie.Title, movie.PosterURL, movie.Genres, movie.ReleaseDate, movie.DurationMinutes, movie.ReleaseStatus,
+		movie.Synopsis, movie.AgeRating, movie.Language, movie.TrailerURL, movie.ID,
+	).Scan(&movie.Rating, &movie.ReviewCount, &movie.CreatedAt, &movie.UpdatedAt)
+	if err != nil {
+		return movie, err
+	}
+
+	if err := replaceMovieCredits(ctx, tx, movie); err != nil {
+		return movie, err
+	}
+
+	return movie, tx.Commit(ctx)
+}
+
+// replaceMovieCredits swaps the stored cast and crew of a movie for the ones it carries
+func replaceMovieCredits(ctx context.Context, tx pgx.Tx, movie entity.Movie) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM movie_cast WHERE movie_id = $1`, movie.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM movie_crew WHERE movie_id = $1`, movie.ID); err != nil {
+		return err
+	}
+
+	if len(movie.Cast) > 0 {
+		names := make([]string, len(movie.Cast))
+		characters := make([]string, len(movie.Cast))
+		orders := make([]int, len(movie.Cast))
+		for i, c := range movie.Cast {
+			names[i], characters[i], orders[i] = c.Name, c.CharacterName, c.BillingOrder
+		}
+		castQuery := `INSERT INTO movie_cast (movie_id, name, character_name, billing_order) 
+					  SELECT $1, name, character_name, billing_order 
+					  FROM unnest($2::text[], $3::text[], $4::int[]) AS c(name, character_name, billing_order)`
+		if _, err := tx.Exec(ctx, castQuery, movie.ID, names, characters, orders); err != nil {
+			return err
+		}
+	}
+
+	if len(movie.Crew) > 0 {
+		names := make([]string, len(movie.Crew))
+		jobs := make([]string, len(movie.Crew))
+		for i, c := range movie.Crew {
+			names[i], jobs[i] = c.Name, c.Job
+		}
+		crewQuery := `INSERT INTO movie_crew (movie_id, name, job) 
+					  SELECT $1, name, job FROM unnest($2::text[], $3::text[]) AS c(name, job)`
+		if _, err := tx.Exec(ctx, crewQuery, movie.ID, names, jobs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMovieCredits retrieves the cast of a movie in billing order and its crew
+func (r *MovieRepo) GetMovieCredits(ctx context.Context, movieID int) ([]entity.MovieCastMember, []entity.MovieCrewMember, error) {
+	castQuery := `SELECT id, movie_id, name, character_name, billing_order 
+				  FROM movie_cast WHERE movie_id = $1 ORDER BY billing_order, id`
+	rows, err := r.DB.Query(ctx, castQuery, movieID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var cast []entity.MovieCastMember
+	for rows.Next() {
+		var c entity.MovieCastMember
+		if err := rows.Scan(&c.ID, &c.MovieID, &c.Name, &c.CharacterName, &c.BillingOrder); err != nil {
+			return nil, nil, err
+		}
+		cast = append(cast, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	rows.Close()
+
+	crewQuery := `SELECT id, movie_id, name, job FROM movie_crew WHERE movie_id = $1 ORDER BY id`
+	rows, err = r.DB.Query(ctx, crewQuery, movieID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var crew []entity.MovieCrewMember
+	for rows.Next() {
+		var c entity.MovieCrewMember
+		if err := rows.Scan(&c.ID, &c.MovieID, &c.Name, &c.Job); err != nil {
+			return nil, nil, err
+		}
+		crew = append(crew, c)
+	}
+
+	return cast, crew, rows.Err()
+}
+
+// DeleteMovie soft deletes a movie, refusing while it still has upcoming showtimes
+func (r *MovieRepo) DeleteMovie(ctx context.Context, id int) error {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the movie so no showtime can be scheduled for it meanwhile
+	var lockedID int
+	lockQuery := `SELECT id FROM movies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.QueryRow(ctx, lockQuery, id).Scan(&lockedID); err != nil {
+		return err
+	}
+
+	var hasShowtimes bool
+	showtimeQuery := `SELECT EXISTS (SELECT 1 FROM showtimes WHERE movie_id = $1 AND status = 'scheduled' AND show_date + show_time > LOCALTIMESTAMP)`
+	if err := tx.QueryRow(ctx, showtimeQuery, id).Scan(&hasShowtimes); err != nil {
+		return err
+	}
+	if hasShowtimes {
+		return ErrMovieHasShowtimes
+	}
+
+	deleteQuery := `UPDATE movies SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1`
+	if _, err := tx.Exec(ctx, deleteQuery, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/dto"
 	"testing"
 	"time"
@@ -32,16 +33,17 @@ func TestMovieRepo_GetAllMovies(t *testing.T) {
 		// Mock movies query
 		rows := pgxmock.NewRows([]string{
 			"id", "title", "poster_url", "genres", "rating", "review_count",
-			"release_date", "duration_in_minutes", "release_status", "created_at", "updated_at",
+			"release_date", "duration_in_minutes", "release_status", "synopsis", "age_rating",
+			"language", "trailer_url", "created_at", "updated_at",
 		}).
-			AddRow(1, "Movie 1", "http://poster1.jpg", []string{"Action"}, 8.5, 100, releaseDate, 120, "now_showing", now, now).
-			AddRow(2, "Movie 2", "http://poster2.jpg", []string{"Comedy"}, 7.0, 50, releaseDate, 90, "coming_soon", now, now)
+			AddRow(1, "Movie 1", "http://poster1.jpg", []string{"Action"}, 8.5, 100, releaseDate, 120, "now_showing", "", "SU", "", "", now, now).
+			AddRow(2, "Movie 2", "http://poster2.jpg", []string{"Comedy"}, 7.0, 50, releaseDate, 90, "coming_soon", "", "SU", "", "", now, now)
 
 		mock.ExpectQuery("SELECT (.+) FROM movies").
 			WithArgs(10, 0).
 			WillReturnRows(rows)
 
-		movies, pagination, err := repo.GetAllMovies(context.Background(), 1, 10)
+		movies, pagination, err := repo.GetAllMovies(context.Background(), entity.MovieFilter{}, 1, 10)
 		assert.NoError(t, err)
 		assert.Len(t, movies, 2)
 		assert.Equal(t, "Movie 1", movies[0].Title)
@@ -50,10 +52,44 @@ func TestMovieRepo_GetAllMovies(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("Success - Filters Apply To Count And Page", func(t *testing.T) {
+		now := time.Now()
+		filter := entity.MovieFilter{
+			Search:        "100%",
+			Genres:        []string{"Action", "Sci-Fi"},
+			ReleaseStatus: "now_playing",
+			MinRating:     4.5,
+			Sort:          entity.MovieSortRating,
+		}
+		where := `WHERE deleted_at IS NULL AND title ILIKE '%' \|\| \$1 \|\| '%' AND genres @> \$2::text\[\] AND release_status = \$3 AND rating >= \$4`
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM movies "+where).
+			WithArgs(`100\%`, []string{"Action", "Sci-Fi"}, "now_playing", 4.5).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(11))
+
+		rows := pgxmock.NewRows([]string{
+			"id", "title", "poster_url", "genres", "rating", "review_count",
+			"release_date", "duration_in_minutes", "release_status", "synopsis", "age_rating",
+			"language", "trailer_url", "created_at", "updated_at",
+		}).
+			AddRow(3, "100% Action", "http://poster3.jpg", []string{"Action", "Sci-Fi"}, 4.9, 10, now, 100, "now_playing", "", "SU", "", "", now, now)
+
+		mock.ExpectQuery(where+"\\s+ORDER BY rating DESC, review_count DESC, id DESC\\s+LIMIT \\$5 OFFSET \\$6").
+			WithArgs(`100\%`, []string{"Action", "Sci-Fi"}, "now_playing", 4.5, 10, 10).
+			WillReturnRows(rows)
+
+		movies, pagination, err := repo.GetAllMovies(context.Background(), filter, 2, 10)
+		assert.NoError(t, err)
+		assert.Len(t, movies, 1)
+		assert.Equal(t, 11, pagination.TotalRecords)
+		assert.Equal(t, 2, pagination.TotalPages)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("Error - Count Query Error", func(t *testing.T) {
 		mock.ExpectQuery("SELECT COUNT").WillReturnError(errors.New("count error"))
 
-		movies, pagination, err := repo.GetAllMovies(context.Background(), 1, 10)
+		movies, pagination, err := repo.GetAllMovies(context.Background(), entity.MovieFilter{}, 1, 10)
 		assert.Error(t, err)
 		assert.Nil(t, movies)
 		assert.Equal(t, dto.Pagination{}, pagination)
@@ -68,7 +104,7 @@ func TestMovieRepo_GetAllMovies(t *testing.T) {
 			WithArgs(10, 0).
 			WillReturnError(errors.New("query error"))
 
-		movies, pagination, err := repo.GetAllMovies(context.Background(), 1, 10)
+		movies, pagination, err := repo.GetAllMovies(context.Background(), entity.MovieFilter{}, 1, 10)
 		assert.Error(t, err)
 		assert.Nil(t, movies)
 		assert.Equal(t, dto.Pagination{}, pagination)
@@ -91,8 +127,10 @@ func TestMovieRepo_GetMovieByID(t *testing.T) {
 
 		rows := pgxmock.NewRows([]string{
 			"id", "title", "poster_url", "genres", "rating", "review_count",
-			"release_date", "duration_in_minutes", "release_status", "created_at", "updated_at",
-		}).AddRow(1, "Test Movie", "http://poster.jpg", []string{"Action"}, 8.5, 100, releaseDate, 120, "now_showing", now, now)
+			"release_date", "duration_in_minutes", "release_status", "synopsis", "age_rating",
+			"language", "trailer_url", "created_at", "updated_at",
+		}).AddRow(1, "Test Movie", "http://poster.jpg", []string{"Action"}, 8.5, 100, releaseDate, 120, "now_showing",
+			"A test synopsis", "13+", "English", "https://example.com/trailer", now, now)
 
 		mock.ExpectQuery("SELECT (.+) FROM movies WHERE id").
 			WithArgs(1).
@@ -103,6 +141,9 @@ func TestMovieRepo_GetMovieByID(t *testing.T) {
 		assert.Equal(t, 1, movie.ID)
 		assert.Equal(t, "Test Movie", movie.Title)
 		assert.Equal(t, 120, movie.DurationMinutes)
+		assert.Equal(t, "A test synopsis", movie.Synopsis)
+		assert.Equal(t, "13+", movie.AgeRating)
+		assert.Equal(t, "https://example.com/trailer", movie.TrailerURL)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -128,3 +169,235 @@ func TestMovieRepo_GetMovieByID(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestMovieRepo_CreateMovie(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewMovieRepo(mock)
+	releaseDate := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	movie := entity.Movie{
+		Title:           "New Movie",
+		PosterURL:       "public/images/new.png",
+		Genres:          []string{"Drama"},
+		ReleaseDate:     releaseDate,
+		DurationMinutes: 110,
+		ReleaseStatus:   "coming_soon",
+		Synopsis:        "A new story",
+		AgeRating:       "13+",
+		Language:        "Indonesian",
+		TrailerURL:      "https://example.com/trailer",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO movies").
+			WithArgs("New Movie", "public/images/new.png", []string{"Drama"}, releaseDate, 110, "coming_soon",
+				"A new story", "13+", "Indonesian", "https://example.com/trailer").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "rating", "review_count", "created_at", "updated_at"}).
+				AddRow(11, 0.0, 0, now, now))
+		mock.ExpectExec("DELETE FROM movie_cast").WithArgs(11).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectExec("DELETE FROM movie_crew").WithArgs(11).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectCommit()
+
+		created, err := repo.CreateMovie(context.Background(), movie)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, created.ID)
+		assert.Equal(t, "New Movie", created.Title)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - With Credits", func(t *testing.T) {
+		now := time.Now()
+		withCredits := movie
+		withCredits.Cast = []entity.MovieCastMember{
+			{Name: "Lead Actor", CharacterName: "Hero", BillingOrder: 1},
+			{Name: "Supporting Actor", BillingOrder: 2},
+		}
+		withCredits.Crew = []entity.MovieCrewMember{{Name: "Some Director", Job: "Director"}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO movies").
+			WithArgs("New Movie", "public/images/new.png", []string{"Drama"}, releaseDate, 110, "coming_soon",
+				"A new story", "13+", "Indonesian", "https://example.com/trailer").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "rating", "review_count", "created_at", "updated_at"}).
+				AddRow(12, 0.0, 0, now, now))
+		mock.ExpectExec("DELETE FROM movie_cast").WithArgs(12).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectExec("DELETE FROM movie_crew").WithArgs(12).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectExec("INSERT INTO movie_cast (.+) FROM unnest").
+			WithArgs(12, []string{"Lead Actor", "Supporting Actor"}, []string{"Hero", ""}, []int{1, 2}).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+		mock.ExpectExec("INSERT INTO movie_crew (.+) FROM unnest").
+			WithArgs(12, []string{"Some Director"}, []string{"Director"}).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		created, err := repo.CreateMovie(context.Background(), withCredits)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, created.ID)
+		assert.Len(t, created.Cast, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO movies").
+			WithArgs("New Movie", "public/images/new.png", []string{"Drama"}, releaseDate, 110, "coming_soon",
+				"A new story", "13+", "Indonesian", "https://example.com/trailer").
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateMovie(context.Background(), movie)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMovieRepo_UpdateMovie(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewMovieRepo(mock)
+	releaseDate := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	movie := entity.Movie{
+		ID:              3,
+		Title:           "Renamed",
+		Genres:          []string{"Drama"},
+		ReleaseDate:     releaseDate,
+		DurationMinutes: 110,
+		ReleaseStatus:   "now_playing",
+		AgeRating:       "SU",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE movies SET (.+) updated_at = NOW\\(\\) WHERE id = (.+) AND deleted_at IS NULL").
+			WithArgs("Renamed", "", []string{"Drama"}, releaseDate, 110, "now_playing", "", "SU", "", "", 3).
+			WillReturnRows(pgxmock.NewRows([]string{"rating", "review_count", "created_at", "updated_at"}).
+				AddRow(4.5, 10, now, now))
+		mock.ExpectExec("DELETE FROM movie_cast").WithArgs(3).WillReturnResult(pgxmock.NewResult("DELETE", 2))
+		mock.ExpectExec("DELETE FROM movie_crew").WithArgs(3).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+
+		updated, err := repo.UpdateMovie(context.Background(), movie)
+		assert.NoError(t, err)
+		assert.Equal(t, 4.5, updated.Rating)
+		assert.Equal(t, now, updated.UpdatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE movies SET").
+			WithArgs("Renamed", "", []string{"Drama"}, releaseDate, 110, "now_playing", "", "SU", "", "", 3).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.UpdateMovie(context.Background(), movie)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMovieRepo_GetMovieCredits(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewMovieRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM movie_cast WHERE movie_id = (.+) ORDER BY billing_order").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "movie_id", "name", "character_name", "billing_order"}).
+				AddRow(1, 1, "Lead Actor", "Hero", 1).
+				AddRow(2, 1, "Supporting Actor", "", 2))
+		mock.ExpectQuery("SELECT (.+) FROM movie_crew WHERE movie_id").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "movie_id", "name", "job"}).
+				AddRow(1, 1, "Some Director", "Director"))
+
+		cast, crew, err := repo.GetMovieCredits(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Len(t, cast, 2)
+		assert.Equal(t, "Hero", cast[0].CharacterName)
+		assert.Len(t, crew, 1)
+		assert.Equal(t, "Director", crew[0].Job)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM movie_cast").
+			WithArgs(2).
+			WillReturnError(errors.New("database error"))
+
+		_, _, err := repo.GetMovieCredits(context.Background(), 2)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMovieRepo_DeleteMovie(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewMovieRepo(mock)
+
+	t.Run("Success - Soft Deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies WHERE id (.+) FOR UPDATE").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery("SELECT EXISTS").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec("UPDATE movies SET deleted_at").
+			WithArgs(3).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteMovie(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Upcoming Showtimes", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies WHERE id (.+) FOR UPDATE").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery("SELECT EXISTS").
+			WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err := repo.DeleteMovie(context.Background(), 3)
+		assert.ErrorIs(t, err, ErrMovieHasShowtimes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies WHERE id (.+) FOR UPDATE").
+			WithArgs(999).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.DeleteMovie(context.Background(), 999)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
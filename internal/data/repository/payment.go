@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"project-app-bioskop/internal/data/entity"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type PaymentRepoInterface interface {
@@ -11,8 +14,19 @@ type PaymentRepoInterface interface {
 	CreatePayment(ctx context.Context, payment entity.Payment) (int, error)
 	GetPaymentByBookingID(ctx context.Context, bookingID int) (entity.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, paymentID int, status string) error
+	SettlePayment(ctx context.Context, paymentID, bookingID int, status, transactionID string) error
+	GetRefundByBookingID(ctx context.Context, bookingID int) (entity.Refund, error)
+	UpdateRefundStatus(ctx context.Context, refundID int, status string) error
+	ApplyWebhookEvent(ctx context.Context, event entity.PaymentWebhookEvent) (entity.Payment, error)
+	GetStalePendingPayments(ctx context.Context) ([]entity.Payment, error)
+	GetPaymentByID(ctx context.Context, id int) (entity.Payment, error)
+	GetFailedRefunds(ctx context.Context) ([]entity.Refund, error)
 }
 
+// unpayableRefundReason is stored on the refund of a charge that completed after its booking
+// stopped being payable
+const unpayableRefundReason = "booking was no longer payable when the charge completed"
+
 type PaymentRepo struct {
 	DB DBPool
 }
@@ -23,7 +37,7 @@ func NewPaymentRepo(db DBPool) PaymentRepoInterface {
 
 // GetAllPaymentMethods retrieves all available payment methods
 func (r *PaymentRepo) GetAllPaymentMethods(ctx context.Context) ([]entity.PaymentMethod, error) {
-	query := `SELECT id, name FROM payment_methods ORDER BY id`
+	query := `SELECT id, name, gateway FROM payment_methods ORDER BY id`
 	rows, err := r.DB.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -33,7 +47,7 @@ func (r *PaymentRepo) GetAllPaymentMethods(ctx context.Context) ([]entity.Paymen
 	var methods []entity.PaymentMethod
 	for rows.Next() {
 		var m entity.PaymentMethod
-		if err := rows.Scan(&m.ID, &m.Name); err != nil {
+		if err := rows.Scan(&m.ID, &m.Name, &m.Gateway); err != nil {
 			return nil, err
 		}
 		methods = append(methods, m)
@@ -43,36 +57,42 @@ func (r *PaymentRepo) GetAllPaymentMethods(ctx context.Context) ([]entity.Paymen
 
 // GetPaymentMethodByID retrieves payment method by ID
 func (r *PaymentRepo) GetPaymentMethodByID(ctx context.Context, id int) (entity.PaymentMethod, error) {
-	query := `SELECT id, name FROM payment_methods WHERE id = $1`
+	query := `SELECT id, name, gateway, COALESCE(webhook_secret, '') FROM payment_methods WHERE id = $1`
 	var m entity.PaymentMethod
-	err := r.DB.QueryRow(ctx, query, id).Scan(&m.ID, &m.Name)
+	err := r.DB.QueryRow(ctx, query, id).Scan(&m.ID, &m.Name, &m.Gateway, &m.WebhookSecret)
 	if err != nil {
 		return m, err
 	}
 	return m, nil
 }
 
-// CreatePayment creates a new payment record
+// CreatePayment creates a new payment record, paid_at is only set for completed payments.
+// It fails with ErrPaymentInProgress when the booking already has a pending or completed payment.
 func (r *PaymentRepo) CreatePayment(ctx context.Context, payment entity.Payment) (int, error) {
 	query := `INSERT INTO payments (booking_id, payment_method_id, status, payment_details, paid_at) 
-			  VALUES ($1, $2, $3, $4, NOW()) RETURNING id`
+			  VALUES ($1, $2, $3, $4, CASE WHEN $3 = 'completed' THEN NOW() END) 
+			  ON CONFLICT (booking_id) WHERE status IN ('pending', 'completed') DO NOTHING 
+			  RETURNING id`
 	var id int
 	err := r.DB.QueryRow(ctx, query,
 		payment.BookingID, payment.PaymentMethodID, payment.Status, payment.PaymentDetails,
 	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrPaymentInProgress
+	}
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
 
-// GetPaymentByBookingID retrieves payment by booking ID
+// GetPaymentByBookingID retrieves the latest payment attempt for a booking
 func (r *PaymentRepo) GetPaymentByBookingID(ctx context.Context, bookingID int) (entity.Payment, error) {
-	query := `SELECT id, booking_id, payment_method_id, status, payment_details, paid_at 
-			  FROM payments WHERE booking_id = $1`
+	query := `SELECT id, booking_id, payment_method_id, status, COALESCE(transaction_id, ''), payment_details, paid_at 
+			  FROM payments WHERE booking_id = $1 ORDER BY id DESC LIMIT 1`
 	var p entity.Payment
 	err := r.DB.QueryRow(ctx, query, bookingID).Scan(
-		&p.ID, &p.BookingID, &p.PaymentMethodID, &p.Status, &p.PaymentDetails, &p.PaidAt,
+		&p.ID, &p.BookingID, &p.PaymentMethodID, &p.Status, &p.TransactionID, &p.PaymentDetails, &p.PaidAt,
 	)
 	if err != nil {
 		return p, err
@@ -82,7 +102,210 @@ func (r *PaymentRepo) GetPaymentByBookingID(ctx context.Context, bookingID int)
 
 // UpdatePaymentStatus updates payment status
 func (r *PaymentRepo) UpdatePaymentStatus(ctx context.Context, paymentID int, status string) error {
-	query := `UPDATE payments SET status = $1, paid_at = NOW() WHERE id = $2`
+	query := `UPDATE payments SET status = $1, 
+			  paid_at = CASE WHEN $1 = 'completed' THEN NOW() ELSE paid_at END, updated_at = NOW() 
+			  WHERE id = $2`
 	_, err := r.DB.Exec(ctx, query, status, paymentID)
 	return err
 }
+
+// SettlePayment records the gateway outcome of a payment and, when it completed,
+// marks the still-pending booking as paid in the same transaction. A completed charge
+// for a booking that expired or was cancelled meanwhile is still recorded, together with
+// a pending refund of it, and ErrBookingStatusChanged is returned.
+func (r *PaymentRepo) SettlePayment(ctx context.Context, paymentID, bookingID int, status, transactionID string) error {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE payments SET status = $1, transaction_id = NULLIF($2, ''), 
+			  paid_at = CASE WHEN $1 = 'completed' THEN NOW() ELSE paid_at END, updated_at = NOW() 
+			  WHERE id = $3`
+	if _, err = tx.Exec(ctx, query, status, transactionID, paymentID); err != nil {
+		return err
+	}
+
+	if status == "completed" {
+		bookingQuery := `UPDATE bookings SET status = 'paid' WHERE id = $1 AND status = 'pending'`
+		tag, err := tx.Exec(ctx, bookingQuery, bookingID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			if err := queueUnpayableRefund(ctx, tx, paymentID, bookingID); err != nil {
+				return err
+			}
+			if err := tx.Commit(ctx); err != nil {
+				return err
+			}
+			return ErrBookingStatusChanged
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// queueUnpayableRefund records a pending refund of the booking total for a completed
+// charge whose booking is no longer pending, so the money taken stays on record until
+// the gateway has given it back
+func queueUnpayableRefund(ctx context.Context, tx pgx.Tx, paymentID, bookingID int) error {
+	query := `INSERT INTO refunds (payment_id, booking_id, amount, status, reason) 
+			  SELECT $1, id, total_amount, 'pending', $3 FROM bookings WHERE id = $2 
+			  ON CONFLICT (payment_id) DO NOTHING`
+	_, err := tx.Exec(ctx, query, paymentID, bookingID, unpayableRefundReason)
+	return err
+}
+
+// GetRefundByBookingID retrieves the refund issued for a booking
+func (r *PaymentRepo) GetRefundByBookingID(ctx context.Context, bookingID int) (entity.Refund, error) {
+	query := `SELECT id, payment_id, booking_id, amount, status, COALESCE(reason, ''), created_at, updated_at 
+			  FROM refunds WHERE booking_id = $1`
+	var rf entity.Refund
+	err := r.DB.QueryRow(ctx, query, bookingID).Scan(
+		&rf.ID, &rf.PaymentID, &rf.BookingID, &rf.Amount, &rf.Status, &rf.Reason, &rf.CreatedAt, &rf.UpdatedAt,
+	)
+	if err != nil {
+		return rf, err
+	}
+	return rf, nil
+}
+
+// UpdateRefundStatus moves a refund to the next status in its lifecycle
+func (r *PaymentRepo) UpdateRefundStatus(ctx context.Context, refundID int, status string) error {
+	query := `UPDATE refunds SET status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.DB.Exec(ctx, query, status, refundID)
+	return err
+}
+
+// ApplyWebhookEvent records a provider callback and moves the matching pending payment,
+// and on completion its booking, to the reported status in a single transaction. A
+// completion for a booking that expired or was cancelled meanwhile is still recorded,
+// together with a pending refund of the charge, and ErrBookingStatusChanged is returned.
+func (r *PaymentRepo) ApplyWebhookEvent(ctx context.Context, event entity.PaymentWebhookEvent) (entity.Payment, error) {
+	var p entity.Payment
+
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return p, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The unique index on (payment_method_id, transaction_id) rejects redelivered callbacks
+	eventQuery := `INSERT INTO payment_webhook_events (payment_method_id, transaction_id, status, payload) 
+				   VALUES ($1, $2, $3, $4) ON CONFLICT (payment_method_id, transaction_id) DO NOTHING`
+	tag, err := tx.Exec(ctx, eventQuery, event.PaymentMethodID, event.TransactionID, event.Status, event.Payload)
+	if err != nil {
+		return p, err
+	}
+	if tag.RowsAffected() == 0 {
+		return p, ErrWebhookDuplicate
+	}
+
+	paymentQuery := `SELECT id, booking_id, payment_method_id, status, transaction_id 
+					 FROM payments WHERE transaction_id = $1 AND payment_method_id = $2 FOR UPDATE`
+	err = tx.QueryRow(ctx, paymentQuery, event.TransactionID, event.PaymentMethodID).Scan(
+		&p.ID, &p.BookingID, &p.PaymentMethodID, &p.Status, &p.TransactionID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, ErrPaymentNotFound
+	}
+	if err != nil {
+		return p, err
+	}
+
+	// Payments already settled by the synchronous charge need no further transition
+	if p.Status != "pending" {
+		return p, ErrWebhookDuplicate
+	}
+
+	updateQuery := `UPDATE payments SET status = $1, 
+					paid_at = CASE WHEN $1 = 'completed' THEN NOW() ELSE paid_at END, updated_at = NOW() 
+					WHERE id = $2 RETURNING paid_at`
+	if err = tx.QueryRow(ctx, updateQuery, event.Status, p.ID).Scan(&p.PaidAt); err != nil {
+		return p, err
+	}
+	p.Status = event.Status
+
+	if event.Status == "completed" {
+		bookingQuery := `UPDATE bookings SET status = 'paid' WHERE id = $1 AND status = 'pending'`
+		tag, err := tx.Exec(ctx, bookingQuery, p.BookingID)
+		if err != nil {
+			return p, err
+		}
+		if tag.RowsAffected() == 0 {
+			if err := queueUnpayableRefund(ctx, tx, p.ID, p.BookingID); err != nil {
+				return p, err
+			}
+			if err := tx.Commit(ctx); err != nil {
+				return p, err
+			}
+			return p, ErrBookingStatusChanged
+		}
+	}
+
+	return p, tx.Commit(ctx)
+}
+
+// GetStalePendingPayments retrieves the pending provider payments of pending bookings
+// whose hold has passed, the ones whose confirmation may never arrive
+func (r *PaymentRepo) GetStalePendingPayments(ctx context.Context) ([]entity.Payment, error) {
+	query := `SELECT p.id, p.booking_id, p.payment_method_id, p.status, p.transaction_id 
+			  FROM payments p 
+			  INNER JOIN bookings b ON b.id = p.booking_id 
+			  WHERE p.status = 'pending' AND p.transaction_id IS NOT NULL 
+			  AND b.status = 'pending' AND b.expires_at <= NOW() 
+			  ORDER BY p.id`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []entity.Payment
+	for rows.Next() {
+		var p entity.Payment
+		if err := rows.Scan(&p.ID, &p.BookingID, &p.PaymentMethodID, &p.Status, &p.TransactionID); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+// GetPaymentByID retrieves a payment attempt by ID
+func (r *PaymentRepo) GetPaymentByID(ctx context.Context, id int) (entity.Payment, error) {
+	query := `SELECT id, booking_id, payment_method_id, status, COALESCE(transaction_id, ''), payment_details, paid_at 
+			  FROM payments WHERE id = $1`
+	var p entity.Payment
+	err := r.DB.QueryRow(ctx, query, id).Scan(
+		&p.ID, &p.BookingID, &p.PaymentMethodID, &p.Status, &p.TransactionID, &p.PaymentDetails, &p.PaidAt,
+	)
+	if err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// GetFailedRefunds retrieves the refunds the gateway did not complete, oldest first, so
+// they can be sent again
+func (r *PaymentRepo) GetFailedRefunds(ctx context.Context) ([]entity.Refund, error) {
+	query := `SELECT id, payment_id, booking_id, amount, status, COALESCE(reason, ''), created_at, updated_at 
+			  FROM refunds WHERE status = 'failed' ORDER BY id`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []entity.Refund
+	for rows.Next() {
+		var rf entity.Refund
+		if err := rows.Scan(&rf.ID, &rf.PaymentID, &rf.BookingID, &rf.Amount, &rf.Status, &rf.Reason, &rf.CreatedAt, &rf.UpdatedAt); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, rf)
+	}
+	return refunds, rows.Err()
+}
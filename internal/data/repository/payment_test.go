@@ -5,6 +5,7 @@ import (
 	"errors"
 	"project-app-bioskop/internal/data/entity"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v3"
@@ -22,11 +23,11 @@ func TestPaymentRepo_GetAllPaymentMethods(t *testing.T) {
 
 	t.Run("Success - Get All Payment Methods", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "name",
+			"id", "name", "gateway",
 		}).
-			AddRow(1, "Credit Card").
-			AddRow(2, "Cash").
-			AddRow(3, "QRIS")
+			AddRow(1, "Credit Card", "simulator").
+			AddRow(2, "Cash", "simulator").
+			AddRow(3, "QRIS", "simulator")
 
 		mock.ExpectQuery("SELECT (.+) FROM payment_methods").
 			WillReturnRows(rows)
@@ -42,7 +43,7 @@ func TestPaymentRepo_GetAllPaymentMethods(t *testing.T) {
 
 	t.Run("Empty Result", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "name",
+			"id", "name", "gateway",
 		})
 
 		mock.ExpectQuery("SELECT (.+) FROM payment_methods").
@@ -76,8 +77,8 @@ func TestPaymentRepo_GetPaymentMethodByID(t *testing.T) {
 
 	t.Run("Success - Payment Method Found", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "name",
-		}).AddRow(1, "Credit Card")
+			"id", "name", "gateway", "webhook_secret",
+		}).AddRow(1, "Credit Card", "simulator", "secret")
 
 		mock.ExpectQuery("SELECT (.+) FROM payment_methods WHERE id").
 			WithArgs(1).
@@ -87,6 +88,8 @@ func TestPaymentRepo_GetPaymentMethodByID(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 1, method.ID)
 		assert.Equal(t, "Credit Card", method.Name)
+		assert.Equal(t, "simulator", method.Gateway)
+		assert.Equal(t, "secret", method.WebhookSecret)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -148,6 +151,24 @@ func TestPaymentRepo_CreatePayment(t *testing.T) {
 		assert.Equal(t, 0, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("Error - Payment Already In Progress", func(t *testing.T) {
+		payment := entity.Payment{
+			BookingID:       1,
+			PaymentMethodID: 1,
+			Status:          "pending",
+			PaymentDetails:  "Payment for booking #1",
+		}
+
+		mock.ExpectQuery("INSERT INTO payments .* ON CONFLICT \\(booking_id\\) WHERE status IN").
+			WithArgs(payment.BookingID, payment.PaymentMethodID, payment.Status, payment.PaymentDetails).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}))
+
+		id, err := repo.CreatePayment(context.Background(), payment)
+		assert.ErrorIs(t, err, ErrPaymentInProgress)
+		assert.Equal(t, 0, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestPaymentRepo_GetPaymentByBookingID(t *testing.T) {
@@ -161,8 +182,8 @@ func TestPaymentRepo_GetPaymentByBookingID(t *testing.T) {
 
 	t.Run("Success - Payment Found", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "booking_id", "payment_method_id", "status", "payment_details", "paid_at",
-		}).AddRow(1, 1, 1, "success", "Payment completed", nil)
+			"id", "booking_id", "payment_method_id", "status", "transaction_id", "payment_details", "paid_at",
+		}).AddRow(1, 1, 1, "success", "SIM-1", "Payment completed", nil)
 
 		mock.ExpectQuery("SELECT (.+) FROM payments WHERE booking_id").
 			WithArgs(1).
@@ -173,6 +194,7 @@ func TestPaymentRepo_GetPaymentByBookingID(t *testing.T) {
 		assert.Equal(t, 1, payment.ID)
 		assert.Equal(t, 1, payment.BookingID)
 		assert.Equal(t, "success", payment.Status)
+		assert.Equal(t, "SIM-1", payment.TransactionID)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -217,3 +239,292 @@ func TestPaymentRepo_UpdatePaymentStatus(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestPaymentRepo_SettlePayment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPaymentRepo(mock)
+
+	t.Run("Success - Completed Marks Booking Paid", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE payments SET status").
+			WithArgs("completed", "SIM-1", 1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec("UPDATE bookings SET status = 'paid'").
+			WithArgs(2).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		err := repo.SettlePayment(context.Background(), 1, 2, "completed", "SIM-1")
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Failed Leaves Booking", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE payments SET status").
+			WithArgs("failed", "SIM-2", 1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		err := repo.SettlePayment(context.Background(), 1, 2, "failed", "SIM-2")
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Booking No Longer Pending Queues Refund", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE payments SET status").
+			WithArgs("completed", "SIM-3", 1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec("UPDATE bookings SET status = 'paid'").
+			WithArgs(2).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectExec("INSERT INTO refunds (.+) SELECT (.+) FROM bookings WHERE id = (.+) ON CONFLICT \\(payment_id\\) DO NOTHING").
+			WithArgs(1, 2, unpayableRefundReason).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		err := repo.SettlePayment(context.Background(), 1, 2, "completed", "SIM-3")
+		assert.ErrorIs(t, err, ErrBookingStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPaymentRepo_GetRefundByBookingID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPaymentRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		rows := pgxmock.NewRows([]string{
+			"id", "payment_id", "booking_id", "amount", "status", "reason", "created_at", "updated_at",
+		}).AddRow(1, 2, 3, 100000.0, "pending", "", now, now)
+
+		mock.ExpectQuery("SELECT (.+) FROM refunds WHERE booking_id").
+			WithArgs(3).
+			WillReturnRows(rows)
+
+		refund, err := repo.GetRefundByBookingID(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, refund.PaymentID)
+		assert.Equal(t, "pending", refund.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM refunds WHERE booking_id").
+			WithArgs(9).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetRefundByBookingID(context.Background(), 9)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPaymentRepo_UpdateRefundStatus(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPaymentRepo(mock)
+
+	mock.ExpectExec("UPDATE refunds SET status").
+		WithArgs("completed", 1).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.UpdateRefundStatus(context.Background(), 1, "completed")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaymentRepo_ApplyWebhookEvent(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPaymentRepo(mock)
+
+	event := entity.PaymentWebhookEvent{
+		PaymentMethodID: 1,
+		TransactionID:   "SIM-1",
+		Status:          "completed",
+		Payload:         `{"transaction_id":"SIM-1","status":"completed"}`,
+	}
+
+	t.Run("Success - Completed Marks Booking Paid", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO payment_webhook_events").
+			WithArgs(1, "SIM-1", "completed", event.Payload).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery("SELECT (.+) FROM payments WHERE transaction_id (.+) FOR UPDATE").
+			WithArgs("SIM-1", 1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "booking_id", "payment_method_id", "status", "transaction_id"}).
+				AddRow(7, 3, 1, "pending", "SIM-1"))
+		mock.ExpectQuery("UPDATE payments SET status").
+			WithArgs("completed", 7).
+			WillReturnRows(pgxmock.NewRows([]string{"paid_at"}).AddRow(&now))
+		mock.ExpectExec("UPDATE bookings SET status = 'paid'").
+			WithArgs(3).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		payment, err := repo.ApplyWebhookEvent(context.Background(), event)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, payment.ID)
+		assert.Equal(t, "completed", payment.Status)
+		assert.NotNil(t, payment.PaidAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Duplicate - Event Already Recorded", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO payment_webhook_events").
+			WithArgs(1, "SIM-1", "completed", event.Payload).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectRollback()
+
+		_, err := repo.ApplyWebhookEvent(context.Background(), event)
+		assert.ErrorIs(t, err, ErrWebhookDuplicate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Duplicate - Payment Already Settled", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO payment_webhook_events").
+			WithArgs(1, "SIM-1", "completed", event.Payload).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery("SELECT (.+) FROM payments WHERE transaction_id (.+) FOR UPDATE").
+			WithArgs("SIM-1", 1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "booking_id", "payment_method_id", "status", "transaction_id"}).
+				AddRow(7, 3, 1, "completed", "SIM-1"))
+		mock.ExpectRollback()
+
+		_, err := repo.ApplyWebhookEvent(context.Background(), event)
+		assert.ErrorIs(t, err, ErrWebhookDuplicate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Unknown Transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO payment_webhook_events").
+			WithArgs(1, "SIM-1", "completed", event.Payload).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery("SELECT (.+) FROM payments WHERE transaction_id (.+) FOR UPDATE").
+			WithArgs("SIM-1", 1).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.ApplyWebhookEvent(context.Background(), event)
+		assert.ErrorIs(t, err, ErrPaymentNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Booking No Longer Pending Queues Refund", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO payment_webhook_events").
+			WithArgs(1, "SIM-1", "completed", event.Payload).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectQuery("SELECT (.+) FROM payments WHERE transaction_id (.+) FOR UPDATE").
+			WithArgs("SIM-1", 1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "booking_id", "payment_method_id", "status", "transaction_id"}).
+				AddRow(7, 3, 1, "pending", "SIM-1"))
+		mock.ExpectQuery("UPDATE payments SET status").
+			WithArgs("completed", 7).
+			WillReturnRows(pgxmock.NewRows([]string{"paid_at"}).AddRow(nil))
+		mock.ExpectExec("UPDATE bookings SET status = 'paid'").
+			WithArgs(3).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectExec("INSERT INTO refunds").
+			WithArgs(7, 3, unpayableRefundReason).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		payment, err := repo.ApplyWebhookEvent(context.Background(), event)
+		assert.ErrorIs(t, err, ErrBookingStatusChanged)
+		assert.Equal(t, "SIM-1", payment.TransactionID)
+		assert.Equal(t, "completed", payment.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPaymentRepo_GetStalePendingPayments(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPaymentRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM payments p INNER JOIN bookings b (.+) WHERE p.status = 'pending'").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "booking_id", "payment_method_id", "status", "transaction_id"}).
+				AddRow(7, 3, 1, "pending", "SIM-7"))
+
+		payments, err := repo.GetStalePendingPayments(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, payments, 1)
+		assert.Equal(t, "SIM-7", payments[0].TransactionID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM payments p").
+			WillReturnError(errors.New("database error"))
+
+		payments, err := repo.GetStalePendingPayments(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, payments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPaymentRepo_GetFailedRefunds(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPaymentRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("SELECT (.+) FROM refunds WHERE status = 'failed' ORDER BY id").
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "payment_id", "booking_id", "amount", "status", "reason", "created_at", "updated_at",
+			}).AddRow(4, 7, 3, 100000.0, "failed", "", now, now))
+
+		refunds, err := repo.GetFailedRefunds(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, refunds, 1)
+		assert.Equal(t, 7, refunds[0].PaymentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM refunds").
+			WillReturnError(errors.New("database error"))
+
+		refunds, err := repo.GetFailedRefunds(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, refunds)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
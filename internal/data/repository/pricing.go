@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PricingRepoInterface interface {
+	GetPricingRules(ctx context.Context) ([]entity.PricingRule, error)
+	CreatePricingRule(ctx context.Context, rule entity.PricingRule) (entity.PricingRule, error)
+	UpdatePricingRule(ctx context.Context, rule entity.PricingRule) (entity.PricingRule, error)
+	DeletePricingRule(ctx context.Context, id int) error
+}
+
+type PricingRepo struct {
+	DB DBPool
+}
+
+func NewPricingRepo(db DBPool) PricingRepoInterface {
+	return &PricingRepo{DB: db}
+}
+
+// pricingRuleColumns lists the pricing rule columns in the order scanPricingRule reads them
+const pricingRuleColumns = `id, name, cinema_id, days_of_week, dates::text[], 
+			  COALESCE(time_from::text, ''), COALESCE(time_until::text, ''), min_occupancy, 
+			  adjustment_type, adjustment_value, priority, is_active, created_at, updated_at`
+
+func scanPricingRule(row pgx.Row) (entity.PricingRule, error) {
+	var r entity.PricingRule
+	err := row.Scan(
+		&r.ID, &r.Name, &r.CinemaID, &r.DaysOfWeek, &r.Dates,
+		&r.TimeFrom, &r.TimeUntil, &r.MinOccupancy,
+		&r.AdjustmentType, &r.AdjustmentValue, &r.Priority, &r.IsActive, &r.CreatedAt, &r.UpdatedAt,
+	)
+	return r, err
+}
+
+func queryPricingRules(ctx context.Context, q querier, query string, args ...interface{}) ([]entity.PricingRule, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []entity.PricingRule{}
+	for rows.Next() {
+		r, err := scanPricingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// GetPricingRules retrieves all pricing rules in the order they are applied
+func (r *PricingRepo) GetPricingRules(ctx context.Context) ([]entity.PricingRule, error) {
+	query := `SELECT ` + pricingRuleColumns + ` FROM pricing_rules ORDER BY priority, id`
+	return queryPricingRules(ctx, r.DB, query)
+}
+
+// CreatePricingRule inserts a pricing rule
+func (r *PricingRepo) CreatePricingRule(ctx context.Context, rule entity.PricingRule) (entity.PricingRule, error) {
+	query := `INSERT INTO pricing_rules (name, cinema_id, days_of_week, dates, time_from, time_until, 
+			  min_occupancy, adjustment_type, adjustment_value, priority, is_active) 
+			  VALUES ($1, $2, $3, $4::date[], NULLIF($5, '')::time, NULLIF($6, '')::time, $7, $8, $9, $10, $11) 
+			  RETURNING ` + pricingRuleColumns
+	return scanPricingRule(r.DB.QueryRow(ctx, query,
+		rule.Name, rule.CinemaID, rule.DaysOfWeek, rule.Dates, rule.TimeFrom, rule.TimeUntil,
+		rule.MinOccupancy, rule.AdjustmentType, rule.AdjustmentValue, rule.Priority, rule.IsActive,
+	))
+}
+
+// UpdatePricingRule replaces a pricing rule. Seats already booked keep their price snapshot.
+func (r *PricingRepo) UpdatePricingRule(ctx context.Context, rule entity.PricingRule) (entity.PricingRule, error) {
+	query := `UPDATE pricing_rules SET name = $1, cinema_id = $2, days_of_week = $3, dates = $4::date[], 
+			  time_from = NULLIF($5, '')::time, time_until = NULLIF($6, '')::time, min_occupancy = $7, 
+			  adjustment_type = $8, adjustment_value = $9, priority = $10, is_active = $11, updated_at = NOW() 
+			  WHERE id = $12 
+			  RETURNING ` + pricingRuleColumns
+	return scanPricingRule(r.DB.QueryRow(ctx, query,
+		rule.Name, rule.CinemaID, rule.DaysOfWeek, rule.Dates, rule.TimeFrom, rule.TimeUntil,
+		rule.MinOccupancy, rule.AdjustmentType, rule.AdjustmentValue, rule.Priority, rule.IsActive, rule.ID,
+	))
+}
+
+// DeletePricingRule removes a pricing rule, returning pgx.ErrNoRows when it does not exist
+func (r *PricingRepo) DeletePricingRule(ctx context.Context, id int) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM pricing_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// resolveShowtimePrice applies the active pricing rules to a showtime's base price.
+// Occupancy counts the seats held or sold for the showtime, so inside a booking
+// transaction that holds the showtime lock it excludes the seats being booked.
+func resolveShowtimePrice(ctx context.Context, q querier, showtimeID int) (float64, error) {
+	var base float64
+	var pc entity.PricingContext
+	var totalSeats, takenSeats int
+	showtimeQuery := `SELECT st.price, st.cinema_id, st.show_date::text, st.show_time::text, 
+			  (SELECT COUNT(*) FROM seats s WHERE s.studio_id = st.studio_id), 
+			  (SELECT COUNT(*) FROM booking_seats bs 
+			   INNER JOIN bookings b ON b.id = bs.booking_id 
+			   WHERE b.showtime_id = st.id AND b.status NOT IN ('cancelled', 'expired')) 
+			  FROM showtimes st WHERE st.id = $1`
+	err := q.QueryRow(ctx, showtimeQuery, showtimeID).Scan(
+		&base, &pc.CinemaID, &pc.ShowDate, &pc.ShowTime, &totalSeats, &takenSeats,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if totalSeats > 0 {
+		pc.Occupancy = float64(takenSeats) * 100 / float64(totalSeats)
+	}
+
+	rulesQuery := `SELECT ` + pricingRuleColumns + ` FROM pricing_rules 
+			  WHERE is_active AND (cinema_id IS NULL OR cinema_id = $1) 
+			  ORDER BY priority, id`
+	rules, err := queryPricingRules(ctx, q, rulesQuery, pc.CinemaID)
+	if err != nil {
+		return 0, err
+	}
+
+	return entity.ResolvePrice(base, rules, pc), nil
+}
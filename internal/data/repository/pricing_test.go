@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var pricingRuleTestColumns = []string{
+	"id", "name", "cinema_id", "days_of_week", "dates", "time_from", "time_until", "min_occupancy",
+	"adjustment_type", "adjustment_value", "priority", "is_active", "created_at", "updated_at",
+}
+
+var showtimePriceTestColumns = []string{"price", "cinema_id", "show_date", "show_time", "total_seats", "taken_seats"}
+
+// expectShowtimePrice expects the queries that resolve a showtime's price when no
+// pricing rule is active, so the base price is used
+func expectShowtimePrice(mock pgxmock.PgxPoolIface, showtimeID int, price float64) {
+	mock.ExpectQuery("SELECT st.price, st.cinema_id(.+) FROM showtimes st WHERE st.id").
+		WithArgs(showtimeID).
+		WillReturnRows(pgxmock.NewRows(showtimePriceTestColumns).AddRow(price, 1, "2026-01-14", "19:00:00", 100, 0))
+	mock.ExpectQuery("SELECT (.+) FROM pricing_rules").
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows(pricingRuleTestColumns))
+}
+
+func TestResolveShowtimePrice(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	t.Run("Success - Matching Rules Applied In Order", func(t *testing.T) {
+		now := time.Now()
+		// Saturday evening at 85% occupancy
+		mock.ExpectQuery("SELECT st.price, st.cinema_id(.+) FROM showtimes st WHERE st.id").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(showtimePriceTestColumns).AddRow(50000.0, 2, "2026-01-17", "19:00:00", 100, 85))
+		mock.ExpectQuery("SELECT (.+) FROM pricing_rules WHERE is_active AND \\(cinema_id IS NULL OR cinema_id = \\$1\\) ORDER BY priority, id").
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows(pricingRuleTestColumns).
+				AddRow(1, "Weekend", nil, []int{0, 6}, []string{}, "", "", 0.0, "percentage", 20.0, 1, true, now, now).
+				AddRow(2, "Matinee", nil, []int{}, []string{}, "", "17:00:00", 0.0, "percentage", -25.0, 2, true, now, now).
+				AddRow(3, "Surge", nil, []int{}, []string{}, "", "", 80.0, "fixed", 5000.0, 3, true, now, now))
+
+		price, err := resolveShowtimePrice(context.Background(), mock, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 65000.0, price)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT st.price, st.cinema_id(.+) FROM showtimes st").
+			WithArgs(999).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := resolveShowtimePrice(context.Background(), mock, 999)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPricingRepo_GetPricingRules(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPricingRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		cinemaID := 2
+		mock.ExpectQuery("SELECT (.+) FROM pricing_rules ORDER BY priority, id").
+			WillReturnRows(pgxmock.NewRows(pricingRuleTestColumns).
+				AddRow(1, "Holiday", &cinemaID, []int{}, []string{"2026-08-17"}, "", "", 0.0, "percentage", 15.0, 1, false, now, now))
+
+		rules, err := repo.GetPricingRules(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, rules, 1)
+		assert.Equal(t, []string{"2026-08-17"}, rules[0].Dates)
+		assert.Equal(t, &cinemaID, rules[0].CinemaID)
+		assert.False(t, rules[0].IsActive)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM pricing_rules").
+			WillReturnError(errors.New("database error"))
+
+		rules, err := repo.GetPricingRules(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, rules)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPricingRepo_CreatePricingRule(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPricingRepo(mock)
+	now := time.Now()
+	rule := entity.PricingRule{
+		Name: "Matinee", DaysOfWeek: []int{}, Dates: []string{}, TimeUntil: "17:00",
+		AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: -25, IsActive: true,
+	}
+
+	mock.ExpectQuery("INSERT INTO pricing_rules (.+) NULLIF\\(\\$5, ''\\)::time(.+)RETURNING").
+		WithArgs("Matinee", rule.CinemaID, []int{}, []string{}, "", "17:00", 0.0, "percentage", -25.0, 0, true).
+		WillReturnRows(pgxmock.NewRows(pricingRuleTestColumns).
+			AddRow(4, "Matinee", nil, []int{}, []string{}, "", "17:00:00", 0.0, "percentage", -25.0, 0, true, now, now))
+
+	created, err := repo.CreatePricingRule(context.Background(), rule)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, created.ID)
+	assert.Equal(t, "17:00:00", created.TimeUntil)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPricingRepo_UpdatePricingRule_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPricingRepo(mock)
+	rule := entity.PricingRule{
+		ID: 99, Name: "Surge", DaysOfWeek: []int{}, Dates: []string{}, MinOccupancy: 80,
+		AdjustmentType: entity.AdjustmentFixed, AdjustmentValue: 5000,
+	}
+
+	mock.ExpectQuery("UPDATE pricing_rules SET (.+) WHERE id = \\$12").
+		WithArgs("Surge", rule.CinemaID, []int{}, []string{}, "", "", 80.0, "fixed", 5000.0, 0, false, 99).
+		WillReturnError(pgx.ErrNoRows)
+
+	_, err = repo.UpdatePricingRule(context.Background(), rule)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPricingRepo_DeletePricingRule(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPricingRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM pricing_rules WHERE id").
+			WithArgs(4).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		assert.NoError(t, repo.DeletePricingRule(context.Background(), 4))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM pricing_rules WHERE id").
+			WithArgs(99).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		assert.ErrorIs(t, repo.DeletePricingRule(context.Background(), 99), pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PromotionRepoInterface interface {
+	GetPromotionByID(ctx context.Context, id int) (entity.Promotion, error)
+	GetPromotionByCode(ctx context.Context, code string) (entity.Promotion, error)
+	GetPromotions(ctx context.Context, page, limit int) ([]entity.Promotion, dto.Pagination, error)
+	CreatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error)
+	UpdatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error)
+}
+
+type PromotionRepo struct {
+	DB DBPool
+}
+
+func NewPromotionRepo(db DBPool) PromotionRepoInterface {
+	return &PromotionRepo{DB: db}
+}
+
+// promotionColumns lists the promotion columns in the order scanPromotion reads them;
+// used_count counts the active bookings that redeemed the promotion
+const promotionColumns = `p.id, p.code, p.description, p.discount_type, p.discount_value, p.max_discount, 
+			  p.movie_id, p.cinema_id, p.payment_method_id, p.days_of_week, p.min_seats, 
+			  p.usage_limit, p.per_user_limit, 
+			  (SELECT COUNT(*) FROM bookings b WHERE b.promotion_id = p.id AND b.status NOT IN ('cancelled', 'expired')), 
+			  p.valid_from, p.valid_until, p.is_active, p.created_at, p.updated_at`
+
+func scanPromotion(row pgx.Row) (entity.Promotion, error) {
+	var p entity.Promotion
+	err := row.Scan(
+		&p.ID, &p.Code, &p.Description, &p.DiscountType, &p.DiscountValue, &p.MaxDiscount,
+		&p.MovieID, &p.CinemaID, &p.PaymentMethodID, &p.DaysOfWeek, &p.MinSeats,
+		&p.UsageLimit, &p.PerUserLimit, &p.UsedCount,
+		&p.ValidFrom, &p.ValidUntil, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+	)
+	return p, err
+}
+
+// GetPromotionByID retrieves a promotion by ID
+func (r *PromotionRepo) GetPromotionByID(ctx context.Context, id int) (entity.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions p WHERE p.id = $1`
+	return scanPromotion(r.DB.QueryRow(ctx, query, id))
+}
+
+// GetPromotionByCode retrieves a promotion by its code, ignoring case
+func (r *PromotionRepo) GetPromotionByCode(ctx context.Context, code string) (entity.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions p WHERE upper(p.code) = upper($1)`
+	return scanPromotion(r.DB.QueryRow(ctx, query, code))
+}
+
+// GetPromotions retrieves all promotions, newest first, with pagination
+func (r *PromotionRepo) GetPromotions(ctx context.Context, page, limit int) ([]entity.Promotion, dto.Pagination, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM promotions`).Scan(&total); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	query := `SELECT ` + promotionColumns + ` FROM promotions p ORDER BY p.created_at DESC, p.id DESC LIMIT $1 OFFSET $2`
+	rows, err := r.DB.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	defer rows.Close()
+
+	var promotions []entity.Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, dto.Pagination{}, err
+		}
+		promotions = append(promotions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	pagination := dto.Pagination{
+		CurrentPage:  page,
+		TotalPages:   (total + limit - 1) / limit,
+		TotalRecords: total,
+		Limit:        limit,
+	}
+
+	return promotions, pagination, nil
+}
+
+// CreatePromotion inserts a promotion, failing with ErrPromoCodeExists when the code is taken
+func (r *PromotionRepo) CreatePromotion(ctx context.Context, p entity.Promotion) (entity.Promotion, error) {
+	query := `INSERT INTO promotions (code, description, discount_type, discount_value, max_discount, 
+			  movie_id, cinema_id, payment_method_id, days_of_week, min_seats, usage_limit, per_user_limit, 
+			  valid_from, valid_until, is_active) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) 
+			  ON CONFLICT ((upper(code))) DO NOTHING 
+			  RETURNING id, created_at, updated_at`
+	err := r.DB.QueryRow(ctx, query,
+		p.Code, p.Description, p.DiscountType, p.DiscountValue, p.MaxDiscount,
+		p.MovieID, p.CinemaID, p.PaymentMethodID, p.DaysOfWeek, p.MinSeats, p.UsageLimit, p.PerUserLimit,
+		p.ValidFrom, p.ValidUntil, p.IsActive,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, ErrPromoCodeExists
+	}
+	return p, err
+}
+
+// UpdatePromotion replaces the settings of a promotion. Bookings that already redeemed
+// it keep their discount.
+func (r *PromotionRepo) UpdatePromotion(ctx context.Context, p entity.Promotion) (entity.Promotion, error) {
+	var taken bool
+	takenQuery := `SELECT EXISTS (SELECT 1 FROM promotions WHERE upper(code) = upper($1) AND id <> $2)`
+	if err := r.DB.QueryRow(ctx, takenQuery, p.Code, p.ID).Scan(&taken); err != nil {
+		return p, err
+	}
+	if taken {
+		return p, ErrPromoCodeExists
+	}
+
+	query := `UPDATE promotions SET code = $1, description = $2, discount_type = $3, discount_value = $4, 
+			  max_discount = $5, movie_id = $6, cinema_id = $7, payment_method_id = $8, days_of_week = $9, 
+			  min_seats = $10, usage_limit = $11, per_user_limit = $12, valid_from = $13, valid_until = $14, 
+			  is_active = $15, updated_at = NOW() 
+			  WHERE id = $16 
+			  RETURNING created_at, updated_at, 
+			  (SELECT COUNT(*) FROM bookings b WHERE b.promotion_id = promotions.id AND b.status NOT IN ('cancelled', 'expired'))`
+	err := r.DB.QueryRow(ctx, query,
+		p.Code, p.Description, p.DiscountType, p.DiscountValue, p.MaxDiscount,
+		p.MovieID, p.CinemaID, p.PaymentMethodID, p.DaysOfWeek, p.MinSeats, p.UsageLimit, p.PerUserLimit,
+		p.ValidFrom, p.ValidUntil, p.IsActive, p.ID,
+	).Scan(&p.CreatedAt, &p.UpdatedAt, &p.UsedCount)
+	return p, err
+}
+
+// redeemPromotion locks a promotion inside a booking transaction, checks it is still
+// active and under its global and per-user limits, and returns the discount for the
+// subtotal. The lock serializes redemptions so a limit cannot be exceeded by racing bookings.
+func redeemPromotion(ctx context.Context, tx pgx.Tx, promotionID, userID int, subtotal float64) (float64, error) {
+	var p entity.Promotion
+	var usable bool
+	lockQuery := `SELECT discount_type, discount_value, max_discount, usage_limit, per_user_limit, 
+				  is_active AND NOW() BETWEEN valid_from AND valid_until 
+				  FROM promotions WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(ctx, lockQuery, promotionID).Scan(
+		&p.DiscountType, &p.DiscountValue, &p.MaxDiscount, &p.UsageLimit, &p.PerUserLimit, &usable,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrPromoUnavailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !usable {
+		return 0, ErrPromoUnavailable
+	}
+
+	var used, usedByUser int
+	usageQuery := `SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $2) 
+				   FROM bookings WHERE promotion_id = $1 AND status NOT IN ('cancelled', 'expired')`
+	if err := tx.QueryRow(ctx, usageQuery, promotionID, userID).Scan(&used, &usedByUser); err != nil {
+		return 0, err
+	}
+	if p.UsageLimit > 0 && used >= p.UsageLimit {
+		return 0, ErrPromoExhausted
+	}
+	if p.PerUserLimit > 0 && usedByUser >= p.PerUserLimit {
+		return 0, ErrPromoUserLimit
+	}
+
+	return p.Discount(subtotal), nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var promotionTestColumns = []string{
+	"id", "code", "description", "discount_type", "discount_value", "max_discount",
+	"movie_id", "cinema_id", "payment_method_id", "days_of_week", "min_seats",
+	"usage_limit", "per_user_limit", "used_count",
+	"valid_from", "valid_until", "is_active", "created_at", "updated_at",
+}
+
+func TestPromotionRepo_GetPromotionByCode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPromotionRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		cinemaID := 2
+		mock.ExpectQuery("SELECT (.+) FROM promotions p WHERE upper\\(p.code\\) = upper\\(\\$1\\)").
+			WithArgs("hemat10").
+			WillReturnRows(pgxmock.NewRows(promotionTestColumns).
+				AddRow(1, "HEMAT10", "10% off", "percentage", 10.0, 20000.0,
+					nil, &cinemaID, nil, []int{1, 2}, 2,
+					100, 1, 7,
+					now, now.Add(24*time.Hour), true, now, now))
+
+		promotion, err := repo.GetPromotionByCode(context.Background(), "hemat10")
+		assert.NoError(t, err)
+		assert.Equal(t, "HEMAT10", promotion.Code)
+		assert.Equal(t, &cinemaID, promotion.CinemaID)
+		assert.Nil(t, promotion.MovieID)
+		assert.Equal(t, []int{1, 2}, promotion.DaysOfWeek)
+		assert.Equal(t, 7, promotion.UsedCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM promotions p").
+			WithArgs("NOPE").
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetPromotionByCode(context.Background(), "NOPE")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPromotionRepo_GetPromotionByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPromotionRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		paymentMethodID := 2
+		mock.ExpectQuery("SELECT (.+) FROM promotions p WHERE p.id = \\$1").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(promotionTestColumns).
+				AddRow(1, "BCA20", "", "percentage", 20.0, 0.0,
+					nil, nil, &paymentMethodID, []int{}, 0,
+					0, 0, 0,
+					now, now.Add(24*time.Hour), true, now, now))
+
+		promotion, err := repo.GetPromotionByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, &paymentMethodID, promotion.PaymentMethodID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPromotionRepo_GetPromotions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPromotionRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM promotions").
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(11))
+		mock.ExpectQuery("SELECT (.+) FROM promotions p ORDER BY p.created_at DESC").
+			WithArgs(10, 10).
+			WillReturnRows(pgxmock.NewRows(promotionTestColumns).
+				AddRow(1, "HEMAT", "", "fixed", 15000.0, 0.0,
+					nil, nil, nil, []int{}, 0,
+					0, 0, 0,
+					now, now.Add(24*time.Hour), true, now, now))
+
+		promotions, pagination, err := repo.GetPromotions(context.Background(), 2, 10)
+		assert.NoError(t, err)
+		assert.Len(t, promotions, 1)
+		assert.Equal(t, 11, pagination.TotalRecords)
+		assert.Equal(t, 2, pagination.TotalPages)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM promotions").
+			WillReturnError(errors.New("database error"))
+
+		promotions, _, err := repo.GetPromotions(context.Background(), 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, promotions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPromotionRepo_CreatePromotion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPromotionRepo(mock)
+	now := time.Now()
+	promotion := entity.Promotion{
+		Code: "HEMAT", DiscountType: entity.PromotionFixed, DiscountValue: 15000,
+		DaysOfWeek: []int{}, ValidFrom: now, ValidUntil: now.Add(24 * time.Hour), IsActive: true,
+	}
+	args := []interface{}{
+		"HEMAT", "", "fixed", 15000.0, 0.0, promotion.MovieID, promotion.CinemaID, promotion.PaymentMethodID,
+		[]int{}, 0, 0, 0, promotion.ValidFrom, promotion.ValidUntil, true,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO promotions (.+) ON CONFLICT").
+			WithArgs(args...).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(4, now, now))
+
+		created, err := repo.CreatePromotion(context.Background(), promotion)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, created.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Code Exists", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO promotions (.+) ON CONFLICT").
+			WithArgs(args...).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.CreatePromotion(context.Background(), promotion)
+		assert.ErrorIs(t, err, ErrPromoCodeExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPromotionRepo_UpdatePromotion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewPromotionRepo(mock)
+	now := time.Now()
+	promotion := entity.Promotion{
+		ID: 4, Code: "HEMAT", DiscountType: entity.PromotionFixed, DiscountValue: 20000,
+		DaysOfWeek: []int{}, ValidFrom: now, ValidUntil: now.Add(24 * time.Hour),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS (.+) id <> \\$2").
+			WithArgs("HEMAT", 4).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectQuery("UPDATE promotions SET (.+) WHERE id = \\$16").
+			WithArgs(
+				"HEMAT", "", "fixed", 20000.0, 0.0, promotion.MovieID, promotion.CinemaID, promotion.PaymentMethodID,
+				[]int{}, 0, 0, 0, promotion.ValidFrom, promotion.ValidUntil, false, 4,
+			).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at", "used_count"}).AddRow(now, now, 12))
+
+		updated, err := repo.UpdatePromotion(context.Background(), promotion)
+		assert.NoError(t, err)
+		assert.Equal(t, 20000.0, updated.DiscountValue)
+		assert.Equal(t, 12, updated.UsedCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Code Taken", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS").
+			WithArgs("HEMAT", 4).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+		_, err := repo.UpdatePromotion(context.Background(), promotion)
+		assert.ErrorIs(t, err, ErrPromoCodeExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
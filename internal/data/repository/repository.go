@@ -2,22 +2,38 @@ package repository
 
 // Repository aggregates all repository interfaces
 type Repository struct {
-	Auth    AuthRepoInterface
-	Cinema  CinemaRepoInterface
-	Seat    SeatRepoInterface
-	Booking BookingRepoInterface
-	Payment PaymentRepoInterface
-	Movie   MovieRepoInterface
+	Auth        AuthRepoInterface
+	Cinema      CinemaRepoInterface
+	Seat        SeatRepoInterface
+	Booking     BookingRepoInterface
+	Payment     PaymentRepoInterface
+	Movie       MovieRepoInterface
+	Idempotency IdempotencyRepoInterface
+	Showtime    ShowtimeRepoInterface
+	Review      ReviewRepoInterface
+	Promotion   PromotionRepoInterface
+	Pricing     PricingRepoInterface
+	Loyalty     LoyaltyRepoInterface
+	Concession  ConcessionRepoInterface
+	Ticket      TicketRepoInterface
 }
 
 // NewRepository creates a new Repository instance with all sub-repositories
 func NewRepository(db DBPool) *Repository {
 	return &Repository{
-		Auth:    NewAuthRepo(db),
-		Cinema:  NewCinemaRepo(db),
-		Seat:    NewSeatRepo(db),
-		Booking: NewBookingRepo(db),
-		Payment: NewPaymentRepo(db),
-		Movie:   NewMovieRepo(db),
+		Auth:        NewAuthRepo(db),
+		Cinema:      NewCinemaRepo(db),
+		Seat:        NewSeatRepo(db),
+		Booking:     NewBookingRepo(db),
+		Payment:     NewPaymentRepo(db),
+		Movie:       NewMovieRepo(db),
+		Idempotency: NewIdempotencyRepo(db),
+		Showtime:    NewShowtimeRepo(db),
+		Review:      NewReviewRepo(db),
+		Promotion:   NewPromotionRepo(db),
+		Pricing:     NewPricingRepo(db),
+		Loyalty:     NewLoyaltyRepo(db),
+		Concession:  NewConcessionRepo(db),
+		Ticket:      NewTicketRepo(db),
 	}
 }
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReviewRepoInterface interface {
+	HasWatchedMovie(ctx context.Context, userID, movieID int) (bool, error)
+	GetReviewByID(ctx context.Context, id int) (entity.Review, error)
+	GetReviewsByMovie(ctx context.Context, movieID, page, limit int) ([]entity.Review, dto.Pagination, error)
+	CreateReview(ctx context.Context, review entity.Review) (entity.Review, error)
+	UpdateReview(ctx context.Context, review entity.Review) (entity.Review, error)
+	DeleteReview(ctx context.Context, review entity.Review) error
+}
+
+type ReviewRepo struct {
+	DB DBPool
+}
+
+func NewReviewRepo(db DBPool) ReviewRepoInterface {
+	return &ReviewRepo{DB: db}
+}
+
+// HasWatchedMovie reports whether a user has a paid booking for a showtime of the movie that already started
+func (r *ReviewRepo) HasWatchedMovie(ctx context.Context, userID, movieID int) (bool, error) {
+	query := `SELECT EXISTS (
+				SELECT 1 FROM bookings b 
+				JOIN showtimes st ON st.id = b.showtime_id 
+				WHERE b.user_id = $1 AND st.movie_id = $2 AND b.status = 'paid' 
+				AND st.show_date + st.show_time <= LOCALTIMESTAMP)`
+
+	var watched bool
+	if err := r.DB.QueryRow(ctx, query, userID, movieID).Scan(&watched); err != nil {
+		return false, err
+	}
+	return watched, nil
+}
+
+// GetReviewByID retrieves a review with its author's username
+func (r *ReviewRepo) GetReviewByID(ctx context.Context, id int) (entity.Review, error) {
+	query := `SELECT rv.id, rv.movie_id, rv.user_id, u.username, rv.rating, rv.comment, rv.created_at, rv.updated_at 
+			  FROM reviews rv 
+			  JOIN users u ON u.id = rv.user_id 
+			  WHERE rv.id = $1`
+
+	var rv entity.Review
+	err := r.DB.QueryRow(ctx, query, id).Scan(
+		&rv.ID, &rv.MovieID, &rv.UserID, &rv.Username, &rv.Rating, &rv.Comment, &rv.CreatedAt, &rv.UpdatedAt,
+	)
+	return rv, err
+}
+
+// GetReviewsByMovie retrieves the reviews of a movie, newest first, with pagination
+func (r *ReviewRepo) GetReviewsByMovie(ctx context.Context, movieID, page, limit int) ([]entity.Review, dto.Pagination, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM reviews WHERE movie_id = $1`
+	if err := r.DB.QueryRow(ctx, countQuery, movieID).Scan(&total); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	query := `SELECT rv.id, rv.movie_id, rv.user_id, u.username, rv.rating, rv.comment, rv.created_at, rv.updated_at 
+			  FROM reviews rv 
+			  JOIN users u ON u.id = rv.user_id 
+			  WHERE rv.movie_id = $1 
+			  ORDER BY rv.created_at DESC, rv.id DESC 
+			  LIMIT $2 OFFSET $3`
+	rows, err := r.DB.Query(ctx, query, movieID, limit, offset)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+	defer rows.Close()
+
+	var reviews []entity.Review
+	for rows.Next() {
+		var rv entity.Review
+		if err := rows.Scan(
+			&rv.ID, &rv.MovieID, &rv.UserID, &rv.Username, &rv.Rating, &rv.Comment, &rv.CreatedAt, &rv.UpdatedAt,
+		); err != nil {
+			return nil, dto.Pagination{}, err
+		}
+		reviews = append(reviews, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	pagination := dto.Pagination{
+		CurrentPage:  page,
+		TotalPages:   (total + limit - 1) / limit,
+		TotalRecords: total,
+		Limit:        limit,
+	}
+
+	return reviews, pagination, nil
+}
+
+// CreateReview stores a user's first review of a movie and recomputes the movie's rating
+func (r *ReviewRepo) CreateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return review, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockMovieForRating(ctx, tx, review.MovieID); err != nil {
+		return review, err
+	}
+
+	insertQuery := `INSERT INTO reviews (movie_id, user_id, rating, comment) 
+					VALUES ($1, $2, $3, $4) 
+					ON CONFLICT (movie_id, user_id) DO NOTHING 
+					RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, insertQuery, review.MovieID, review.UserID, review.Rating, review.Comment).
+		Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return review, ErrReviewExists
+	}
+	if err != nil {
+		return review, err
+	}
+
+	if err := refreshMovieRating(ctx, tx, review.MovieID); err != nil {
+		return review, err
+	}
+
+	return review, tx.Commit(ctx)
+}
+
+// UpdateReview replaces the rating and comment of a review and recomputes the movie's rating
+func (r *ReviewRepo) UpdateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return review, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockMovieForRating(ctx, tx, review.MovieID); err != nil {
+		return review, err
+	}
+
+	updateQuery := `UPDATE reviews SET rating = $1, comment = $2, updated_at = NOW() 
+					WHERE id = $3 
+					RETURNING created_at, updated_at`
+	err = tx.QueryRow(ctx, updateQuery, review.Rating, review.Comment, review.ID).
+		Scan(&review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		return review, err
+	}
+
+	if err := refreshMovieRating(ctx, tx, review.MovieID); err != nil {
+		return review, err
+	}
+
+	return review, tx.Commit(ctx)
+}
+
+// DeleteReview removes a review and recomputes the movie's rating
+func (r *ReviewRepo) DeleteReview(ctx context.Context, review entity.Review) error {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockMovieForRating(ctx, tx, review.MovieID); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM reviews WHERE id = $1`, review.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if err := refreshMovieRating(ctx, tx, review.MovieID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockMovieForRating serializes review changes per movie, so each recomputation sees
+// every review committed before it
+func lockMovieForRating(ctx context.Context, tx pgx.Tx, movieID int) error {
+	var lockedID int
+	return tx.QueryRow(ctx, `SELECT id FROM movies WHERE id = $1 FOR UPDATE`, movieID).Scan(&lockedID)
+}
+
+// refreshMovieRating recomputes a movie's average rating and review count from its reviews
+func refreshMovieRating(ctx context.Context, tx pgx.Tx, movieID int) error {
+	query := `UPDATE movies SET 
+			  rating = COALESCE((SELECT ROUND(AVG(rating), 1) FROM reviews WHERE movie_id = $1), 0), 
+			  review_count = (SELECT COUNT(*) FROM reviews WHERE movie_id = $1) 
+			  WHERE id = $1`
+	_, err := tx.Exec(ctx, query, movieID)
+	return err
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewRepo_HasWatchedMovie(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepo(mock)
+
+	t.Run("Success - Watched", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS (.+) b.status = 'paid' (.+) <= LOCALTIMESTAMP").
+			WithArgs(5, 1).
+			WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+		watched, err := repo.HasWatchedMovie(context.Background(), 5, 1)
+		assert.NoError(t, err)
+		assert.True(t, watched)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS").
+			WithArgs(5, 1).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.HasWatchedMovie(context.Background(), 5, 1)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepo_GetReviewsByMovie(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM reviews WHERE movie_id").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(12))
+		mock.ExpectQuery("SELECT (.+) FROM reviews rv JOIN users u (.+) ORDER BY rv.created_at DESC").
+			WithArgs(1, 10, 10).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "movie_id", "user_id", "username", "rating", "comment", "created_at", "updated_at",
+			}).
+				AddRow(3, 1, 5, "budi", 5, "Great", now, now).
+				AddRow(2, 1, 6, "sari", 3, "", now, now))
+
+		reviews, pagination, err := repo.GetReviewsByMovie(context.Background(), 1, 2, 10)
+		assert.NoError(t, err)
+		assert.Len(t, reviews, 2)
+		assert.Equal(t, "budi", reviews[0].Username)
+		assert.Equal(t, 12, pagination.TotalRecords)
+		assert.Equal(t, 2, pagination.TotalPages)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepo_CreateReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepo(mock)
+	review := entity.Review{MovieID: 1, UserID: 5, Rating: 4, Comment: "Nice"}
+
+	t.Run("Success - Recomputes Movie Rating", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies WHERE id = (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("INSERT INTO reviews (.+) ON CONFLICT \\(movie_id, user_id\\) DO NOTHING").
+			WithArgs(1, 5, 4, "Nice").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(9, now, now))
+		mock.ExpectExec("UPDATE movies SET rating = (.+)AVG\\(rating\\)(.+)review_count = \\(SELECT COUNT").
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		created, err := repo.CreateReview(context.Background(), review)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, created.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Already Reviewed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("INSERT INTO reviews").
+			WithArgs(1, 5, 4, "Nice").
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.CreateReview(context.Background(), review)
+		assert.ErrorIs(t, err, ErrReviewExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepo_UpdateReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("UPDATE reviews SET rating = (.+) WHERE id").
+			WithArgs(2, "Changed my mind", 9).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+		mock.ExpectExec("UPDATE movies SET").
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		updated, err := repo.UpdateReview(context.Background(), entity.Review{ID: 9, MovieID: 1, Rating: 2, Comment: "Changed my mind"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, updated.Rating)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepo_DeleteReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("DELETE FROM reviews WHERE id").
+			WithArgs(9).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec("UPDATE movies SET").
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteReview(context.Background(), entity.Review{ID: 9, MovieID: 1})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("DELETE FROM reviews WHERE id").
+			WithArgs(9).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectRollback()
+
+		err := repo.DeleteReview(context.Background(), entity.Review{ID: 9, MovieID: 1})
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"project-app-bioskop/internal/data/entity"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type SeatRepoInterface interface {
 	GetSeatsByShowtime(ctx context.Context, showtimeID int) ([]entity.SeatAvailability, error)
-	GetShowtimeByParams(ctx context.Context, cinemaID int, date, time string) (entity.Showtime, error)
 	GetShowtimeByID(ctx context.Context, id int) (entity.Showtime, error)
 	GetSeatsByIDs(ctx context.Context, seatIDs []int) ([]entity.Seat, error)
 	CheckSeatsAvailable(ctx context.Context, showtimeID int, seatIDs []int) (bool, error)
@@ -22,23 +24,39 @@ func NewSeatRepo(db DBPool) SeatRepoInterface {
 	return &SeatRepo{DB: db}
 }
 
-// GetSeatsByShowtime retrieves all seats with availability status for a showtime
+// GetSeatsByShowtime retrieves all seats with position, state and price for a showtime.
+// Prices use the showtime price resolved by the pricing rules.
 func (r *SeatRepo) GetSeatsByShowtime(ctx context.Context, showtimeID int) ([]entity.SeatAvailability, error) {
+	showtimePrice, err := resolveShowtimePrice(ctx, r.DB, showtimeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT DISTINCT s.id, s.seat_code, s.studio_id,
-			   CASE WHEN EXISTS (
-			   		SELECT 1 FROM booking_seats bs
+		SELECT s.id, s.seat_code, s.studio_id,
+			   COALESCE(s.row_label, '') as row_label,
+			   COALESCE(s.column_number, 0) as column_number,
+			   s.category,
+			   ROUND($2 * c.price_multiplier, 2) as price,
+			   COALESCE((
+			   		SELECT CASE WHEN bool_or(b.status = 'paid') THEN 'booked' ELSE 'held' END
+			   		FROM booking_seats bs
 			   		INNER JOIN bookings b ON b.id = bs.booking_id
 			   		WHERE bs.seat_id = s.id 
 			   		AND b.showtime_id = $1 
-			   		AND b.status != 'cancelled'
-			   ) THEN true ELSE false END as is_booked
+			   		AND b.status NOT IN ('cancelled', 'expired')
+			   		HAVING COUNT(*) > 0
+			   ), 'available') as state
 		FROM seats s
 		INNER JOIN showtimes st ON st.studio_id = s.studio_id
+		INNER JOIN seat_categories c ON c.name = s.category
 		WHERE st.id = $1
-		ORDER BY s.seat_code`
+		ORDER BY row_label, column_number, s.seat_code`
 
-	rows, err := r.DB.Query(ctx, query, showtimeID)
+	rows, err := r.DB.Query(ctx, query, showtimeID, showtimePrice)
 	if err != nil {
 		return nil, err
 	}
@@ -47,41 +65,25 @@ func (r *SeatRepo) GetSeatsByShowtime(ctx context.Context, showtimeID int) ([]en
 	var seats []entity.SeatAvailability
 	for rows.Next() {
 		var s entity.SeatAvailability
-		if err := rows.Scan(&s.ID, &s.SeatCode, &s.StudioID, &s.IsBooked); err != nil {
+		if err := rows.Scan(
+			&s.ID, &s.SeatCode, &s.StudioID, &s.RowLabel, &s.ColumnNumber, &s.Category, &s.Price, &s.State,
+		); err != nil {
 			return nil, err
 		}
+		s.IsBooked = s.State != entity.SeatAvailable
 		seats = append(seats, s)
 	}
 	return seats, nil
 }
 
-// GetShowtimeByParams retrieves showtime by cinema, date, and time
-func (r *SeatRepo) GetShowtimeByParams(ctx context.Context, cinemaID int, date, time string) (entity.Showtime, error) {
-	query := `SELECT id, cinema_id, studio_id, movie_id, 
-			  show_date::text as show_date, 
-			  show_time::text as show_time, 
-			  price 
-			  FROM showtimes 
-			  WHERE cinema_id = $1 AND show_date::text = $2 AND show_time::text = $3`
-	var st entity.Showtime
-	err := r.DB.QueryRow(ctx, query, cinemaID, date, time).Scan(
-		&st.ID, &st.CinemaID, &st.StudioID, &st.MovieID,
-		&st.ShowDate, &st.ShowTime, &st.Price,
-	)
-	if err != nil {
-		return st, err
-	}
-	return st, nil
-}
-
 // GetShowtimeByID retrieves showtime by ID with movie and studio details
 func (r *SeatRepo) GetShowtimeByID(ctx context.Context, id int) (entity.Showtime, error) {
 	query := `SELECT 
 				st.id, st.cinema_id, st.studio_id, st.movie_id, 
 			  	st.show_date::text as show_date, 
 			  	st.show_time::text as show_time, 
-			  	st.price,
-				m.id, m.title, m.poster_url, m.genres, m.rating, m.duration_in_minutes,
+			  	st.price, st.status,
+				m.id, m.title, m.poster_url, m.genres, m.rating, m.duration_in_minutes, m.age_rating,
 				s.id, s.name, s.total_seats
 			  FROM showtimes st
 			  INNER JOIN movies m ON m.id = st.movie_id
@@ -94,8 +96,8 @@ func (r *SeatRepo) GetShowtimeByID(ctx context.Context, id int) (entity.Showtime
 
 	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&st.ID, &st.CinemaID, &st.StudioID, &st.MovieID,
-		&st.ShowDate, &st.ShowTime, &st.Price,
-		&movie.ID, &movie.Title, &movie.PosterURL, &movie.Genres, &movie.Rating, &movie.DurationMinutes,
+		&st.ShowDate, &st.ShowTime, &st.Price, &st.Status,
+		&movie.ID, &movie.Title, &movie.PosterURL, &movie.Genres, &movie.Rating, &movie.DurationMinutes, &movie.AgeRating,
 		&studio.ID, &studio.Name, &studio.TotalSeats,
 	)
 	if err != nil {
@@ -109,7 +111,7 @@ func (r *SeatRepo) GetShowtimeByID(ctx context.Context, id int) (entity.Showtime
 
 // GetSeatsByIDs retrieves seats by their IDs
 func (r *SeatRepo) GetSeatsByIDs(ctx context.Context, seatIDs []int) ([]entity.Seat, error) {
-	query := `SELECT id, studio_id, seat_code FROM seats WHERE id = ANY($1)`
+	query := `SELECT id, studio_id, seat_code, category FROM seats WHERE id = ANY($1)`
 	rows, err := r.DB.Query(ctx, query, seatIDs)
 	if err != nil {
 		return nil, err
@@ -119,7 +121,7 @@ func (r *SeatRepo) GetSeatsByIDs(ctx context.Context, seatIDs []int) ([]entity.S
 	var seats []entity.Seat
 	for rows.Next() {
 		var s entity.Seat
-		if err := rows.Scan(&s.ID, &s.StudioID, &s.SeatCode); err != nil {
+		if err := rows.Scan(&s.ID, &s.StudioID, &s.SeatCode, &s.Category); err != nil {
 			return nil, err
 		}
 		seats = append(seats, s)
@@ -132,7 +134,7 @@ func (r *SeatRepo) CheckSeatsAvailable(ctx context.Context, showtimeID int, seat
 	query := `
 		SELECT COUNT(*) FROM booking_seats bs
 		INNER JOIN bookings b ON b.id = bs.booking_id
-		WHERE b.showtime_id = $1 AND bs.seat_id = ANY($2) AND b.status != 'cancelled'`
+		WHERE b.showtime_id = $1 AND bs.seat_id = ANY($2) AND b.status NOT IN ('cancelled', 'expired')`
 	var count int
 	err := r.DB.QueryRow(ctx, query, showtimeID, seatIDs).Scan(&count)
 	if err != nil {
@@ -141,7 +143,7 @@ func (r *SeatRepo) CheckSeatsAvailable(ctx context.Context, showtimeID int, seat
 	return count == 0, nil
 }
 
-// GetShowtimesByCinema retrieves all showtimes for a cinema
+// GetShowtimesByCinema retrieves all scheduled showtimes for a cinema
 func (r *SeatRepo) GetShowtimesByCinema(ctx context.Context, cinemaID int) ([]entity.Showtime, error) {
 	query := `SELECT 
 				st.id, st.cinema_id, st.studio_id, st.movie_id, 
@@ -153,7 +155,7 @@ func (r *SeatRepo) GetShowtimesByCinema(ctx context.Context, cinemaID int) ([]en
 			  FROM showtimes st
 			  INNER JOIN movies m ON m.id = st.movie_id
 			  INNER JOIN studios s ON s.id = st.studio_id
-			  WHERE st.cinema_id = $1 
+			  WHERE st.cinema_id = $1 AND st.status = 'scheduled'
 			  ORDER BY st.show_date, st.show_time`
 	rows, err := r.DB.Query(ctx, query, cinemaID)
 	if err != nil {
@@ -21,14 +21,15 @@ func TestSeatRepo_GetSeatsByShowtime(t *testing.T) {
 
 	t.Run("Success - Get Seats", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "seat_code", "studio_id", "is_booked",
+			"id", "seat_code", "studio_id", "row_label", "column_number", "category", "price", "state",
 		}).
-			AddRow(1, "A1", 1, false).
-			AddRow(2, "A2", 1, true).
-			AddRow(3, "A3", 1, false)
+			AddRow(1, "A1", 1, "A", 1, "regular", 50000.0, "available").
+			AddRow(2, "A2", 1, "A", 2, "regular", 50000.0, "held").
+			AddRow(3, "A3", 1, "A", 4, "vip", 75000.0, "available")
 
-		mock.ExpectQuery("SELECT DISTINCT").
-			WithArgs(1).
+		expectShowtimePrice(mock, 1, 50000)
+		mock.ExpectQuery("SELECT s.id, s.seat_code(.+)FROM seats s").
+			WithArgs(1, 50000.0).
 			WillReturnRows(rows)
 
 		seats, err := repo.GetSeatsByShowtime(context.Background(), 1)
@@ -37,69 +38,49 @@ func TestSeatRepo_GetSeatsByShowtime(t *testing.T) {
 		assert.Equal(t, "A1", seats[0].SeatCode)
 		assert.False(t, seats[0].IsBooked)
 		assert.True(t, seats[1].IsBooked)
+		assert.Equal(t, "held", seats[1].State)
+		assert.Equal(t, 4, seats[2].ColumnNumber)
+		assert.Equal(t, "vip", seats[2].Category)
+		assert.Equal(t, 75000.0, seats[2].Price)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Empty Result", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "seat_code", "studio_id", "is_booked",
+			"id", "seat_code", "studio_id", "row_label", "column_number", "category", "price", "state",
 		})
 
-		mock.ExpectQuery("SELECT DISTINCT").
-			WithArgs(999).
+		expectShowtimePrice(mock, 2, 50000)
+		mock.ExpectQuery("SELECT s.id, s.seat_code(.+)FROM seats s").
+			WithArgs(2, 50000.0).
 			WillReturnRows(rows)
 
-		seats, err := repo.GetSeatsByShowtime(context.Background(), 999)
+		seats, err := repo.GetSeatsByShowtime(context.Background(), 2)
 		assert.NoError(t, err)
 		assert.Len(t, seats, 0)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Error - Database Error", func(t *testing.T) {
-		mock.ExpectQuery("SELECT DISTINCT").
-			WithArgs(1).
-			WillReturnError(errors.New("database error"))
-
-		seats, err := repo.GetSeatsByShowtime(context.Background(), 1)
-		assert.Error(t, err)
-		assert.Nil(t, seats)
-		assert.NoError(t, mock.ExpectationsWereMet())
-	})
-}
-
-func TestSeatRepo_GetShowtimeByParams(t *testing.T) {
-	mock, err := pgxmock.NewPool()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer mock.Close()
-
-	repo := NewSeatRepo(mock)
-
-	t.Run("Success - Showtime Found", func(t *testing.T) {
-		rows := pgxmock.NewRows([]string{
-			"id", "cinema_id", "studio_id", "movie_id", "show_date", "show_time", "price",
-		}).AddRow(1, 1, 1, 1, "2024-01-15", "19:00", 50000.0)
-
-		mock.ExpectQuery("SELECT (.+) FROM showtimes WHERE cinema_id").
-			WithArgs(1, "2024-01-15", "19:00").
-			WillReturnRows(rows)
+	t.Run("Showtime Not Found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT st.price, st.cinema_id(.+) FROM showtimes st").
+			WithArgs(999).
+			WillReturnError(pgx.ErrNoRows)
 
-		showtime, err := repo.GetShowtimeByParams(context.Background(), 1, "2024-01-15", "19:00")
+		seats, err := repo.GetSeatsByShowtime(context.Background(), 999)
 		assert.NoError(t, err)
-		assert.Equal(t, 1, showtime.ID)
-		assert.Equal(t, 50000.0, showtime.Price)
+		assert.Len(t, seats, 0)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Not Found", func(t *testing.T) {
-		mock.ExpectQuery("SELECT (.+) FROM showtimes WHERE cinema_id").
-			WithArgs(999, "2024-01-15", "19:00").
-			WillReturnError(pgx.ErrNoRows)
+	t.Run("Error - Database Error", func(t *testing.T) {
+		expectShowtimePrice(mock, 1, 50000)
+		mock.ExpectQuery("SELECT s.id, s.seat_code(.+)FROM seats s").
+			WithArgs(1, 50000.0).
+			WillReturnError(errors.New("database error"))
 
-		showtime, err := repo.GetShowtimeByParams(context.Background(), 999, "2024-01-15", "19:00")
+		seats, err := repo.GetSeatsByShowtime(context.Background(), 1)
 		assert.Error(t, err)
-		assert.Equal(t, 0, showtime.ID)
+		assert.Nil(t, seats)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -178,10 +159,10 @@ func TestSeatRepo_GetSeatsByIDs(t *testing.T) {
 
 	t.Run("Success - Get Seats by IDs", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "studio_id", "seat_code",
+			"id", "studio_id", "seat_code", "category",
 		}).
-			AddRow(1, 1, "A1").
-			AddRow(2, 1, "A2")
+			AddRow(1, 1, "A1", "regular").
+			AddRow(2, 1, "A2", "couple")
 
 		mock.ExpectQuery("SELECT (.+) FROM seats WHERE id = ANY").
 			WithArgs([]int{1, 2}).
@@ -196,7 +177,7 @@ func TestSeatRepo_GetSeatsByIDs(t *testing.T) {
 
 	t.Run("Empty IDs", func(t *testing.T) {
 		rows := pgxmock.NewRows([]string{
-			"id", "studio_id", "seat_code",
+			"id", "studio_id", "seat_code", "category",
 		})
 
 		mock.ExpectQuery("SELECT (.+) FROM seats WHERE id = ANY").
@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-bioskop/internal/data/entity"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ShowtimeRepoInterface interface {
+	CreateShowtime(ctx context.Context, showtime entity.Showtime, bufferMinutes int) (entity.Showtime, error)
+	RescheduleShowtime(ctx context.Context, showtime entity.Showtime, bufferMinutes int) (entity.Showtime, []entity.Booking, error)
+	CancelShowtime(ctx context.Context, id int, reason string) ([]entity.Booking, []entity.Refund, error)
+	SearchShowtimes(ctx context.Context, filter entity.ShowtimeFilter) ([]entity.Showtime, error)
+	GetMovieSchedule(ctx context.Context, movieID int, filter entity.MovieScheduleFilter) ([]entity.ShowtimeListing, error)
+}
+
+type ShowtimeRepo struct {
+	DB DBPool
+}
+
+func NewShowtimeRepo(db DBPool) ShowtimeRepoInterface {
+	return &ShowtimeRepo{DB: db}
+}
+
+// CreateShowtime schedules a movie in a studio unless it overlaps another showtime there
+func (r *ShowtimeRepo) CreateShowtime(ctx context.Context, showtime entity.Showtime, bufferMinutes int) (entity.Showtime, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return showtime, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize scheduling per studio
+	studioQuery := `SELECT cinema_id FROM studios WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, studioQuery, showtime.StudioID).Scan(&showtime.CinemaID); err != nil {
+		return showtime, err
+	}
+
+	// Keep the movie from being deleted until the showtime is stored
+	var duration int
+	movieQuery := `SELECT duration_in_minutes FROM movies WHERE id = $1 AND deleted_at IS NULL FOR SHARE`
+	if err := tx.QueryRow(ctx, movieQuery, showtime.MovieID).Scan(&duration); err != nil {
+		return showtime, err
+	}
+
+	if err := checkStudioConflict(ctx, tx, showtime, duration, bufferMinutes); err != nil {
+		return showtime, err
+	}
+
+	insertQuery := `INSERT INTO showtimes (cinema_id, studio_id, movie_id, show_date, show_time, price)
+					VALUES ($1, $2, $3, $4::date, $5::time, $6) RETURNING id, status`
+	err = tx.QueryRow(ctx, insertQuery,
+		showtime.CinemaID, showtime.StudioID, showtime.MovieID, showtime.ShowDate, showtime.ShowTime, showtime.Price,
+	).Scan(&showtime.ID, &showtime.Status)
+	if err != nil {
+		return showtime, err
+	}
+
+	return showtime, tx.Commit(ctx)
+}
+
+// RescheduleShowtime moves a scheduled showtime to a new start and price.
+// It returns the active bookings so their holders can be told about the change.
+func (r *ShowtimeRepo) RescheduleShowtime(ctx context.Context, showtime entity.Showtime, bufferMinutes int) (entity.Showtime, []entity.Booking, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return showtime, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var duration int
+	lockQuery := `SELECT st.studio_id, st.movie_id, m.duration_in_minutes
+				  FROM showtimes st
+				  INNER JOIN movies m ON m.id = st.movie_id
+				  WHERE st.id = $1 AND st.status = 'scheduled'
+				  FOR UPDATE OF st`
+	if err := tx.QueryRow(ctx, lockQuery, showtime.ID).Scan(&showtime.StudioID, &showtime.MovieID, &duration); err != nil {
+		return showtime, nil, err
+	}
+
+	var lockedID int
+	studioQuery := `SELECT id FROM studios WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, studioQuery, showtime.StudioID).Scan(&lockedID); err != nil {
+		return showtime, nil, err
+	}
+
+	if err := checkStudioConflict(ctx, tx, showtime, duration, bufferMinutes); err != nil {
+		return showtime, nil, err
+	}
+
+	// A zero price keeps the current one
+	updateQuery := `UPDATE showtimes
+					SET show_date = $2::date, show_time = $3::time, price = COALESCE(NULLIF($4, 0), price)
+					WHERE id = $1
+					RETURNING cinema_id, price, status`
+	err = tx.QueryRow(ctx, updateQuery, showtime.ID, showtime.ShowDate, showtime.ShowTime, showtime.Price).
+		Scan(&showtime.CinemaID, &showtime.Price, &showtime.Status)
+	if err != nil {
+		return showtime, nil, err
+	}
+
+	bookingQuery := `SELECT id, user_id, showtime_id, status, total_amount, expires_at, created_at
+					 FROM bookings
+					 WHERE showtime_id = $1 AND status IN ('pending', 'paid')
+					 ORDER BY id`
+	rows, err := tx.Query(ctx, bookingQuery, showtime.ID)
+	if err != nil {
+		return showtime, nil, err
+	}
+	bookings, err := scanBookings(rows)
+	if err != nil {
+		return showtime, nil, err
+	}
+
+	return showtime, bookings, tx.Commit(ctx)
+}
+
+// CancelShowtime cancels a scheduled showtime together with its pending and paid bookings.
+// Paid bookings get a pending refund for their completed payment, and the loyalty points
+// and concessions of every cancelled booking are reversed. The returned bookings carry the status they
+// had before cancellation.
+func (r *ShowtimeRepo) CancelShowtime(ctx context.Context, id int, reason string) ([]entity.Booking, []entity.Refund, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Waits for in-flight bookings and stops new ones
+	var cancelledID int
+	cancelQuery := `UPDATE showtimes SET status = 'cancelled', cancelled_at = NOW()
+					WHERE id = $1 AND status = 'scheduled' RETURNING id`
+	if err := tx.QueryRow(ctx, cancelQuery, id).Scan(&cancelledID); err != nil {
+		return nil, nil, err
+	}
+
+	bookingQuery := `
+		WITH affected AS (
+			SELECT id, status FROM bookings
+			WHERE showtime_id = $1 AND status IN ('pending', 'paid')
+			FOR UPDATE
+		)
+		UPDATE bookings b SET status = 'cancelled'
+		FROM affected a
+		WHERE b.id = a.id
+		RETURNING b.id, b.user_id, b.showtime_id, a.status, b.total_amount, b.expires_at, b.created_at`
+	rows, err := tx.Query(ctx, bookingQuery, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookings, err := scanBookings(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bookingIDs, paidIDs []int
+	for _, b := range bookings {
+		bookingIDs = append(bookingIDs, b.ID)
+		if b.Status == "paid" {
+			paidIDs = append(paidIDs, b.ID)
+		}
+	}
+
+	var refunds []entity.Refund
+	if len(paidIDs) > 0 {
+		refundQuery := `
+			INSERT INTO refunds (payment_id, booking_id, amount, status, reason)
+			SELECT DISTINCT ON (p.booking_id) p.id, p.booking_id, b.total_amount, 'pending', $2
+			FROM payments p
+			INNER JOIN bookings b ON b.id = p.booking_id
+			WHERE p.booking_id = ANY($1) AND p.status = 'completed'
+			ORDER BY p.booking_id, p.id DESC
+			ON CONFLICT (payment_id) DO NOTHING
+			RETURNING id, payment_id, booking_id, amount, status`
+		rows, err := tx.Query(ctx, refundQuery, paidIDs, reason)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var rf entity.Refund
+			if err := rows.Scan(&rf.ID, &rf.PaymentID, &rf.BookingID, &rf.Amount, &rf.Status); err != nil {
+				return nil, nil, err
+			}
+			refunds = append(refunds, rf)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := reverseLoyalty(ctx, tx, bookingIDs); err != nil {
+		return nil, nil, err
+	}
+	if err := releaseConcessions(ctx, tx, bookingIDs); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return bookings, refunds, nil
+}
+
+// SearchShowtimes lists scheduled showtimes across cinemas and studios matching the filter
+func (r *ShowtimeRepo) SearchShowtimes(ctx context.Context, filter entity.ShowtimeFilter) ([]entity.Showtime, error) {
+	conditions := []string{"st.status = 'scheduled'"}
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.CinemaID > 0 {
+		add("st.cinema_id = $%d", filter.CinemaID)
+	}
+	if filter.MovieID > 0 {
+		add("st.movie_id = $%d", filter.MovieID)
+	}
+	if filter.ShowDate != "" {
+		add("st.show_date = $%d::date", filter.ShowDate)
+	} else {
+		conditions = append(conditions, "st.show_date >= CURRENT_DATE")
+	}
+	if filter.ShowTime != "" {
+		add("st.show_time = $%d::time", filter.ShowTime)
+	}
+	if filter.TimeFrom != "" {
+		add("st.show_time >= $%d::time", filter.TimeFrom)
+	}
+	if filter.TimeTo != "" {
+		add("st.show_time <= $%d::time", filter.TimeTo)
+	}
+
+	query := `SELECT 
+				st.id, st.cinema_id, st.studio_id, st.movie_id, 
+			  	st.show_date::text as show_date, 
+			  	st.show_time::text as show_time, 
+			  	st.price, st.status,
+				m.id, m.title, m.poster_url, m.genres, m.rating, m.duration_in_minutes,
+				s.id, s.name, s.total_seats
+			  FROM showtimes st
+			  INNER JOIN movies m ON m.id = st.movie_id
+			  INNER JOIN studios s ON s.id = st.studio_id
+			  WHERE ` + strings.Join(conditions, " AND ") + `
+			  ORDER BY st.show_date, st.show_time, st.cinema_id, st.studio_id`
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var showtimes []entity.Showtime
+	for rows.Next() {
+		var st entity.Showtime
+		var movie entity.Movie
+		var studio entity.Studio
+
+		if err := rows.Scan(
+			&st.ID, &st.CinemaID, &st.StudioID, &st.MovieID,
+			&st.ShowDate, &st.ShowTime, &st.Price, &st.Status,
+			&movie.ID, &movie.Title, &movie.PosterURL, &movie.Genres, &movie.Rating, &movie.DurationMinutes,
+			&studio.ID, &studio.Name, &studio.TotalSeats,
+		); err != nil {
+			return nil, err
+		}
+
+		st.Movie = &movie
+		st.Studio = &studio
+		showtimes = append(showtimes, st)
+	}
+	return showtimes, rows.Err()
+}
+
+// GetMovieSchedule lists a movie's showtimes that have not started yet with their cinema
+// and remaining seats, ordered by date, cinema and time
+func (r *ShowtimeRepo) GetMovieSchedule(ctx context.Context, movieID int, filter entity.MovieScheduleFilter) ([]entity.ShowtimeListing, error) {
+	conditions := []string{
+		"st.movie_id = $1",
+		"st.status = 'scheduled'",
+		"st.show_date + st.show_time > LOCALTIMESTAMP",
+	}
+	args := []any{movieID}
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.DateFrom != "" {
+		add("st.show_date >= $%d::date", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		add("st.show_date <= $%d::date", filter.DateTo)
+	}
+	if filter.Location != "" {
+		add("c.location ILIKE '%%' || $%d || '%%'", likeEscaper.Replace(filter.Location))
+	}
+
+	query := `SELECT 
+				st.id, st.cinema_id, st.studio_id, st.movie_id, 
+			  	st.show_date::text as show_date, 
+			  	st.show_time::text as show_time, 
+			  	st.price, st.status,
+				c.id, c.name, c.location,
+				s.id, s.name, s.total_seats,
+				(
+					SELECT COUNT(*) FROM seats se
+					WHERE se.studio_id = st.studio_id
+					AND NOT EXISTS (
+						SELECT 1 FROM booking_seats bs
+						INNER JOIN bookings b ON b.id = bs.booking_id
+						WHERE bs.seat_id = se.id AND b.showtime_id = st.id
+						AND b.status NOT IN ('cancelled', 'expired')
+					)
+				) as remaining_seats
+			  FROM showtimes st
+			  INNER JOIN cinemas c ON c.id = st.cinema_id
+			  INNER JOIN studios s ON s.id = st.studio_id
+			  WHERE ` + strings.Join(conditions, " AND ") + `
+			  ORDER BY st.show_date, c.name, c.id, st.show_time, s.name`
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listings []entity.ShowtimeListing
+	for rows.Next() {
+		var l entity.ShowtimeListing
+		var studio entity.Studio
+
+		if err := rows.Scan(
+			&l.Showtime.ID, &l.Showtime.CinemaID, &l.Showtime.StudioID, &l.Showtime.MovieID,
+			&l.Showtime.ShowDate, &l.Showtime.ShowTime, &l.Showtime.Price, &l.Showtime.Status,
+			&l.Cinema.ID, &l.Cinema.Name, &l.Cinema.Location,
+			&studio.ID, &studio.Name, &studio.TotalSeats,
+			&l.RemainingSeats,
+		); err != nil {
+			return nil, err
+		}
+
+		l.Showtime.Studio = &studio
+		listings = append(listings, l)
+	}
+	return listings, rows.Err()
+}
+
+// checkStudioConflict returns ErrShowtimeConflict when another scheduled showtime in the studio
+// overlaps the running time of the given one, each padded with the cleaning buffer
+func checkStudioConflict(ctx context.Context, tx pgx.Tx, showtime entity.Showtime, duration, bufferMinutes int) error {
+	query := `
+		SELECT st.id, st.show_date::text, st.show_time::text
+		FROM showtimes st
+		INNER JOIN movies m ON m.id = st.movie_id
+		WHERE st.studio_id = $1 AND st.status = 'scheduled' AND st.id <> $2
+		AND st.show_date + st.show_time < $3::date + $4::time + make_interval(mins => $5::int + $6::int)
+		AND $3::date + $4::time < st.show_date + st.show_time + make_interval(mins => m.duration_in_minutes + $6::int)
+		ORDER BY st.show_date, st.show_time
+		LIMIT 1`
+
+	var conflictID int
+	var date, start string
+	err := tx.QueryRow(ctx, query,
+		showtime.StudioID, showtime.ID, showtime.ShowDate, showtime.ShowTime, duration, bufferMinutes,
+	).Scan(&conflictID, &date, &start)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: showtime %d at %s %s", ErrShowtimeConflict, conflictID, date, start)
+}
+
+// scanBookings reads booking rows and closes them
+func scanBookings(rows pgx.Rows) ([]entity.Booking, error) {
+	defer rows.Close()
+
+	var bookings []entity.Booking
+	for rows.Next() {
+		var b entity.Booking
+		if err := rows.Scan(&b.ID, &b.UserID, &b.ShowtimeID, &b.Status, &b.TotalAmount, &b.ExpiresAt, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, rows.Err()
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowtimeRepo_CreateShowtime(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewShowtimeRepo(mock)
+	showtime := entity.Showtime{StudioID: 1, MovieID: 2, ShowDate: "2030-01-15", ShowTime: "19:00", Price: 50000}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT cinema_id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"cinema_id"}).AddRow(3))
+		mock.ExpectQuery("SELECT duration_in_minutes FROM movies (.+) FOR SHARE").
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows([]string{"duration_in_minutes"}).AddRow(120))
+		mock.ExpectQuery("SELECT st.id, st.show_date::text, st.show_time::text FROM showtimes st").
+			WithArgs(1, 0, "2030-01-15", "19:00", 120, 15).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("INSERT INTO showtimes").
+			WithArgs(3, 1, 2, "2030-01-15", "19:00", 50000.0).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "status"}).AddRow(11, "scheduled"))
+		mock.ExpectCommit()
+
+		created, err := repo.CreateShowtime(context.Background(), showtime, 15)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, created.ID)
+		assert.Equal(t, 3, created.CinemaID)
+		assert.Equal(t, entity.ShowtimeScheduled, created.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Overlaps Existing Showtime", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT cinema_id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"cinema_id"}).AddRow(3))
+		mock.ExpectQuery("SELECT duration_in_minutes FROM movies (.+) FOR SHARE").
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows([]string{"duration_in_minutes"}).AddRow(120))
+		mock.ExpectQuery("SELECT st.id, st.show_date::text, st.show_time::text FROM showtimes st").
+			WithArgs(1, 0, "2030-01-15", "19:00", 120, 15).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "show_date", "show_time"}).AddRow(7, "2030-01-15", "18:00:00"))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateShowtime(context.Background(), showtime, 15)
+		assert.ErrorIs(t, err, ErrShowtimeConflict)
+		assert.Contains(t, err.Error(), "showtime 7 at 2030-01-15 18:00:00")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Movie Deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT cinema_id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"cinema_id"}).AddRow(3))
+		mock.ExpectQuery("SELECT duration_in_minutes FROM movies (.+) FOR SHARE").
+			WithArgs(2).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.CreateShowtime(context.Background(), showtime, 15)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestShowtimeRepo_RescheduleShowtime(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewShowtimeRepo(mock)
+	showtime := entity.Showtime{ID: 4, ShowDate: "2030-01-16", ShowTime: "21:00"}
+
+	t.Run("Success - Returns Active Bookings", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT st.studio_id, st.movie_id, m.duration_in_minutes (.+) FOR UPDATE OF st").
+			WithArgs(4).
+			WillReturnRows(pgxmock.NewRows([]string{"studio_id", "movie_id", "duration_in_minutes"}).AddRow(1, 2, 90))
+		mock.ExpectQuery("SELECT id FROM studios WHERE id (.+) FOR UPDATE").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT st.id, st.show_date::text, st.show_time::text FROM showtimes st").
+			WithArgs(1, 4, "2030-01-16", "21:00", 90, 10).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectQuery("UPDATE showtimes").
+			WithArgs(4, "2030-01-16", "21:00", 0.0).
+			WillReturnRows(pgxmock.NewRows([]string{"cinema_id", "price", "status"}).AddRow(3, 50000.0, "scheduled"))
+		mock.ExpectQuery("SELECT id, user_id, showtime_id, status, total_amount, expires_at, created_at FROM bookings").
+			WithArgs(4).
+			WillReturnRows(pgxmock.NewRows([]string{
+				"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at",
+			}).AddRow(5, 1, 4, "paid", 100000.0, nil, now))
+		mock.ExpectCommit()
+
+		updated, bookings, err := repo.RescheduleShowtime(context.Background(), showtime, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 50000.0, updated.Price)
+		assert.Len(t, bookings, 1)
+		assert.Equal(t, 1, bookings[0].UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not Found Or Cancelled", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT st.studio_id, st.movie_id, m.duration_in_minutes (.+) FOR UPDATE OF st").
+			WithArgs(4).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, _, err := repo.RescheduleShowtime(context.Background(), showtime, 10)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestShowtimeRepo_CancelShowtime(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewShowtimeRepo(mock)
+	bookingColumns := []string{"id", "user_id", "showtime_id", "status", "total_amount", "expires_at", "created_at"}
+
+	t.Run("Success - Cascades Bookings And Refunds Paid", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE showtimes SET status = 'cancelled'").
+			WithArgs(4).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectQuery("UPDATE bookings b SET status = 'cancelled'").
+			WithArgs(4).
+			WillReturnRows(pgxmock.NewRows(bookingColumns).
+				AddRow(5, 1, 4, "paid", 100000.0, nil, now).
+				AddRow(6, 2, 4, "pending", 50000.0, &now, now))
+		mock.ExpectQuery("INSERT INTO refunds").
+			WithArgs([]int{5}, "projector failure").
+			WillReturnRows(pgxmock.NewRows([]string{"id", "payment_id", "booking_id", "amount", "status"}).
+				AddRow(3, 9, 5, 100000.0, "pending"))
+		expectReverseLoyalty(mock, []int{5, 6})
+		expectReleaseConcessions(mock, []int{5, 6})
+		mock.ExpectCommit()
+
+		bookings, refunds, err := repo.CancelShowtime(context.Background(), 4, "projector failure")
+		assert.NoError(t, err)
+		assert.Len(t, bookings, 2)
+		assert.Equal(t, "paid", bookings[0].Status)
+		assert.Len(t, refunds, 1)
+		assert.Equal(t, 9, refunds[0].PaymentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - No Bookings", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE showtimes SET status = 'cancelled'").
+			WithArgs(4).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectQuery("UPDATE bookings b SET status = 'cancelled'").
+			WithArgs(4).
+			WillReturnRows(pgxmock.NewRows(bookingColumns))
+		mock.ExpectCommit()
+
+		bookings, refunds, err := repo.CancelShowtime(context.Background(), 4, "")
+		assert.NoError(t, err)
+		assert.Empty(t, bookings)
+		assert.Empty(t, refunds)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already Cancelled", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE showtimes SET status = 'cancelled'").
+			WithArgs(4).
+			WillReturnError(pgx.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, _, err := repo.CancelShowtime(context.Background(), 4, "")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestShowtimeRepo_SearchShowtimes(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewShowtimeRepo(mock)
+	columns := []string{
+		"id", "cinema_id", "studio_id", "movie_id", "show_date", "show_time", "price", "status",
+		"m_id", "m_title", "m_poster_url", "m_genres", "m_rating", "m_duration",
+		"s_id", "s_name", "s_total_seats",
+	}
+
+	t.Run("Matches Every Studio", func(t *testing.T) {
+		rows := pgxmock.NewRows(columns).
+			AddRow(1, 1, 1, 1, "2030-01-15", "19:00:00", 50000.0, "scheduled",
+				1, "Movie 1", "http://poster1.jpg", []string{"Action"}, 8.5, 120, 1, "Studio 1", 100).
+			AddRow(2, 1, 2, 2, "2030-01-15", "19:00:00", 60000.0, "scheduled",
+				2, "Movie 2", "http://poster2.jpg", []string{"Comedy"}, 7.0, 90, 2, "Studio 2", 150)
+
+		mock.ExpectQuery(`WHERE st.status = 'scheduled' AND st.cinema_id = \$1 AND st.show_date = \$2::date AND st.show_time = \$3::time`).
+			WithArgs(1, "2030-01-15", "19:00:00").
+			WillReturnRows(rows)
+
+		showtimes, err := repo.SearchShowtimes(context.Background(), entity.ShowtimeFilter{
+			CinemaID: 1,
+			ShowDate: "2030-01-15",
+			ShowTime: "19:00:00",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, showtimes, 2)
+		assert.Equal(t, "Studio 2", showtimes[1].Studio.Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Upcoming Days When No Date", func(t *testing.T) {
+		mock.ExpectQuery(`st.movie_id = \$1 AND st.show_date >= CURRENT_DATE AND st.show_time >= \$2::time AND st.show_time <= \$3::time`).
+			WithArgs(4, "18:00:00", "22:00:00").
+			WillReturnRows(pgxmock.NewRows(columns))
+
+		showtimes, err := repo.SearchShowtimes(context.Background(), entity.ShowtimeFilter{
+			MovieID:  4,
+			TimeFrom: "18:00:00",
+			TimeTo:   "22:00:00",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, showtimes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestShowtimeRepo_GetMovieSchedule(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewShowtimeRepo(mock)
+
+	t.Run("Upcoming Showtimes With Remaining Seats", func(t *testing.T) {
+		rows := pgxmock.NewRows([]string{
+			"id", "cinema_id", "studio_id", "movie_id", "show_date", "show_time", "price", "status",
+			"c_id", "c_name", "c_location", "s_id", "s_name", "s_total_seats", "remaining_seats",
+		}).
+			AddRow(1, 1, 1, 4, "2030-01-15", "19:00:00", 50000.0, "scheduled",
+				1, "Central", "Jakarta Selatan", 1, "Studio 1", 100, 42)
+
+		mock.ExpectQuery(`st.show_date \+ st.show_time > LOCALTIMESTAMP AND st.show_date >= \$2::date AND st.show_date <= \$3::date AND c.location ILIKE '%' \|\| \$4 \|\| '%'`).
+			WithArgs(4, "2030-01-15", "2030-01-20", "jakarta").
+			WillReturnRows(rows)
+
+		listings, err := repo.GetMovieSchedule(context.Background(), 4, entity.MovieScheduleFilter{
+			DateFrom: "2030-01-15",
+			DateTo:   "2030-01-20",
+			Location: "jakarta",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, listings, 1)
+		assert.Equal(t, "Central", listings[0].Cinema.Name)
+		assert.Equal(t, "Studio 1", listings[0].Showtime.Studio.Name)
+		assert.Equal(t, 42, listings[0].RemainingSeats)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
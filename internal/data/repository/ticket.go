@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TicketRepoInterface interface {
+	GetTicketsByBooking(ctx context.Context, bookingID int) ([]entity.Ticket, error)
+	GetTicketByID(ctx context.Context, id int) (entity.Ticket, error)
+	AdmitTicket(ctx context.Context, id, staffID int) (time.Time, error)
+}
+
+type TicketRepo struct {
+	DB DBPool
+}
+
+func NewTicketRepo(db DBPool) TicketRepoInterface {
+	return &TicketRepo{DB: db}
+}
+
+// ticketQuery selects tickets in the order scanTicket reads them
+const ticketQuery = `SELECT
+				bs.id, bs.booking_id, b.user_id, b.status, bs.seat_id, s.seat_code, bs.admitted_at,
+				st.id, st.cinema_id, st.studio_id, st.movie_id,
+				st.show_date::text as show_date,
+				st.show_time::text as show_time,
+				st.price, st.status,
+				m.id, m.title, m.duration_in_minutes,
+				sd.id, sd.name
+			  FROM booking_seats bs
+			  INNER JOIN bookings b ON b.id = bs.booking_id
+			  INNER JOIN seats s ON s.id = bs.seat_id
+			  INNER JOIN showtimes st ON st.id = b.showtime_id
+			  INNER JOIN movies m ON m.id = st.movie_id
+			  INNER JOIN studios sd ON sd.id = st.studio_id`
+
+func scanTicket(row pgx.Row) (entity.Ticket, error) {
+	var t entity.Ticket
+	var movie entity.Movie
+	var studio entity.Studio
+	err := row.Scan(
+		&t.ID, &t.BookingID, &t.UserID, &t.BookingStatus, &t.SeatID, &t.SeatCode, &t.AdmittedAt,
+		&t.Showtime.ID, &t.Showtime.CinemaID, &t.Showtime.StudioID, &t.Showtime.MovieID,
+		&t.Showtime.ShowDate, &t.Showtime.ShowTime, &t.Showtime.Price, &t.Showtime.Status,
+		&movie.ID, &movie.Title, &movie.DurationMinutes,
+		&studio.ID, &studio.Name,
+	)
+	t.Showtime.Movie = &movie
+	t.Showtime.Studio = &studio
+	return t, err
+}
+
+// GetTicketsByBooking retrieves the tickets of a booking, one per booked seat
+func (r *TicketRepo) GetTicketsByBooking(ctx context.Context, bookingID int) ([]entity.Ticket, error) {
+	rows, err := r.DB.Query(ctx, ticketQuery+` WHERE bs.booking_id = $1 ORDER BY s.seat_code`, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []entity.Ticket
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, t)
+	}
+	return tickets, rows.Err()
+}
+
+// GetTicketByID retrieves a ticket by its booked seat ID
+func (r *TicketRepo) GetTicketByID(ctx context.Context, id int) (entity.Ticket, error) {
+	return scanTicket(r.DB.QueryRow(ctx, ticketQuery+` WHERE bs.id = $1`, id))
+}
+
+// AdmitTicket marks a ticket of a paid booking as admitted by a staff member. The update
+// only matches a ticket that has not been admitted yet, so when the same ticket is
+// scanned twice at once only one scan lets the holder in; the others get
+// ErrTicketAlreadyAdmitted.
+func (r *TicketRepo) AdmitTicket(ctx context.Context, id, staffID int) (time.Time, error) {
+	query := `UPDATE booking_seats bs SET admitted_at = NOW(), admitted_by = $2
+			  FROM bookings b
+			  WHERE bs.id = $1 AND b.id = bs.booking_id AND b.status = 'paid' AND bs.admitted_at IS NULL
+			  RETURNING bs.admitted_at`
+	var admittedAt time.Time
+	err := r.DB.QueryRow(ctx, query, id, staffID).Scan(&admittedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return admittedAt, ErrTicketAlreadyAdmitted
+	}
+	return admittedAt, err
+}
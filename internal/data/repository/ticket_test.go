@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var ticketTestColumns = []string{
+	"id", "booking_id", "user_id", "status", "seat_id", "seat_code", "admitted_at",
+	"id", "cinema_id", "studio_id", "movie_id", "show_date", "show_time", "price", "status",
+	"id", "title", "duration_in_minutes",
+	"id", "name",
+}
+
+func TestTicketRepo_GetTicketsByBooking(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewTicketRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		admittedAt := time.Now()
+		mock.ExpectQuery("SELECT (.+) FROM booking_seats bs (.+) WHERE bs.booking_id = (.+) ORDER BY s.seat_code").
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(ticketTestColumns).
+				AddRow(10, 1, 1, "paid", 5, "A5", &admittedAt, 3, 1, 2, 4, "2026-01-15", "19:00:00", 50000.0, "scheduled", 4, "Avengers", 120, 2, "Studio 2").
+				AddRow(11, 1, 1, "paid", 6, "A6", nil, 3, 1, 2, 4, "2026-01-15", "19:00:00", 50000.0, "scheduled", 4, "Avengers", 120, 2, "Studio 2"))
+
+		tickets, err := repo.GetTicketsByBooking(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Len(t, tickets, 2)
+		assert.Equal(t, "A5", tickets[0].SeatCode)
+		assert.NotNil(t, tickets[0].AdmittedAt)
+		assert.Nil(t, tickets[1].AdmittedAt)
+		assert.Equal(t, "Avengers", tickets[1].Showtime.Movie.Title)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM booking_seats bs").
+			WithArgs(1).
+			WillReturnError(errors.New("database error"))
+
+		tickets, err := repo.GetTicketsByBooking(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, tickets)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTicketRepo_AdmitTicket(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewTicketRepo(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery("UPDATE booking_seats bs SET admitted_at = NOW\\(\\), admitted_by = (.+) AND bs.admitted_at IS NULL").
+			WithArgs(10, 7).
+			WillReturnRows(pgxmock.NewRows([]string{"admitted_at"}).AddRow(now))
+
+		admittedAt, err := repo.AdmitTicket(context.Background(), 10, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, now, admittedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Already Admitted", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE booking_seats bs SET admitted_at").
+			WithArgs(10, 7).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.AdmitTicket(context.Background(), 10, 7)
+		assert.ErrorIs(t, err, ErrTicketAlreadyAdmitted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -5,6 +5,13 @@ type RegisterRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=100"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	// DateOfBirth is optional at sign-up but required before booking age-restricted movies
+	DateOfBirth string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// UpdateProfileRequest for updating the caller's profile
+type UpdateProfileRequest struct {
+	DateOfBirth string `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
 }
 
 // LoginRequest for user login
@@ -13,6 +20,11 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// UpdateRoleRequest for changing a user's access role
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=customer cinema_staff admin"`
+}
+
 // VerifyOTPRequest for email OTP verification
 type VerifyOTPRequest struct {
 	Email string `json:"email" validate:"required,email"`
@@ -24,11 +36,35 @@ type ResendOTPRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
-// BookingRequest for seat booking
+// BookingRequest for seat booking. Either pick seats by ID or give a seat count
+// and let the server choose the best available block.
 type BookingRequest struct {
 	ShowtimeID    int   `json:"showtime_id" validate:"required"`
-	SeatIDs       []int `json:"seat_ids" validate:"required,min=1"`
+	SeatIDs       []int `json:"seat_ids" validate:"required_without=SeatCount,omitempty,min=1,unique"`
+	SeatCount     int   `json:"seat_count" validate:"excluded_with=SeatIDs,omitempty,min=1,max=10"`
 	PaymentMethod int   `json:"payment_method" validate:"required"`
+	// PromoCode optionally redeems a promotion against the booking
+	PromoCode string `json:"promo_code" validate:"omitempty,max=50"`
+	// RedeemPoints optionally spends loyalty points on what is left after the promotion
+	RedeemPoints int `json:"redeem_points" validate:"omitempty,min=1"`
+	// Concessions optionally adds food and beverage from the showtime's cinema
+	Concessions []BookingConcessionRequest `json:"concessions" validate:"max=10,unique=ConcessionID,dive"`
+}
+
+// BookingConcessionRequest for one concession line item of a booking
+type BookingConcessionRequest struct {
+	ConcessionID int `json:"concession_id" validate:"required,min=1"`
+	Quantity     int `json:"quantity" validate:"required,min=1,max=20"`
+}
+
+// BestSeatsQueryRequest for suggesting the best available seats of a showtime
+type BestSeatsQueryRequest struct {
+	Count int `validate:"required,min=1,max=10"`
+}
+
+// CancelBookingRequest for cancelling a booking
+type CancelBookingRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
 }
 
 // PayRequest for processing payment
@@ -38,8 +74,171 @@ type PayRequest struct {
 	PaymentDetails string `json:"payment_details"`
 }
 
+// CinemaRequest for creating or updating a cinema
+type CinemaRequest struct {
+	Name     string `json:"name" validate:"required,max=150"`
+	Location string `json:"location" validate:"max=255"`
+}
+
+// StudioRequest for creating or renaming a studio
+type StudioRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// SeatLayoutRequest describes the seat grid of a studio.
+// Aisles lists seat numbers followed by a walkway, Disabled lists seat codes left out.
+// RowCategories maps row labels to a seat category, other rows are regular.
+type SeatLayoutRequest struct {
+	Rows          int               `json:"rows" validate:"required,min=1,max=26"`
+	SeatsPerRow   int               `json:"seats_per_row" validate:"required,min=1,max=50"`
+	Aisles        []int             `json:"aisles" validate:"dive,min=1"`
+	Disabled      []string          `json:"disabled" validate:"dive,required,max=10"`
+	RowCategories map[string]string `json:"row_categories" validate:"dive,keys,len=1,endkeys,required,max=20"`
+}
+
+// ConcessionRequest for adding or updating a food and beverage item of a cinema
+type ConcessionRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Category    string  `json:"category" validate:"required,oneof=popcorn drink snack combo"`
+	Description string  `json:"description" validate:"max=255"`
+	Price       float64 `json:"price" validate:"min=0"`
+	Stock       int     `json:"stock" validate:"min=0"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// MovieRequest for creating or updating a movie
+type MovieRequest struct {
+	Title           string   `json:"title" validate:"required,max=255"`
+	PosterURL       string   `json:"poster_url" validate:"omitempty,max=2048"`
+	Genres          []string `json:"genres" validate:"required,min=1,dive,required,max=50"`
+	DurationMinutes int      `json:"duration_in_minutes" validate:"required,min=1,max=600"`
+	ReleaseDate     string   `json:"release_date" validate:"required,datetime=2006-01-02"`
+	ReleaseStatus   string   `json:"release_status" validate:"required,oneof=now_playing coming_soon"`
+	Synopsis        string   `json:"synopsis" validate:"max=5000"`
+	AgeRating       string   `json:"age_rating" validate:"omitempty,oneof=SU 13+ 17+ 21+"`
+	Language        string   `json:"language" validate:"max=50"`
+	TrailerURL      string   `json:"trailer_url" validate:"omitempty,url,max=2048"`
+
+	Cast []MovieCastRequest `json:"cast" validate:"max=50,dive"`
+	Crew []MovieCrewRequest `json:"crew" validate:"max=50,dive"`
+}
+
+// MovieCastRequest for an actor of a movie, listed in the given order
+type MovieCastRequest struct {
+	Name          string `json:"name" validate:"required,max=100"`
+	CharacterName string `json:"character_name" validate:"max=100"`
+}
+
+// MovieCrewRequest for a crew member of a movie such as its Director
+type MovieCrewRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Job  string `json:"job" validate:"required,max=50"`
+}
+
+// MovieQueryRequest for searching, filtering and sorting the movie list
+type MovieQueryRequest struct {
+	Search        string   `validate:"max=100"`
+	Genres        []string `validate:"max=5,dive,required,max=50"`
+	ReleaseStatus string   `validate:"omitempty,oneof=now_playing coming_soon"`
+	MinRating     float64  `validate:"min=0,max=5"`
+	Sort          string   `validate:"omitempty,oneof=newest release_date rating popular title"`
+}
+
+// MovieScheduleRequest for filtering a movie's upcoming showtimes
+type MovieScheduleRequest struct {
+	DateFrom string `validate:"omitempty,datetime=2006-01-02"`
+	DateTo   string `validate:"omitempty,datetime=2006-01-02"`
+	Location string `validate:"max=100"`
+}
+
+// ReviewRequest for posting or editing a movie review
+type ReviewRequest struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+// PromotionRequest for creating or updating a promo code. Zero limits and empty
+// restrictions leave the promotion unrestricted; days_of_week uses 0 for Sunday.
+type PromotionRequest struct {
+	Code            string  `json:"code" validate:"required,max=50"`
+	Description     string  `json:"description" validate:"max=255"`
+	DiscountType    string  `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	DiscountValue   float64 `json:"discount_value" validate:"required,gt=0"`
+	MaxDiscount     float64 `json:"max_discount" validate:"min=0"`
+	MovieID         *int    `json:"movie_id" validate:"omitempty,min=1"`
+	CinemaID        *int    `json:"cinema_id" validate:"omitempty,min=1"`
+	PaymentMethodID *int    `json:"payment_method_id" validate:"omitempty,min=1"`
+	DaysOfWeek      []int   `json:"days_of_week" validate:"omitempty,unique,dive,min=0,max=6"`
+	MinSeats        int     `json:"min_seats" validate:"min=0"`
+	UsageLimit      int     `json:"usage_limit" validate:"min=0"`
+	PerUserLimit    int     `json:"per_user_limit" validate:"min=0"`
+	ValidFrom       string  `json:"valid_from" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	ValidUntil      string  `json:"valid_until" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	IsActive        *bool   `json:"is_active"`
+}
+
+// PricingRuleRequest for creating or updating a pricing rule. Conditions left empty do
+// not restrict the rule; days_of_week uses 0 for Sunday and matches alongside dates,
+// and a negative adjustment_value is a discount.
+type PricingRuleRequest struct {
+	Name            string   `json:"name" validate:"required,max=100"`
+	CinemaID        *int     `json:"cinema_id" validate:"omitempty,min=1"`
+	DaysOfWeek      []int    `json:"days_of_week" validate:"omitempty,unique,dive,min=0,max=6"`
+	Dates           []string `json:"dates" validate:"omitempty,unique,dive,datetime=2006-01-02"`
+	TimeFrom        string   `json:"time_from" validate:"omitempty,datetime=15:04"`
+	TimeUntil       string   `json:"time_until" validate:"omitempty,datetime=15:04"`
+	MinOccupancy    float64  `json:"min_occupancy" validate:"min=0,max=100"`
+	AdjustmentType  string   `json:"adjustment_type" validate:"required,oneof=percentage fixed"`
+	AdjustmentValue float64  `json:"adjustment_value" validate:"required"`
+	Priority        int      `json:"priority"`
+	IsActive        *bool    `json:"is_active"`
+}
+
+// ShowtimeRequest for scheduling a movie in a studio
+type ShowtimeRequest struct {
+	MovieID  int     `json:"movie_id" validate:"required"`
+	StudioID int     `json:"studio_id" validate:"required"`
+	ShowDate string  `json:"show_date" validate:"required,datetime=2006-01-02"`
+	ShowTime string  `json:"show_time" validate:"required,datetime=15:04"`
+	Price    float64 `json:"price" validate:"required,gt=0"`
+}
+
+// RescheduleShowtimeRequest for moving a showtime, an empty price keeps the current one
+type RescheduleShowtimeRequest struct {
+	ShowDate string  `json:"show_date" validate:"required,datetime=2006-01-02"`
+	ShowTime string  `json:"show_time" validate:"required,datetime=15:04"`
+	Price    float64 `json:"price" validate:"omitempty,gt=0"`
+}
+
+// CancelShowtimeRequest for cancelling a showtime
+type CancelShowtimeRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// PaymentWebhookRequest for provider payment callbacks
+type PaymentWebhookRequest struct {
+	TransactionID string `json:"transaction_id" validate:"required,max=100"`
+	Status        string `json:"status" validate:"required,oneof=completed failed"`
+}
+
+// ShowtimeSearchRequest for searching showtimes across cinemas and studios
+// Date and times are parsed by the usecase, which accepts both HH:MM and HH:MM:SS.
+type ShowtimeSearchRequest struct {
+	CinemaID int `validate:"omitempty,min=1"`
+	MovieID  int `validate:"omitempty,min=1"`
+	Date     string
+	Time     string
+	TimeFrom string
+	TimeTo   string
+}
+
 // SeatQueryRequest for seat availability query
 type SeatQueryRequest struct {
 	Date string `validate:"required"`
 	Time string `validate:"required"`
 }
+
+// TicketScanRequest for admitting a ticket at the door with the token read from its QR code
+type TicketScanRequest struct {
+	Token string `json:"token" validate:"required,max=255"`
+}
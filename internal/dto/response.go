@@ -6,11 +6,13 @@ import (
 
 // ResponseUser for user data response
 type ResponseUser struct {
-	ID         int       `json:"id"`
-	Username   string    `json:"username"`
-	Email      string    `json:"email"`
-	IsVerified bool      `json:"is_verified"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID          int       `json:"id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	IsVerified  bool      `json:"is_verified"`
+	Role        string    `json:"role"`
+	DateOfBirth string    `json:"date_of_birth,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // LoginResponse for login success response
@@ -36,21 +38,58 @@ type StudioResponse struct {
 	TotalSeats int    `json:"total_seats"`
 }
 
+// SeatLayoutResponse for a generated studio seat layout
+type SeatLayoutResponse struct {
+	StudioID   int      `json:"studio_id"`
+	TotalSeats int      `json:"total_seats"`
+	SeatCodes  []string `json:"seat_codes"`
+}
+
 // SeatResponse for seat availability response
 type SeatResponse struct {
-	ID       int    `json:"id"`
-	SeatCode string `json:"seat_code"`
-	IsBooked bool   `json:"is_booked"`
+	ID       int     `json:"id"`
+	SeatCode string  `json:"seat_code"`
+	Category string  `json:"category,omitempty"`
+	Price    float64 `json:"price,omitempty"`
+	IsBooked bool    `json:"is_booked"`
+}
+
+// SeatMapResponse for the seat grid of a showtime's studio.
+// Columns counts physical columns, so aisles and missing seats show up as gaps.
+type SeatMapResponse struct {
+	ShowtimeID int               `json:"showtime_id"`
+	StudioID   int               `json:"studio_id"`
+	Columns    int               `json:"columns"`
+	Rows       []SeatRowResponse `json:"rows"`
+}
+
+// SeatRowResponse for one row of a seat map, with a cell for every column
+type SeatRowResponse struct {
+	Label string             `json:"label"`
+	Cells []SeatCellResponse `json:"cells"`
+}
+
+// SeatCellResponse for one column of a seat map row, either a seat or a gap
+type SeatCellResponse struct {
+	Column   int     `json:"column"`
+	Gap      bool    `json:"gap"`
+	ID       int     `json:"id,omitempty"`
+	SeatCode string  `json:"seat_code,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Price    float64 `json:"price,omitempty"`
+	State    string  `json:"state,omitempty"`
 }
 
 // ShowtimeResponse for showtime data response
 type ShowtimeResponse struct {
 	ID       int            `json:"id"`
+	CinemaID int            `json:"cinema_id,omitempty"`
 	Movie    MovieResponse  `json:"movie"`
 	Studio   StudioResponse `json:"studio"`
 	ShowDate string         `json:"show_date"`
 	ShowTime string         `json:"show_time"`
 	Price    float64        `json:"price"`
+	Status   string         `json:"status,omitempty"`
 }
 
 // MovieResponse for movie data response
@@ -60,18 +99,171 @@ type MovieResponse struct {
 	PosterURL       string   `json:"poster_url"`
 	Genres          []string `json:"genres"`
 	Rating          float64  `json:"rating"`
+	ReviewCount     int      `json:"review_count"`
 	DurationMinutes int      `json:"duration_minutes"`
+	ReleaseDate     string   `json:"release_date,omitempty"`
+	ReleaseStatus   string   `json:"release_status,omitempty"`
+	AgeRating       string   `json:"age_rating,omitempty"`
+	Synopsis        string   `json:"synopsis,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	TrailerURL      string   `json:"trailer_url,omitempty"`
+	Director        string   `json:"director,omitempty"`
+
+	Cast []MovieCastResponse `json:"cast,omitempty"`
+	Crew []MovieCrewResponse `json:"crew,omitempty"`
+}
+
+// MovieCastResponse for an actor of a movie
+type MovieCastResponse struct {
+	Name          string `json:"name"`
+	CharacterName string `json:"character_name,omitempty"`
+}
+
+// MovieCrewResponse for a crew member of a movie
+type MovieCrewResponse struct {
+	Name string `json:"name"`
+	Job  string `json:"job"`
+}
+
+// ReviewResponse for a user's review of a movie
+type ReviewResponse struct {
+	ID        int       `json:"id"`
+	MovieID   int       `json:"movie_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MovieScheduleResponse for where and when a movie is playing, grouped by date then cinema
+type MovieScheduleResponse struct {
+	Movie MovieResponse          `json:"movie"`
+	Dates []ScheduleDateResponse `json:"dates"`
+}
+
+// ScheduleDateResponse for the cinemas showing a movie on one date
+type ScheduleDateResponse struct {
+	Date    string                   `json:"date"`
+	Cinemas []ScheduleCinemaResponse `json:"cinemas"`
+}
+
+// ScheduleCinemaResponse for a cinema's showtimes of a movie on one date
+type ScheduleCinemaResponse struct {
+	ID        int                        `json:"id"`
+	Name      string                     `json:"name"`
+	Location  string                     `json:"location"`
+	Showtimes []ScheduleShowtimeResponse `json:"showtimes"`
+}
+
+// ScheduleShowtimeResponse for one showtime in a movie schedule
+type ScheduleShowtimeResponse struct {
+	ID             int            `json:"id"`
+	ShowTime       string         `json:"show_time"`
+	Price          float64        `json:"price"`
+	Studio         StudioResponse `json:"studio"`
+	RemainingSeats int            `json:"remaining_seats"`
 }
 
 // BookingResponse for booking data response
 type BookingResponse struct {
-	ID          int              `json:"id"`
-	Showtime    ShowtimeResponse `json:"showtime"`
-	Seats       []SeatResponse   `json:"seats"`
-	TotalAmount float64          `json:"total_amount"`
-	Status      string           `json:"status"`
-	Payment     *PaymentResponse `json:"payment,omitempty"`
-	CreatedAt   time.Time        `json:"created_at"`
+	ID                   int                         `json:"id"`
+	Showtime             ShowtimeResponse            `json:"showtime"`
+	Seats                []SeatResponse              `json:"seats"`
+	TotalAmount          float64                     `json:"total_amount"`
+	DiscountAmount       float64                     `json:"discount_amount"`
+	PromoCode            string                      `json:"promo_code,omitempty"`
+	PointsRedeemed       int                         `json:"points_redeemed"`
+	PointsDiscount       float64                     `json:"points_discount"`
+	Concessions          []BookingConcessionResponse `json:"concessions,omitempty"`
+	Status               string                      `json:"status"`
+	ExpiresAt            *time.Time                  `json:"expires_at,omitempty"`
+	HoldRemainingSeconds int                         `json:"hold_remaining_seconds"`
+	Payment              *PaymentResponse            `json:"payment,omitempty"`
+	CreatedAt            time.Time                   `json:"created_at"`
+}
+
+// BookingConcessionResponse for a concession line item of a booking
+type BookingConcessionResponse struct {
+	ConcessionID int     `json:"concession_id"`
+	Name         string  `json:"name"`
+	Quantity     int     `json:"quantity"`
+	Price        float64 `json:"price"`
+	Subtotal     float64 `json:"subtotal"`
+}
+
+// ConcessionResponse for a food and beverage item of a cinema
+type ConcessionResponse struct {
+	ID          int       `json:"id"`
+	CinemaID    int       `json:"cinema_id"`
+	Name        string    `json:"name"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	Stock       int       `json:"stock"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PromotionResponse for promo code data response
+type PromotionResponse struct {
+	ID              int       `json:"id"`
+	Code            string    `json:"code"`
+	Description     string    `json:"description"`
+	DiscountType    string    `json:"discount_type"`
+	DiscountValue   float64   `json:"discount_value"`
+	MaxDiscount     float64   `json:"max_discount"`
+	MovieID         *int      `json:"movie_id"`
+	CinemaID        *int      `json:"cinema_id"`
+	PaymentMethodID *int      `json:"payment_method_id"`
+	DaysOfWeek      []int     `json:"days_of_week"`
+	MinSeats        int       `json:"min_seats"`
+	UsageLimit      int       `json:"usage_limit"`
+	PerUserLimit    int       `json:"per_user_limit"`
+	UsedCount       int       `json:"used_count"`
+	ValidFrom       time.Time `json:"valid_from"`
+	ValidUntil      time.Time `json:"valid_until"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PricingRuleResponse for pricing rule data response
+type PricingRuleResponse struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	CinemaID        *int      `json:"cinema_id"`
+	DaysOfWeek      []int     `json:"days_of_week"`
+	Dates           []string  `json:"dates"`
+	TimeFrom        string    `json:"time_from,omitempty"`
+	TimeUntil       string    `json:"time_until,omitempty"`
+	MinOccupancy    float64   `json:"min_occupancy"`
+	AdjustmentType  string    `json:"adjustment_type"`
+	AdjustmentValue float64   `json:"adjustment_value"`
+	Priority        int       `json:"priority"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// LoyaltyBalanceResponse for a user's spendable loyalty points and what they are worth
+type LoyaltyBalanceResponse struct {
+	Balance    int     `json:"balance"`
+	PointValue float64 `json:"point_value"`
+	Worth      float64 `json:"worth"`
+}
+
+// LoyaltyEntryResponse for one movement in a user's loyalty points ledger
+type LoyaltyEntryResponse struct {
+	ID        int        `json:"id"`
+	BookingID *int       `json:"booking_id"`
+	EntryType string     `json:"entry_type"`
+	Points    int        `json:"points"`
+	Remaining int        `json:"remaining,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 // PaymentResponse for payment data response
@@ -79,7 +271,41 @@ type PaymentResponse struct {
 	ID            int        `json:"id"`
 	PaymentMethod string     `json:"payment_method"`
 	Status        string     `json:"status"`
+	TransactionID string     `json:"transaction_id,omitempty"`
+	Message       string     `json:"message,omitempty"`
 	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	PointsEarned  int        `json:"points_earned,omitempty"`
+}
+
+// PaymentWebhookResponse for an applied provider callback
+type PaymentWebhookResponse struct {
+	PaymentID    int    `json:"payment_id"`
+	BookingID    int    `json:"booking_id"`
+	Status       string `json:"status"`
+	RefundStatus string `json:"refund_status,omitempty"`
+}
+
+// RefundResponse for refund data response
+type RefundResponse struct {
+	ID        int       `json:"id"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CancelBookingResponse for booking cancellation response
+type CancelBookingResponse struct {
+	BookingID int             `json:"booking_id"`
+	Status    string          `json:"status"`
+	Refund    *RefundResponse `json:"refund,omitempty"`
+}
+
+// CancelShowtimeResponse for showtime cancellation response
+type CancelShowtimeResponse struct {
+	ShowtimeID        int              `json:"showtime_id"`
+	Status            string           `json:"status"`
+	CancelledBookings int              `json:"cancelled_bookings"`
+	Refunds           []RefundResponse `json:"refunds"`
 }
 
 // PaymentMethodResponse for payment method list response
@@ -94,3 +320,29 @@ type PriceStats struct {
 	Max float64
 	Avg float64
 }
+
+// TicketResponse for one e-ticket of a paid booking; QRCodeURL serves Token as a QR code PNG
+type TicketResponse struct {
+	ID         int        `json:"id"`
+	BookingID  int        `json:"booking_id"`
+	SeatCode   string     `json:"seat_code"`
+	MovieTitle string     `json:"movie_title"`
+	StudioName string     `json:"studio_name"`
+	ShowDate   string     `json:"show_date"`
+	ShowTime   string     `json:"show_time"`
+	Token      string     `json:"token"`
+	QRCodeURL  string     `json:"qr_code_url"`
+	AdmittedAt *time.Time `json:"admitted_at"`
+}
+
+// TicketScanResponse for a ticket admitted at the door
+type TicketScanResponse struct {
+	TicketID   int       `json:"ticket_id"`
+	BookingID  int       `json:"booking_id"`
+	SeatCode   string    `json:"seat_code"`
+	MovieTitle string    `json:"movie_title"`
+	StudioName string    `json:"studio_name"`
+	ShowDate   string    `json:"show_date"`
+	ShowTime   string    `json:"show_time"`
+	AdmittedAt time.Time `json:"admitted_at"`
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -22,6 +23,7 @@ type AuthUseCaseInterface interface {
 	ValidateToken(ctx context.Context, token string) (entity.User, error)
 	VerifyOTP(ctx context.Context, req dto.VerifyOTPRequest) error
 	ResendOTP(ctx context.Context, req dto.ResendOTPRequest) error
+	UpdateUserRole(ctx context.Context, userID int, req dto.UpdateRoleRequest) (dto.ResponseUser, error)
 }
 
 type AuthUseCase struct {
@@ -64,6 +66,11 @@ func (u *AuthUseCase) Register(ctx context.Context, req dto.RegisterRequest) (dt
 		return dto.ResponseUser{}, errors.New("email already exists")
 	}
 
+	dateOfBirth, err := parseBirthDate(req.DateOfBirth)
+	if err != nil {
+		return dto.ResponseUser{}, err
+	}
+
 	// Hash password using bcrypt
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -74,6 +81,7 @@ func (u *AuthUseCase) Register(ctx context.Context, req dto.RegisterRequest) (dt
 		Username:     req.Username,
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
+		DateOfBirth:  dateOfBirth,
 	}
 
 	id, err := u.Repo.Auth.CreateUser(ctx, user)
@@ -101,10 +109,12 @@ func (u *AuthUseCase) Register(ctx context.Context, req dto.RegisterRequest) (dt
 	go u.EmailService.SendOTP(req.Email, req.Username, otpCode)
 
 	return dto.ResponseUser{
-		ID:         id,
-		Username:   req.Username,
-		Email:      req.Email,
-		IsVerified: false,
+		ID:          id,
+		Username:    req.Username,
+		Email:       req.Email,
+		IsVerified:  false,
+		Role:        entity.RoleCustomer,
+		DateOfBirth: formatBirthDate(dateOfBirth),
 	}, nil
 }
 
@@ -214,13 +224,7 @@ func (u *AuthUseCase) Login(ctx context.Context, req dto.LoginRequest) (dto.Logi
 	return dto.LoginResponse{
 		Token:     token,
 		ExpiredAt: expiredAt,
-		User: dto.ResponseUser{
-			ID:         user.ID,
-			Username:   user.Username,
-			Email:      user.Email,
-			IsVerified: user.IsVerified,
-			CreatedAt:  user.CreatedAt,
-		},
+		User:      toResponseUser(user),
 	}, nil
 }
 
@@ -243,3 +247,20 @@ func (u *AuthUseCase) ValidateToken(ctx context.Context, token string) (entity.U
 
 	return user, nil
 }
+
+// UpdateUserRole assigns a new access role to a user
+func (u *AuthUseCase) UpdateUserRole(ctx context.Context, userID int, req dto.UpdateRoleRequest) (dto.ResponseUser, error) {
+	if err := u.Repo.Auth.UpdateUserRole(ctx, userID, req.Role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ResponseUser{}, ErrUserNotFound
+		}
+		return dto.ResponseUser{}, err
+	}
+
+	user, err := u.Repo.Auth.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.ResponseUser{}, err
+	}
+
+	return toResponseUser(user), nil
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
@@ -47,6 +48,16 @@ func (m *MockAuthRepo) UpdateUserVerified(ctx context.Context, userID int) error
 	return args.Error(0)
 }
 
+func (m *MockAuthRepo) UpdateUserRole(ctx context.Context, userID int, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepo) UpdateUserBirthDate(ctx context.Context, userID int, dateOfBirth time.Time) error {
+	args := m.Called(ctx, userID, dateOfBirth)
+	return args.Error(0)
+}
+
 func (m *MockAuthRepo) CreateSession(ctx context.Context, session entity.Session) error {
 	args := m.Called(ctx, session)
 	return args.Error(0)
@@ -255,6 +266,36 @@ func TestAuthUseCase_ValidateToken_Success(t *testing.T) {
 	mockAuthRepo.AssertExpectations(t)
 }
 
+func TestAuthUseCase_UpdateUserRole_Success(t *testing.T) {
+	mockAuthRepo := new(MockAuthRepo)
+	repo := &repository.Repository{Auth: mockAuthRepo}
+	usecase := &AuthUseCase{Repo: repo}
+
+	user := entity.User{ID: 2, Username: "staff", Email: "staff@example.com", IsVerified: true, Role: entity.RoleCinemaStaff}
+
+	mockAuthRepo.On("UpdateUserRole", mock.Anything, 2, "cinema_staff").Return(nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 2).Return(user, nil)
+
+	result, err := usecase.UpdateUserRole(context.Background(), 2, dto.UpdateRoleRequest{Role: "cinema_staff"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cinema_staff", result.Role)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestAuthUseCase_UpdateUserRole_UserNotFound(t *testing.T) {
+	mockAuthRepo := new(MockAuthRepo)
+	repo := &repository.Repository{Auth: mockAuthRepo}
+	usecase := &AuthUseCase{Repo: repo}
+
+	mockAuthRepo.On("UpdateUserRole", mock.Anything, 999, "admin").Return(pgx.ErrNoRows)
+
+	_, err := usecase.UpdateUserRole(context.Background(), 999, dto.UpdateRoleRequest{Role: "admin"})
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	mockAuthRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, 999)
+}
+
 func TestAuthUseCase_ValidateToken_InvalidToken(t *testing.T) {
 	mockAuthRepo := new(MockAuthRepo)
 	repo := &repository.Repository{Auth: mockAuthRepo}
@@ -382,3 +423,31 @@ func TestAuthUseCase_VerifyOTP_InvalidOTP(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid or expired OTP")
 	mockAuthRepo.AssertExpectations(t)
 }
+
+func TestUserUseCase_UpdateProfile(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockAuthRepo := new(MockAuthRepo)
+		usecase := &UserUseCase{Repo: &repository.Repository{Auth: mockAuthRepo}}
+
+		dob := time.Date(2001, 8, 17, 0, 0, 0, 0, time.UTC)
+		mockAuthRepo.On("UpdateUserBirthDate", mock.Anything, 1, dob).Return(nil)
+		mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1, Username: "budi", DateOfBirth: &dob}, nil)
+
+		profile, err := usecase.UpdateProfile(context.Background(), 1, dto.UpdateProfileRequest{DateOfBirth: "2001-08-17"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2001-08-17", profile.DateOfBirth)
+		mockAuthRepo.AssertExpectations(t)
+	})
+
+	t.Run("Future Date", func(t *testing.T) {
+		mockAuthRepo := new(MockAuthRepo)
+		usecase := &UserUseCase{Repo: &repository.Repository{Auth: mockAuthRepo}}
+
+		future := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+		_, err := usecase.UpdateProfile(context.Background(), 1, dto.UpdateProfileRequest{DateOfBirth: future})
+
+		assert.ErrorIs(t, err, ErrInvalidBirthDate)
+		mockAuthRepo.AssertNotCalled(t, "UpdateUserBirthDate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
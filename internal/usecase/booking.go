@@ -7,9 +7,14 @@ import (
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // bookingDetailResult is used for concurrent booking detail fetching
@@ -19,21 +24,208 @@ type bookingDetailResult struct {
 	Err      error
 }
 
+// defaultHoldDuration is used when no booking hold window is configured
+const defaultHoldDuration = 15 * time.Minute
+
+// defaultPaymentGrace is how long past its hold a booking with an unconfirmed payment
+// keeps its seats when not configured
+const defaultPaymentGrace = 30 * time.Minute
+
 type BookingUseCaseInterface interface {
 	CreateBooking(ctx context.Context, userID int, req dto.BookingRequest) (dto.BookingResponse, error)
 	GetUserBookings(ctx context.Context, userID int) ([]dto.BookingResponse, error)
+	ExpirePendingBookings(ctx context.Context) (int, error)
+	CancelBooking(ctx context.Context, userID, bookingID int, req dto.CancelBookingRequest) (dto.CancelBookingResponse, error)
 }
 
 type BookingUseCase struct {
 	Repo         *repository.Repository
 	EmailService *utils.EmailService
+	Gateways     *gateway.Registry
+	SeatHub      *realtime.Hub
+	HoldDuration time.Duration
+	CancelCutoff time.Duration
+	PaymentGrace time.Duration
+	Loyalty      utils.LoyaltyConfig
 }
 
-func NewBookingUseCase(repo *repository.Repository) BookingUseCaseInterface {
+func NewBookingUseCase(repo *repository.Repository, config utils.Configuration, gateways *gateway.Registry, seatHub *realtime.Hub) BookingUseCaseInterface {
 	return &BookingUseCase{
 		Repo:         repo,
 		EmailService: utils.NewEmailService(),
+		Gateways:     gateways,
+		SeatHub:      seatHub,
+		HoldDuration: time.Duration(config.Booking.HoldMinutes) * time.Minute,
+		CancelCutoff: time.Duration(config.Booking.CancelCutoffMinutes) * time.Minute,
+		PaymentGrace: time.Duration(config.Booking.PaymentGraceMinutes) * time.Minute,
+		Loyalty:      config.Loyalty,
+	}
+}
+
+// holdDuration returns the configured seat hold window for pending bookings
+func (u *BookingUseCase) holdDuration() time.Duration {
+	if u.HoldDuration <= 0 {
+		return defaultHoldDuration
+	}
+	return u.HoldDuration
+}
+
+// paymentGrace returns how long past its hold a booking with an unconfirmed payment keeps its seats
+func (u *BookingUseCase) paymentGrace() time.Duration {
+	if u.PaymentGrace <= 0 {
+		return defaultPaymentGrace
 	}
+	return u.PaymentGrace
+}
+
+// checkAgeRating refuses a booking when the customer will be younger on the show date than
+// the movie's classification allows. The profile is only read for restricted movies.
+func (u *BookingUseCase) checkAgeRating(ctx context.Context, userID int, showtime entity.Showtime) error {
+	if showtime.Movie == nil {
+		return nil
+	}
+	minimumAge, restricted := entity.AgeRatingMinimums[showtime.Movie.AgeRating]
+	if !restricted {
+		return nil
+	}
+
+	user, err := u.Repo.Auth.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DateOfBirth == nil {
+		return ErrBirthDateRequired
+	}
+
+	showDate, err := time.Parse("2006-01-02", showtime.ShowDate)
+	if err != nil {
+		showDate = time.Now()
+	}
+	if ageOn(*user.DateOfBirth, showDate) < minimumAge {
+		return ErrAgeRestricted
+	}
+	return nil
+}
+
+// ageOn returns a person's age in whole years on a date
+func ageOn(dateOfBirth, date time.Time) int {
+	age := date.Year() - dateOfBirth.Year()
+	if date.Month() < dateOfBirth.Month() || (date.Month() == dateOfBirth.Month() && date.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// checkPromotion verifies the conditions of a promotion that are known before the booking
+// is stored. The usage limits are counted when the booking is inserted.
+func checkPromotion(p entity.Promotion, showtime entity.Showtime, paymentMethodID, seatCount int, now time.Time) error {
+	if !p.IsActive || now.Before(p.ValidFrom) || now.After(p.ValidUntil) {
+		return ErrPromoUnavailable
+	}
+	if p.MovieID != nil && *p.MovieID != showtime.MovieID {
+		return fmt.Errorf("%w: not valid for this movie", ErrPromoNotApplicable)
+	}
+	if p.CinemaID != nil && *p.CinemaID != showtime.CinemaID {
+		return fmt.Errorf("%w: not valid at this cinema", ErrPromoNotApplicable)
+	}
+	if len(p.DaysOfWeek) > 0 {
+		showDate, err := time.Parse("2006-01-02", showtime.ShowDate)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(p.DaysOfWeek, int(showDate.Weekday())) {
+			return fmt.Errorf("%w: not valid on %s", ErrPromoNotApplicable, showDate.Weekday())
+		}
+	}
+	if p.PaymentMethodID != nil && *p.PaymentMethodID != paymentMethodID {
+		return fmt.Errorf("%w: not valid for this payment method", ErrPromoNotApplicable)
+	}
+	if seatCount < p.MinSeats {
+		return fmt.Errorf("%w: requires at least %d seats", ErrPromoNotApplicable, p.MinSeats)
+	}
+	return nil
+}
+
+// holdRemainingSeconds returns how long a pending booking keeps its seats
+func holdRemainingSeconds(booking entity.Booking, now time.Time) int {
+	if booking.Status != "pending" || booking.ExpiresAt == nil {
+		return 0
+	}
+	remaining := booking.ExpiresAt.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	return int(remaining.Seconds())
+}
+
+// showtimeStart parses a showtime's show_date and show_time into a local timestamp
+func showtimeStart(showtime entity.Showtime) (time.Time, error) {
+	value := showtime.ShowDate + " " + showtime.ShowTime
+	start, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if err != nil {
+		start, err = time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	}
+	return start, err
+}
+
+// seatPrices maps each booked seat to the price charged for it
+func seatPrices(bookingSeats []entity.BookingSeat) map[int]float64 {
+	prices := make(map[int]float64, len(bookingSeats))
+	for _, bs := range bookingSeats {
+		prices[bs.SeatID] = bs.PriceSnapshot
+	}
+	return prices
+}
+
+// toBookingConcessionResponses maps a booking's concession line items at their snapshot prices
+func toBookingConcessionResponses(concessions []entity.BookingConcession) []dto.BookingConcessionResponse {
+	var response []dto.BookingConcessionResponse
+	for _, c := range concessions {
+		response = append(response, dto.BookingConcessionResponse{
+			ConcessionID: c.ConcessionID,
+			Name:         c.Name,
+			Quantity:     c.Quantity,
+			Price:        c.PriceSnapshot,
+			Subtotal:     c.PriceSnapshot * float64(c.Quantity),
+		})
+	}
+	return response
+}
+
+// publishBookingSeats tells live seat map viewers that a booking's seats changed state.
+// The seats are only looked up when someone is watching the showtime.
+func publishBookingSeats(ctx context.Context, repo *repository.Repository, hub *realtime.Hub, booking entity.Booking, state, reason string) {
+	if !hub.HasSubscribers(booking.ShowtimeID) {
+		return
+	}
+
+	bookingSeats, err := repo.Booking.GetBookingSeats(ctx, booking.ID)
+	if err != nil || len(bookingSeats) == 0 {
+		return
+	}
+	seatIDs := make([]int, 0, len(bookingSeats))
+	for _, bs := range bookingSeats {
+		seatIDs = append(seatIDs, bs.SeatID)
+	}
+
+	hub.Publish(realtime.SeatEvent{
+		ShowtimeID: booking.ShowtimeID,
+		SeatIDs:    seatIDs,
+		State:      state,
+		Reason:     reason,
+	})
+}
+
+// sendCancellationNotice sends booking cancellation email asynchronously (GOROUTINE)
+func (u *BookingUseCase) sendCancellationNotice(email, username, movieTitle string, bookingID int, refund *dto.RefundResponse) {
+	message := fmt.Sprintf(
+		"Dear %s,\n\nYour booking #%d for %s has been cancelled.",
+		username, bookingID, movieTitle,
+	)
+	if refund != nil {
+		message += fmt.Sprintf("\n\nA refund of Rp %.0f has been requested and is currently %s.", refund.Amount, refund.Status)
+	}
+	u.EmailService.SendOTP(email, username, message)
 }
 
 // sendBookingConfirmation sends booking confirmation email asynchronously (GOROUTINE)
@@ -53,6 +245,36 @@ func (u *BookingUseCase) CreateBooking(ctx context.Context, userID int, req dto.
 	if err != nil {
 		return dto.BookingResponse{}, errors.New("showtime not found")
 	}
+	if showtime.Status == entity.ShowtimeCancelled {
+		return dto.BookingResponse{}, ErrShowtimeCancelled
+	}
+	if err := u.checkAgeRating(ctx, userID, showtime); err != nil {
+		return dto.BookingResponse{}, err
+	}
+
+	// Choose the best block for the group when no seats were picked
+	if len(req.SeatIDs) == 0 {
+		seats, err := u.Repo.Seat.GetSeatsByShowtime(ctx, req.ShowtimeID)
+		if err != nil {
+			return dto.BookingResponse{}, err
+		}
+		picked := pickBestSeats(seats, req.SeatCount)
+		if picked == nil {
+			return dto.BookingResponse{}, ErrNotEnoughSeats
+		}
+		for _, seat := range picked {
+			req.SeatIDs = append(req.SeatIDs, seat.ID)
+		}
+	}
+
+	// Refuse a seat listed twice before promotions and points are checked against the count
+	seen := make(map[int]bool, len(req.SeatIDs))
+	for _, id := range req.SeatIDs {
+		if seen[id] {
+			return dto.BookingResponse{}, ErrDuplicateSeat
+		}
+		seen[id] = true
+	}
 
 	// Check seat availability
 	available, err := u.Repo.Seat.CheckSeatsAvailable(ctx, req.ShowtimeID, req.SeatIDs)
@@ -60,7 +282,7 @@ func (u *BookingUseCase) CreateBooking(ctx context.Context, userID int, req dto.
 		return dto.BookingResponse{}, err
 	}
 	if !available {
-		return dto.BookingResponse{}, errors.New("one or more seats are not available")
+		return dto.BookingResponse{}, ErrSeatTaken
 	}
 
 	// Verify payment method exists
@@ -69,17 +291,60 @@ func (u *BookingUseCase) CreateBooking(ctx context.Context, userID int, req dto.
 		return dto.BookingResponse{}, errors.New("invalid payment method")
 	}
 
-	// Create booking
+	// Create booking, holding the seats until the payment window closes
+	expiresAt := time.Now().Add(u.holdDuration())
 	booking := entity.Booking{
 		UserID:     userID,
 		ShowtimeID: req.ShowtimeID,
+		ExpiresAt:  &expiresAt,
+	}
+
+	// Resolve the promo code; the discount itself is computed when the booking is stored
+	if code := strings.TrimSpace(req.PromoCode); code != "" {
+		promotion, err := u.Repo.Promotion.GetPromotionByCode(ctx, code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.BookingResponse{}, ErrPromoNotFound
+		}
+		if err != nil {
+			return dto.BookingResponse{}, err
+		}
+		if err := checkPromotion(promotion, showtime, req.PaymentMethod, len(req.SeatIDs), time.Now()); err != nil {
+			return dto.BookingResponse{}, err
+		}
+		booking.PromotionID = &promotion.ID
+		booking.PromoCode = promotion.Code
 	}
 
-	bookingID, err := u.Repo.Booking.CreateBooking(ctx, booking, req.SeatIDs, showtime.Price)
+	// Concessions are priced and taken off stock when the booking is stored
+	for _, item := range req.Concessions {
+		booking.Concessions = append(booking.Concessions, entity.BookingConcession{
+			ConcessionID: item.ConcessionID,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	// Points are spent when the booking is stored, capped at what is left to pay
+	if req.RedeemPoints > 0 {
+		booking.PointsRedeemed = req.RedeemPoints
+		booking.PointsDiscount = float64(req.RedeemPoints) * loyaltySettings(u.Loyalty).PointValue
+	}
+
+	bookingID, err := u.Repo.Booking.CreateBooking(ctx, booking, req.SeatIDs)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The showtime was cancelled after it was read
+		return dto.BookingResponse{}, ErrShowtimeCancelled
+	}
 	if err != nil {
 		return dto.BookingResponse{}, err
 	}
 
+	u.SeatHub.Publish(realtime.SeatEvent{
+		ShowtimeID: req.ShowtimeID,
+		SeatIDs:    req.SeatIDs,
+		State:      entity.SeatHeld,
+		Reason:     realtime.ReasonBookingCreated,
+	})
+
 	// Get created booking for response
 	createdBooking, err := u.Repo.Booking.GetBookingByID(ctx, bookingID)
 	if err != nil {
@@ -88,11 +353,16 @@ func (u *BookingUseCase) CreateBooking(ctx context.Context, userID int, req dto.
 
 	// Build response
 	seats, _ := u.Repo.Seat.GetSeatsByIDs(ctx, req.SeatIDs)
+	bookingSeats, _ := u.Repo.Booking.GetBookingSeats(ctx, bookingID)
+	bookingConcessions, _ := u.Repo.Booking.GetBookingConcessions(ctx, bookingID)
+	prices := seatPrices(bookingSeats)
 	var seatResponses []dto.SeatResponse
 	for _, s := range seats {
 		seatResponses = append(seatResponses, dto.SeatResponse{
 			ID:       s.ID,
 			SeatCode: s.SeatCode,
+			Category: s.Category,
+			Price:    prices[s.ID],
 			IsBooked: true,
 		})
 	}
@@ -103,7 +373,7 @@ func (u *BookingUseCase) CreateBooking(ctx context.Context, userID int, req dto.
 			ID:       showtime.ID,
 			ShowDate: showtime.ShowDate,
 			ShowTime: showtime.ShowTime,
-			Price:    showtime.Price,
+			Price:    createdBooking.ShowtimePrice,
 			Movie: dto.MovieResponse{
 				ID:              showtime.Movie.ID,
 				Title:           showtime.Movie.Title,
@@ -118,10 +388,17 @@ func (u *BookingUseCase) CreateBooking(ctx context.Context, userID int, req dto.
 				TotalSeats: showtime.Studio.TotalSeats,
 			},
 		},
-		Seats:       seatResponses,
-		TotalAmount: createdBooking.TotalAmount,
-		Status:      createdBooking.Status,
-		CreatedAt:   createdBooking.CreatedAt,
+		Seats:                seatResponses,
+		TotalAmount:          createdBooking.TotalAmount,
+		DiscountAmount:       createdBooking.DiscountAmount,
+		PromoCode:            createdBooking.PromoCode,
+		PointsRedeemed:       createdBooking.PointsRedeemed,
+		PointsDiscount:       createdBooking.PointsDiscount,
+		Concessions:          toBookingConcessionResponses(bookingConcessions),
+		Status:               createdBooking.Status,
+		ExpiresAt:            createdBooking.ExpiresAt,
+		HoldRemainingSeconds: holdRemainingSeconds(createdBooking, time.Now()),
+		CreatedAt:            createdBooking.CreatedAt,
 	}
 
 	// Send booking confirmation email asynchronously using GOROUTINE
@@ -189,13 +466,17 @@ func (u *BookingUseCase) GetUserBookings(ctx context.Context, userID int) ([]dto
 				seatIDs = append(seatIDs, bs.SeatID)
 			}
 
-			// Fetch seat details
+			// Fetch seat details and concessions
 			seats, _ := u.Repo.Seat.GetSeatsByIDs(ctx, seatIDs)
+			bookingConcessions, _ := u.Repo.Booking.GetBookingConcessions(ctx, booking.ID)
+			prices := seatPrices(bookingSeats)
 			var seatResponses []dto.SeatResponse
 			for _, s := range seats {
 				seatResponses = append(seatResponses, dto.SeatResponse{
 					ID:       s.ID,
 					SeatCode: s.SeatCode,
+					Category: s.Category,
+					Price:    prices[s.ID],
 				})
 			}
 
@@ -219,7 +500,7 @@ func (u *BookingUseCase) GetUserBookings(ctx context.Context, userID int) ([]dto
 					ID:       showtime.ID,
 					ShowDate: showtime.ShowDate,
 					ShowTime: showtime.ShowTime,
-					Price:    showtime.Price,
+					Price:    booking.ShowtimePrice,
 					Movie: dto.MovieResponse{
 						ID:              showtime.Movie.ID,
 						Title:           showtime.Movie.Title,
@@ -234,11 +515,18 @@ func (u *BookingUseCase) GetUserBookings(ctx context.Context, userID int) ([]dto
 						TotalSeats: showtime.Studio.TotalSeats,
 					},
 				},
-				Seats:       seatResponses,
-				TotalAmount: booking.TotalAmount,
-				Status:      booking.Status,
-				Payment:     paymentResp,
-				CreatedAt:   booking.CreatedAt,
+				Seats:                seatResponses,
+				TotalAmount:          booking.TotalAmount,
+				DiscountAmount:       booking.DiscountAmount,
+				PromoCode:            booking.PromoCode,
+				PointsRedeemed:       booking.PointsRedeemed,
+				PointsDiscount:       booking.PointsDiscount,
+				Concessions:          toBookingConcessionResponses(bookingConcessions),
+				Status:               booking.Status,
+				ExpiresAt:            booking.ExpiresAt,
+				HoldRemainingSeconds: holdRemainingSeconds(booking, time.Now()),
+				Payment:              paymentResp,
+				CreatedAt:            booking.CreatedAt,
 			}
 
 			resultCh <- bookingDetailResult{Index: index, Response: response, Err: nil}
@@ -262,3 +550,123 @@ func (u *BookingUseCase) GetUserBookings(ctx context.Context, userID int) ([]dto
 
 	return responses, nil
 }
+
+// ExpirePendingBookings releases the seats of pending bookings whose hold window has
+// passed, and of those still waiting on their payment once the payment grace is over
+func (u *BookingUseCase) ExpirePendingBookings(ctx context.Context) (int, error) {
+	expired, err := u.Repo.Booking.ExpirePendingBookings(ctx, u.paymentGrace())
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range expired {
+		publishBookingSeats(ctx, u.Repo, u.SeatHub, b, entity.SeatAvailable, realtime.ReasonBookingExpired)
+	}
+	return len(expired), nil
+}
+
+// CancelBooking cancels a user's booking before the cutoff and requests a refund for paid bookings
+func (u *BookingUseCase) CancelBooking(ctx context.Context, userID, bookingID int, req dto.CancelBookingRequest) (dto.CancelBookingResponse, error) {
+	booking, err := u.Repo.Booking.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return dto.CancelBookingResponse{}, ErrBookingNotFound
+	}
+
+	if booking.UserID != userID {
+		return dto.CancelBookingResponse{}, ErrBookingNotOwned
+	}
+
+	if booking.Status != "pending" && booking.Status != "paid" {
+		return dto.CancelBookingResponse{}, ErrBookingNotCancellable
+	}
+
+	// Enforce the cutoff relative to the showtime start
+	showtime, err := u.Repo.Seat.GetShowtimeByID(ctx, booking.ShowtimeID)
+	if err != nil {
+		return dto.CancelBookingResponse{}, errors.New("showtime not found")
+	}
+	start, err := showtimeStart(showtime)
+	if err != nil {
+		return dto.CancelBookingResponse{}, err
+	}
+	if !time.Now().Before(start.Add(-u.CancelCutoff)) {
+		return dto.CancelBookingResponse{}, ErrCancellationClosed
+	}
+
+	// Paid bookings get a refund linked to their payment
+	var refund *entity.Refund
+	var payment entity.Payment
+	if booking.Status == "paid" {
+		payment, err = u.Repo.Payment.GetPaymentByBookingID(ctx, bookingID)
+		if err != nil {
+			return dto.CancelBookingResponse{}, errors.New("payment not found for booking")
+		}
+		refund = &entity.Refund{
+			PaymentID: payment.ID,
+			Amount:    booking.TotalAmount,
+			Status:    "pending",
+			Reason:    req.Reason,
+		}
+	}
+
+	refundID, err := u.Repo.Booking.CancelBooking(ctx, bookingID, booking.Status, refund)
+	if errors.Is(err, repository.ErrBookingStatusChanged) {
+		return dto.CancelBookingResponse{}, ErrBookingNotCancellable
+	}
+	if err != nil {
+		return dto.CancelBookingResponse{}, err
+	}
+
+	publishBookingSeats(ctx, u.Repo, u.SeatHub, booking, entity.SeatAvailable, realtime.ReasonBookingCancelled)
+
+	response := dto.CancelBookingResponse{
+		BookingID: bookingID,
+		Status:    "cancelled",
+	}
+	if refund != nil {
+		response.Refund = &dto.RefundResponse{
+			ID:        refundID,
+			Amount:    refund.Amount,
+			Status:    processRefund(ctx, u.Repo, u.Gateways, refundID, payment, refund.Amount),
+			CreatedAt: time.Now(),
+		}
+	}
+
+	// GOROUTINE: Non-blocking cancellation notice
+	user, _ := u.Repo.Auth.GetUserByID(ctx, userID)
+	if user.Email != "" {
+		go u.sendCancellationNotice(user.Email, user.Username, showtime.Movie.Title, bookingID, response.Refund)
+	}
+
+	return response, nil
+}
+
+// processRefund sends a refund to the payment's gateway and records the outcome.
+// Payments without a provider transaction stay pending for manual processing.
+func processRefund(ctx context.Context, repo *repository.Repository, gateways *gateway.Registry, refundID int, payment entity.Payment, amount float64) string {
+	if payment.TransactionID == "" {
+		return "pending"
+	}
+
+	method, err := repo.Payment.GetPaymentMethodByID(ctx, payment.PaymentMethodID)
+	if err != nil {
+		return "pending"
+	}
+	gw, err := gateways.Get(method.Gateway)
+	if err != nil {
+		return "pending"
+	}
+
+	if err := repo.Payment.UpdateRefundStatus(ctx, refundID, "processing"); err != nil {
+		return "pending"
+	}
+
+	result, err := gw.Refund(ctx, payment.TransactionID, amount)
+	if err != nil || result.Status != gateway.StatusRefunded {
+		repo.Payment.UpdateRefundStatus(ctx, refundID, "failed")
+		return "failed"
+	}
+
+	repo.Payment.UpdateRefundStatus(ctx, refundID, "completed")
+	repo.Payment.UpdatePaymentStatus(ctx, payment.ID, gateway.StatusRefunded)
+	return "completed"
+}
@@ -6,10 +6,14 @@ import (
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
 	"testing"
 	"time"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,8 +26,8 @@ type MockBookingRepo struct {
 	mock.Mock
 }
 
-func (m *MockBookingRepo) CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int, price float64) (int, error) {
-	args := m.Called(ctx, booking, seatIDs, price)
+func (m *MockBookingRepo) CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int) (int, error) {
+	args := m.Called(ctx, booking, seatIDs)
 	return args.Int(0), args.Error(1)
 }
 
@@ -42,11 +46,26 @@ func (m *MockBookingRepo) GetBookingSeats(ctx context.Context, bookingID int) ([
 	return args.Get(0).([]entity.BookingSeat), args.Error(1)
 }
 
+func (m *MockBookingRepo) GetBookingConcessions(ctx context.Context, bookingID int) ([]entity.BookingConcession, error) {
+	args := m.Called(ctx, bookingID)
+	return args.Get(0).([]entity.BookingConcession), args.Error(1)
+}
+
 func (m *MockBookingRepo) UpdateBookingStatus(ctx context.Context, bookingID int, status string) error {
 	args := m.Called(ctx, bookingID, status)
 	return args.Error(0)
 }
 
+func (m *MockBookingRepo) ExpirePendingBookings(ctx context.Context, paymentGrace time.Duration) ([]entity.Booking, error) {
+	args := m.Called(ctx, paymentGrace)
+	return args.Get(0).([]entity.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) CancelBooking(ctx context.Context, bookingID int, fromStatus string, refund *entity.Refund) (int, error) {
+	args := m.Called(ctx, bookingID, fromStatus, refund)
+	return args.Int(0), args.Error(1)
+}
+
 // =====================
 // Mock Repository untuk Seat (Booking test)
 // =====================
@@ -60,11 +79,6 @@ func (m *MockSeatRepoForBooking) GetSeatsByShowtime(ctx context.Context, showtim
 	return args.Get(0).([]entity.SeatAvailability), args.Error(1)
 }
 
-func (m *MockSeatRepoForBooking) GetShowtimeByParams(ctx context.Context, cinemaID int, date, time string) (entity.Showtime, error) {
-	args := m.Called(ctx, cinemaID, date, time)
-	return args.Get(0).(entity.Showtime), args.Error(1)
-}
-
 func (m *MockSeatRepoForBooking) GetShowtimeByID(ctx context.Context, id int) (entity.Showtime, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(entity.Showtime), args.Error(1)
@@ -118,6 +132,41 @@ func (m *MockPaymentRepoForBooking) UpdatePaymentStatus(ctx context.Context, pay
 	return args.Error(0)
 }
 
+func (m *MockPaymentRepoForBooking) SettlePayment(ctx context.Context, paymentID, bookingID int, status, transactionID string) error {
+	args := m.Called(ctx, paymentID, bookingID, status, transactionID)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepoForBooking) ApplyWebhookEvent(ctx context.Context, event entity.PaymentWebhookEvent) (entity.Payment, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepoForBooking) GetStalePendingPayments(ctx context.Context) ([]entity.Payment, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepoForBooking) GetRefundByBookingID(ctx context.Context, bookingID int) (entity.Refund, error) {
+	args := m.Called(ctx, bookingID)
+	return args.Get(0).(entity.Refund), args.Error(1)
+}
+
+func (m *MockPaymentRepoForBooking) UpdateRefundStatus(ctx context.Context, refundID int, status string) error {
+	args := m.Called(ctx, refundID, status)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepoForBooking) GetPaymentByID(ctx context.Context, id int) (entity.Payment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepoForBooking) GetFailedRefunds(ctx context.Context) ([]entity.Refund, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Refund), args.Error(1)
+}
+
 // =====================
 // Mock Auth Repo (Booking test)
 // =====================
@@ -151,6 +200,16 @@ func (m *MockAuthRepoForBooking) UpdateUserVerified(ctx context.Context, userID
 	return args.Error(0)
 }
 
+func (m *MockAuthRepoForBooking) UpdateUserRole(ctx context.Context, userID int, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepoForBooking) UpdateUserBirthDate(ctx context.Context, userID int, dateOfBirth time.Time) error {
+	args := m.Called(ctx, userID, dateOfBirth)
+	return args.Error(0)
+}
+
 func (m *MockAuthRepoForBooking) CreateSession(ctx context.Context, session entity.Session) error {
 	args := m.Called(ctx, session)
 	return args.Error(0)
@@ -217,6 +276,104 @@ func TestBookingUseCase_CreateBooking_ShowtimeNotFound(t *testing.T) {
 	mockSeatRepo.AssertExpectations(t)
 }
 
+func TestBookingUseCase_CreateBooking_ShowtimeCancelled(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	showtime := entity.Showtime{ID: 1, ShowDate: "2026-01-15", ShowTime: "19:00", Status: entity.ShowtimeCancelled}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+
+	_, err := usecase.CreateBooking(context.Background(), 1, dto.BookingRequest{ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1})
+
+	assert.ErrorIs(t, err, ErrShowtimeCancelled)
+	mockSeatRepo.AssertNotCalled(t, "CheckSeatsAvailable", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBookingUseCase_CreateBooking_AgeRating(t *testing.T) {
+	restricted := entity.Showtime{
+		ID:       1,
+		ShowDate: "2026-06-15",
+		ShowTime: "19:00",
+		Movie:    &entity.Movie{ID: 1, Title: "Horror Night", AgeRating: entity.AgeRating17},
+	}
+	birthDate := func(value string) *time.Time {
+		d, _ := time.Parse("2006-01-02", value)
+		return &d
+	}
+
+	t.Run("Underage", func(t *testing.T) {
+		mockSeatRepo := new(MockSeatRepoForBooking)
+		mockAuthRepo := new(MockAuthRepoForBooking)
+		usecase := &BookingUseCase{Repo: &repository.Repository{Seat: mockSeatRepo, Auth: mockAuthRepo}}
+
+		mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(restricted, nil)
+		// Turns 17 the day after the show
+		mockAuthRepo.On("GetUserByID", mock.Anything, 7).Return(entity.User{ID: 7, DateOfBirth: birthDate("2009-06-16")}, nil)
+
+		_, err := usecase.CreateBooking(context.Background(), 7, dto.BookingRequest{ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1})
+
+		assert.ErrorIs(t, err, ErrAgeRestricted)
+		mockSeatRepo.AssertNotCalled(t, "CheckSeatsAvailable", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Birth Date Missing", func(t *testing.T) {
+		mockSeatRepo := new(MockSeatRepoForBooking)
+		mockAuthRepo := new(MockAuthRepoForBooking)
+		usecase := &BookingUseCase{Repo: &repository.Repository{Seat: mockSeatRepo, Auth: mockAuthRepo}}
+
+		mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(restricted, nil)
+		mockAuthRepo.On("GetUserByID", mock.Anything, 7).Return(entity.User{ID: 7}, nil)
+
+		_, err := usecase.CreateBooking(context.Background(), 7, dto.BookingRequest{ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1})
+
+		assert.ErrorIs(t, err, ErrBirthDateRequired)
+	})
+
+	t.Run("Old Enough On Show Date", func(t *testing.T) {
+		mockSeatRepo := new(MockSeatRepoForBooking)
+		mockAuthRepo := new(MockAuthRepoForBooking)
+		usecase := &BookingUseCase{Repo: &repository.Repository{Seat: mockSeatRepo, Auth: mockAuthRepo}}
+
+		mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(restricted, nil)
+		mockAuthRepo.On("GetUserByID", mock.Anything, 7).Return(entity.User{ID: 7, DateOfBirth: birthDate("2009-06-15")}, nil)
+		mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(false, nil)
+
+		_, err := usecase.CreateBooking(context.Background(), 7, dto.BookingRequest{ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1})
+
+		// Passes the age check and reaches seat availability
+		assert.ErrorIs(t, err, ErrSeatTaken)
+	})
+
+	t.Run("Unrestricted Movie Skips Profile", func(t *testing.T) {
+		mockSeatRepo := new(MockSeatRepoForBooking)
+		mockAuthRepo := new(MockAuthRepoForBooking)
+		usecase := &BookingUseCase{Repo: &repository.Repository{Seat: mockSeatRepo, Auth: mockAuthRepo}}
+
+		allAges := restricted
+		allAges.Movie = &entity.Movie{ID: 2, AgeRating: entity.AgeRatingAll}
+		mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(allAges, nil)
+		mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(false, nil)
+
+		_, err := usecase.CreateBooking(context.Background(), 7, dto.BookingRequest{ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1})
+
+		assert.ErrorIs(t, err, ErrSeatTaken)
+		mockAuthRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAgeOn(t *testing.T) {
+	dob := time.Date(2008, 2, 29, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 17, ageOn(dob, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 18, ageOn(dob, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 16, ageOn(dob, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)))
+}
+
 func TestBookingUseCase_CreateBooking_SeatsNotAvailable(t *testing.T) {
 	mockBookingRepo := new(MockBookingRepo)
 	mockSeatRepo := new(MockSeatRepoForBooking)
@@ -327,17 +484,23 @@ func TestBookingUseCase_CreateBooking_Success(t *testing.T) {
 	}
 
 	seats := []entity.Seat{
-		{ID: 1, SeatCode: "A1", StudioID: 1},
-		{ID: 2, SeatCode: "A2", StudioID: 1},
+		{ID: 1, SeatCode: "A1", StudioID: 1, Category: "regular"},
+		{ID: 2, SeatCode: "A2", StudioID: 1, Category: "vip"},
+	}
+	// A pricing rule took the showtime from 50000 down to 40000 when it was booked
+	bookingSeats := []entity.BookingSeat{
+		{ID: 1, BookingID: 1, SeatID: 1, PriceSnapshot: 40000},
+		{ID: 2, BookingID: 1, SeatID: 2, PriceSnapshot: 60000},
 	}
 
 	createdBooking := entity.Booking{
-		ID:          1,
-		UserID:      1,
-		ShowtimeID:  1,
-		Status:      "pending",
-		TotalAmount: 100000,
-		CreatedAt:   now,
+		ID:            1,
+		UserID:        1,
+		ShowtimeID:    1,
+		Status:        "pending",
+		TotalAmount:   100000,
+		CreatedAt:     now,
+		ShowtimePrice: 40000,
 	}
 
 	user := entity.User{
@@ -349,9 +512,11 @@ func TestBookingUseCase_CreateBooking_Success(t *testing.T) {
 	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
 	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1, 2}).Return(true, nil)
 	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1, Name: "Credit Card"}, nil)
-	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{1, 2}, 50000.0).Return(1, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{1, 2}).Return(1, nil)
 	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(createdBooking, nil)
 	mockSeatRepo.On("GetSeatsByIDs", mock.Anything, []int{1, 2}).Return(seats, nil)
+	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return(bookingSeats, nil)
+	mockBookingRepo.On("GetBookingConcessions", mock.Anything, 1).Return([]entity.BookingConcession{}, nil)
 	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(user, nil)
 
 	req := dto.BookingRequest{
@@ -366,13 +531,438 @@ func TestBookingUseCase_CreateBooking_Success(t *testing.T) {
 	assert.Equal(t, 1, result.ID)
 	assert.Equal(t, "pending", result.Status)
 	assert.Equal(t, 100000.0, result.TotalAmount)
+	assert.Equal(t, 40000.0, result.Showtime.Price)
 	assert.Len(t, result.Seats, 2)
+	assert.Equal(t, "vip", result.Seats[1].Category)
+	assert.Equal(t, 60000.0, result.Seats[1].Price)
 	assert.Equal(t, "Avengers", result.Showtime.Movie.Title)
 	mockSeatRepo.AssertExpectations(t)
 	mockPaymentRepo.AssertExpectations(t)
 	mockBookingRepo.AssertExpectations(t)
 }
 
+func TestBookingUseCase_CreateBooking_WithPromoCode(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+	mockPromotionRepo := new(MockPromotionRepo)
+
+	repo := &repository.Repository{
+		Booking:   mockBookingRepo,
+		Seat:      mockSeatRepo,
+		Payment:   mockPaymentRepo,
+		Auth:      mockAuthRepo,
+		Promotion: mockPromotionRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	now := time.Now()
+	showtime := entity.Showtime{
+		ID:       1,
+		CinemaID: 1,
+		MovieID:  1,
+		ShowDate: "2026-01-15",
+		ShowTime: "19:00",
+		Price:    50000,
+		Movie:    &entity.Movie{ID: 1, Title: "Avengers"},
+		Studio:   &entity.Studio{ID: 1, Name: "Studio 1", TotalSeats: 100},
+	}
+	promotion := entity.Promotion{
+		ID:            3,
+		Code:          "HEMAT10",
+		DiscountType:  entity.PromotionPercentage,
+		DiscountValue: 10,
+		ValidFrom:     now.Add(-time.Hour),
+		ValidUntil:    now.Add(time.Hour),
+		IsActive:      true,
+	}
+	createdBooking := entity.Booking{
+		ID:             1,
+		UserID:         1,
+		ShowtimeID:     1,
+		Status:         "pending",
+		TotalAmount:    90000,
+		PromotionID:    &promotion.ID,
+		PromoCode:      "HEMAT10",
+		DiscountAmount: 10000,
+		CreatedAt:      now,
+	}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1, 2}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1, Name: "Credit Card"}, nil)
+	mockPromotionRepo.On("GetPromotionByCode", mock.Anything, "hemat10").Return(promotion, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.MatchedBy(func(b entity.Booking) bool {
+		return b.PromotionID != nil && *b.PromotionID == 3 && b.PromoCode == "HEMAT10"
+	}), []int{1, 2}).Return(1, nil)
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(createdBooking, nil)
+	mockSeatRepo.On("GetSeatsByIDs", mock.Anything, []int{1, 2}).Return([]entity.Seat{}, nil)
+	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return([]entity.BookingSeat{}, nil)
+	mockBookingRepo.On("GetBookingConcessions", mock.Anything, 1).Return([]entity.BookingConcession{}, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	req := dto.BookingRequest{
+		ShowtimeID:    1,
+		SeatIDs:       []int{1, 2},
+		PaymentMethod: 1,
+		PromoCode:     " hemat10 ",
+	}
+
+	result, err := usecase.CreateBooking(context.Background(), 1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 90000.0, result.TotalAmount)
+	assert.Equal(t, 10000.0, result.DiscountAmount)
+	assert.Equal(t, "HEMAT10", result.PromoCode)
+	mockPromotionRepo.AssertExpectations(t)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CreateBooking_PromoCodeNotFound(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockPromotionRepo := new(MockPromotionRepo)
+
+	repo := &repository.Repository{
+		Booking:   mockBookingRepo,
+		Seat:      mockSeatRepo,
+		Payment:   mockPaymentRepo,
+		Promotion: mockPromotionRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(entity.Showtime{ID: 1, ShowDate: "2026-01-15"}, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1}, nil)
+	mockPromotionRepo.On("GetPromotionByCode", mock.Anything, "NOPE").Return(entity.Promotion{}, pgx.ErrNoRows)
+
+	_, err := usecase.CreateBooking(context.Background(), 1, dto.BookingRequest{
+		ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1, PromoCode: "NOPE",
+	})
+
+	assert.ErrorIs(t, err, ErrPromoNotFound)
+	mockBookingRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBookingUseCase_CreateBooking_RedeemPoints(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+		Auth:    mockAuthRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo, Loyalty: utils.LoyaltyConfig{PointValue: 10}}
+
+	showtime := entity.Showtime{
+		ID:       1,
+		ShowDate: "2026-01-15",
+		ShowTime: "19:00",
+		Price:    50000,
+		Movie:    &entity.Movie{ID: 1, Title: "Avengers"},
+		Studio:   &entity.Studio{ID: 1, Name: "Studio 1", TotalSeats: 100},
+	}
+	createdBooking := entity.Booking{
+		ID:             1,
+		UserID:         1,
+		ShowtimeID:     1,
+		Status:         "pending",
+		TotalAmount:    47500,
+		PointsRedeemed: 250,
+		PointsDiscount: 2500,
+	}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1}, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.MatchedBy(func(b entity.Booking) bool {
+		return b.PointsRedeemed == 250 && b.PointsDiscount == 2500
+	}), []int{1}).Return(1, nil)
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(createdBooking, nil)
+	mockSeatRepo.On("GetSeatsByIDs", mock.Anything, []int{1}).Return([]entity.Seat{}, nil)
+	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return([]entity.BookingSeat{}, nil)
+	mockBookingRepo.On("GetBookingConcessions", mock.Anything, 1).Return([]entity.BookingConcession{}, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.CreateBooking(context.Background(), 1, dto.BookingRequest{
+		ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1, RedeemPoints: 250,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 47500.0, result.TotalAmount)
+	assert.Equal(t, 250, result.PointsRedeemed)
+	assert.Equal(t, 2500.0, result.PointsDiscount)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CreateBooking_WithConcessions(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+		Auth:    mockAuthRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	showtime := entity.Showtime{
+		ID:       1,
+		ShowDate: "2026-01-15",
+		ShowTime: "19:00",
+		Price:    50000,
+		Movie:    &entity.Movie{ID: 1, Title: "Avengers"},
+		Studio:   &entity.Studio{ID: 1, Name: "Studio 1", TotalSeats: 100},
+	}
+	createdBooking := entity.Booking{ID: 1, UserID: 1, ShowtimeID: 1, Status: "pending", TotalAmount: 180000}
+	bookingConcessions := []entity.BookingConcession{
+		{ID: 1, BookingID: 1, ConcessionID: 2, Name: "Popcorn Combo", Quantity: 2, PriceSnapshot: 65000},
+	}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1}, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.MatchedBy(func(b entity.Booking) bool {
+		return len(b.Concessions) == 1 && b.Concessions[0].ConcessionID == 2 && b.Concessions[0].Quantity == 2
+	}), []int{1}).Return(1, nil)
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(createdBooking, nil)
+	mockSeatRepo.On("GetSeatsByIDs", mock.Anything, []int{1}).Return([]entity.Seat{}, nil)
+	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return([]entity.BookingSeat{}, nil)
+	mockBookingRepo.On("GetBookingConcessions", mock.Anything, 1).Return(bookingConcessions, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.CreateBooking(context.Background(), 1, dto.BookingRequest{
+		ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1,
+		Concessions: []dto.BookingConcessionRequest{{ConcessionID: 2, Quantity: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 180000.0, result.TotalAmount)
+	assert.Len(t, result.Concessions, 1)
+	assert.Equal(t, "Popcorn Combo", result.Concessions[0].Name)
+	assert.Equal(t, 130000.0, result.Concessions[0].Subtotal)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CreateBooking_ConcessionUnavailable(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(entity.Showtime{ID: 1, ShowDate: "2026-01-15"}, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1}, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{1}).
+		Return(0, repository.ErrConcessionUnavailable)
+
+	_, err := usecase.CreateBooking(context.Background(), 1, dto.BookingRequest{
+		ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1,
+		Concessions: []dto.BookingConcessionRequest{{ConcessionID: 9, Quantity: 1}},
+	})
+
+	assert.ErrorIs(t, err, ErrConcessionUnavailable)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CreateBooking_InsufficientPoints(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(entity.Showtime{ID: 1, ShowDate: "2026-01-15"}, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1}, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.MatchedBy(func(b entity.Booking) bool {
+		return b.PointsRedeemed == 500 && b.PointsDiscount == 500
+	}), []int{1}).Return(0, repository.ErrInsufficientPoints)
+
+	_, err := usecase.CreateBooking(context.Background(), 1, dto.BookingRequest{
+		ShowtimeID: 1, SeatIDs: []int{1}, PaymentMethod: 1, RedeemPoints: 500,
+	})
+
+	assert.ErrorIs(t, err, ErrInsufficientPoints)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestCheckPromotion(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	movieID, cinemaID, paymentMethodID := 1, 2, 3
+	otherID := 9
+	// 2026-01-15 is a Thursday
+	showtime := entity.Showtime{MovieID: movieID, CinemaID: cinemaID, ShowDate: "2026-01-15"}
+	base := entity.Promotion{
+		MovieID:         &movieID,
+		CinemaID:        &cinemaID,
+		PaymentMethodID: &paymentMethodID,
+		DaysOfWeek:      []int{int(time.Thursday), int(time.Friday)},
+		MinSeats:        2,
+		ValidFrom:       now.Add(-time.Hour),
+		ValidUntil:      now.Add(time.Hour),
+		IsActive:        true,
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(p *entity.Promotion)
+		seats    int
+		expected error
+	}{
+		{"applies", func(p *entity.Promotion) {}, 2, nil},
+		{"inactive", func(p *entity.Promotion) { p.IsActive = false }, 2, ErrPromoUnavailable},
+		{"not started", func(p *entity.Promotion) { p.ValidFrom = now.Add(time.Minute) }, 2, ErrPromoUnavailable},
+		{"ended", func(p *entity.Promotion) { p.ValidUntil = now.Add(-time.Minute) }, 2, ErrPromoUnavailable},
+		{"other movie", func(p *entity.Promotion) { p.MovieID = &otherID }, 2, ErrPromoNotApplicable},
+		{"other cinema", func(p *entity.Promotion) { p.CinemaID = &otherID }, 2, ErrPromoNotApplicable},
+		{"other day", func(p *entity.Promotion) { p.DaysOfWeek = []int{int(time.Saturday)} }, 2, ErrPromoNotApplicable},
+		{"other payment method", func(p *entity.Promotion) { p.PaymentMethodID = &otherID }, 2, ErrPromoNotApplicable},
+		{"too few seats", func(p *entity.Promotion) {}, 1, ErrPromoNotApplicable},
+		{"unrestricted", func(p *entity.Promotion) {
+			p.MovieID, p.CinemaID, p.PaymentMethodID, p.DaysOfWeek, p.MinSeats = nil, nil, nil, nil, 0
+		}, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := base
+			tt.modify(&promotion)
+			err := checkPromotion(promotion, showtime, paymentMethodID, tt.seats, now)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBookingUseCase_CreateBooking_AutoSelectSeats(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+		Auth:    mockAuthRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo, EmailService: utils.NewEmailService()}
+
+	showtime := entity.Showtime{
+		ID:       1,
+		CinemaID: 1,
+		ShowDate: "2026-01-15",
+		ShowTime: "19:00",
+		Price:    50000,
+		Movie:    &entity.Movie{ID: 1, Title: "Avengers"},
+		Studio:   &entity.Studio{ID: 1, Name: "Studio 1"},
+	}
+	available := []entity.SeatAvailability{
+		{ID: 1, SeatCode: "A1", RowLabel: "A", ColumnNumber: 1, State: entity.SeatBooked},
+		{ID: 2, SeatCode: "A2", RowLabel: "A", ColumnNumber: 2, State: entity.SeatAvailable},
+		{ID: 3, SeatCode: "A3", RowLabel: "A", ColumnNumber: 3, State: entity.SeatAvailable},
+		{ID: 4, SeatCode: "A4", RowLabel: "A", ColumnNumber: 4, State: entity.SeatAvailable},
+		{ID: 5, SeatCode: "A5", RowLabel: "A", ColumnNumber: 5, State: entity.SeatAvailable},
+	}
+	seats := []entity.Seat{
+		{ID: 2, SeatCode: "A2", StudioID: 1},
+		{ID: 3, SeatCode: "A3", StudioID: 1},
+	}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(available, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{2, 3}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1}, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{2, 3}).Return(1, nil)
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(entity.Booking{ID: 1, UserID: 1, ShowtimeID: 1, Status: "pending", TotalAmount: 100000}, nil)
+	mockSeatRepo.On("GetSeatsByIDs", mock.Anything, []int{2, 3}).Return(seats, nil)
+	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return([]entity.BookingSeat{}, nil)
+	mockBookingRepo.On("GetBookingConcessions", mock.Anything, 1).Return([]entity.BookingConcession{}, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	req := dto.BookingRequest{
+		ShowtimeID:    1,
+		SeatCount:     2,
+		PaymentMethod: 1,
+	}
+
+	result, err := usecase.CreateBooking(context.Background(), 1, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Seats, 2)
+	assert.Equal(t, "A2", result.Seats[0].SeatCode)
+	mockSeatRepo.AssertExpectations(t)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CreateBooking_DuplicateSeats(t *testing.T) {
+	req := dto.BookingRequest{ShowtimeID: 1, SeatIDs: []int{5, 5}, PaymentMethod: 1}
+
+	t.Run("Rejected By Request Validation", func(t *testing.T) {
+		err := validator.New().Struct(req)
+		assert.ErrorContains(t, err, "'unique' tag")
+	})
+
+	t.Run("Rejected Before Pricing", func(t *testing.T) {
+		mockSeatRepo := new(MockSeatRepoForBooking)
+		mockBookingRepo := new(MockBookingRepo)
+		repo := &repository.Repository{Seat: mockSeatRepo, Booking: mockBookingRepo}
+		usecase := &BookingUseCase{Repo: repo, EmailService: utils.NewEmailService()}
+
+		mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(entity.Showtime{ID: 1, ShowDate: "2026-01-15", ShowTime: "19:00"}, nil)
+
+		_, err := usecase.CreateBooking(context.Background(), 1, req)
+
+		assert.ErrorIs(t, err, ErrDuplicateSeat)
+		mockSeatRepo.AssertNotCalled(t, "CheckSeatsAvailable", mock.Anything, mock.Anything, mock.Anything)
+		mockBookingRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestBookingUseCase_CreateBooking_NotEnoughSeats(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	usecase := &BookingUseCase{Repo: repo, EmailService: utils.NewEmailService()}
+
+	showtime := entity.Showtime{ID: 1, ShowDate: "2026-01-15", ShowTime: "19:00"}
+	available := []entity.SeatAvailability{
+		{ID: 1, SeatCode: "A1", RowLabel: "A", ColumnNumber: 1, State: entity.SeatAvailable},
+	}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(available, nil)
+
+	req := dto.BookingRequest{ShowtimeID: 1, SeatCount: 2, PaymentMethod: 1}
+
+	_, err := usecase.CreateBooking(context.Background(), 1, req)
+
+	assert.ErrorIs(t, err, ErrNotEnoughSeats)
+	mockSeatRepo.AssertExpectations(t)
+}
+
 func TestBookingUseCase_GetUserBookings_Success(t *testing.T) {
 	mockBookingRepo := new(MockBookingRepo)
 	mockSeatRepo := new(MockSeatRepoForBooking)
@@ -390,10 +980,11 @@ func TestBookingUseCase_GetUserBookings_Success(t *testing.T) {
 	studio := &entity.Studio{ID: 1, Name: "Studio 1", TotalSeats: 100}
 
 	bookings := []entity.Booking{
-		{ID: 1, UserID: 1, ShowtimeID: 1, Status: "paid", TotalAmount: 100000, CreatedAt: now},
+		{ID: 1, UserID: 1, ShowtimeID: 1, Status: "paid", TotalAmount: 100000, CreatedAt: now, ShowtimePrice: 50000},
 	}
 
-	showtime1 := entity.Showtime{ID: 1, ShowDate: "2026-01-15", ShowTime: "19:00", Price: 50000, Movie: movie, Studio: studio}
+	// The showtime's base price changed after the booking was made
+	showtime1 := entity.Showtime{ID: 1, ShowDate: "2026-01-15", ShowTime: "19:00", Price: 65000, Movie: movie, Studio: studio}
 
 	bookingSeats := []entity.BookingSeat{
 		{ID: 1, BookingID: 1, SeatID: 1, PriceSnapshot: 50000},
@@ -418,6 +1009,7 @@ func TestBookingUseCase_GetUserBookings_Success(t *testing.T) {
 	mockBookingRepo.On("GetBookingsByUserID", mock.Anything, 1).Return(bookings, nil)
 	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime1, nil)
 	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return(bookingSeats, nil)
+	mockBookingRepo.On("GetBookingConcessions", mock.Anything, 1).Return([]entity.BookingConcession{}, nil)
 	mockSeatRepo.On("GetSeatsByIDs", mock.Anything, []int{1, 2}).Return(seats, nil)
 	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(payment, nil)
 	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(paymentMethod, nil)
@@ -427,6 +1019,7 @@ func TestBookingUseCase_GetUserBookings_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Equal(t, "paid", result[0].Status)
+	assert.Equal(t, 50000.0, result[0].Showtime.Price)
 	mockBookingRepo.AssertExpectations(t)
 	mockSeatRepo.AssertExpectations(t)
 }
@@ -477,7 +1070,7 @@ func TestBookingUseCase_CreateBooking_CreateBookingError(t *testing.T) {
 	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
 	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1, 2}).Return(true, nil)
 	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1, Name: "Credit Card"}, nil)
-	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{1, 2}, 50000.0).Return(0, errors.New("database error"))
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{1, 2}).Return(0, errors.New("database error"))
 
 	req := dto.BookingRequest{
 		ShowtimeID:    1,
@@ -492,3 +1085,259 @@ func TestBookingUseCase_CreateBooking_CreateBookingError(t *testing.T) {
 	mockPaymentRepo.AssertExpectations(t)
 	mockBookingRepo.AssertExpectations(t)
 }
+
+func TestBookingUseCase_CreateBooking_SeatTakenConcurrently(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	movie := &entity.Movie{ID: 1, Title: "Avengers", Genres: []string{"Action"}}
+	studio := &entity.Studio{ID: 1, Name: "Studio 1", TotalSeats: 100}
+	showtime := entity.Showtime{ID: 1, CinemaID: 1, Price: 50000, Movie: movie, Studio: studio}
+
+	// Pre-check passes but another request wins the seat inside the transaction
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("CheckSeatsAvailable", mock.Anything, 1, []int{1}).Return(true, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1, Name: "QRIS"}, nil)
+	mockBookingRepo.On("CreateBooking", mock.Anything, mock.AnythingOfType("entity.Booking"), []int{1}).Return(0, repository.ErrSeatTaken)
+
+	req := dto.BookingRequest{
+		ShowtimeID:    1,
+		SeatIDs:       []int{1},
+		PaymentMethod: 1,
+	}
+
+	_, err := usecase.CreateBooking(context.Background(), 1, req)
+
+	assert.ErrorIs(t, err, ErrSeatTaken)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_ExpirePendingBookings(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	repo := &repository.Repository{Booking: mockBookingRepo}
+	usecase := &BookingUseCase{Repo: repo}
+
+	expired := []entity.Booking{
+		{ID: 1, ShowtimeID: 1, Status: "expired"},
+		{ID: 2, ShowtimeID: 1, Status: "expired"},
+	}
+	mockBookingRepo.On("ExpirePendingBookings", mock.Anything, defaultPaymentGrace).Return(expired, nil)
+
+	count, err := usecase.ExpirePendingBookings(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_ExpirePendingBookings_PublishesReleasedSeats(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	repo := &repository.Repository{Booking: mockBookingRepo}
+	hub := realtime.NewHub()
+	usecase := &BookingUseCase{Repo: repo, SeatHub: hub}
+
+	sub := hub.Subscribe(1)
+	defer sub.Close()
+
+	expired := []entity.Booking{
+		{ID: 1, ShowtimeID: 1, Status: "expired"},
+		{ID: 2, ShowtimeID: 2, Status: "expired"},
+	}
+	mockBookingRepo.On("ExpirePendingBookings", mock.Anything, mock.Anything).Return(expired, nil)
+	mockBookingRepo.On("GetBookingSeats", mock.Anything, 1).Return([]entity.BookingSeat{
+		{BookingID: 1, SeatID: 7},
+		{BookingID: 1, SeatID: 8},
+	}, nil)
+
+	count, err := usecase.ExpirePendingBookings(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	event := <-sub.C
+	assert.Equal(t, 1, event.ShowtimeID)
+	assert.Equal(t, []int{7, 8}, event.SeatIDs)
+	assert.Equal(t, entity.SeatAvailable, event.State)
+	assert.Equal(t, realtime.ReasonBookingExpired, event.Reason)
+	// Nobody watches showtime 2, so its seats are never looked up
+	mockBookingRepo.AssertNotCalled(t, "GetBookingSeats", mock.Anything, 2)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestHoldRemainingSeconds(t *testing.T) {
+	now := time.Now()
+	future := now.Add(10 * time.Minute)
+	past := now.Add(-time.Minute)
+
+	assert.Equal(t, 600, holdRemainingSeconds(entity.Booking{Status: "pending", ExpiresAt: &future}, now))
+	assert.Equal(t, 0, holdRemainingSeconds(entity.Booking{Status: "pending", ExpiresAt: &past}, now))
+	assert.Equal(t, 0, holdRemainingSeconds(entity.Booking{Status: "paid", ExpiresAt: &future}, now))
+	assert.Equal(t, 0, holdRemainingSeconds(entity.Booking{Status: "pending"}, now))
+}
+
+func newCancelTestShowtime(start time.Time) entity.Showtime {
+	return entity.Showtime{
+		ID:       1,
+		CinemaID: 1,
+		ShowDate: start.Format("2006-01-02"),
+		ShowTime: start.Format("15:04:05"),
+		Price:    50000,
+		Movie:    &entity.Movie{ID: 1, Title: "Avengers"},
+		Studio:   &entity.Studio{ID: 1, Name: "Studio 1"},
+	}
+}
+
+func TestBookingUseCase_CancelBooking_PaidWithRefund(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+		Auth:    mockAuthRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo, CancelCutoff: time.Hour}
+
+	booking := entity.Booking{ID: 5, UserID: 1, ShowtimeID: 1, Status: "paid", TotalAmount: 100000}
+	showtime := newCancelTestShowtime(time.Now().Add(48 * time.Hour))
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 5).Return(booking, nil)
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 5).Return(entity.Payment{ID: 9, BookingID: 5}, nil)
+	mockBookingRepo.On("CancelBooking", mock.Anything, 5, "paid", mock.MatchedBy(func(r *entity.Refund) bool {
+		return r != nil && r.PaymentID == 9 && r.Amount == 100000 && r.Status == "pending"
+	})).Return(3, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.CancelBooking(context.Background(), 1, 5, dto.CancelBookingRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", result.Status)
+	assert.NotNil(t, result.Refund)
+	assert.Equal(t, 3, result.Refund.ID)
+	assert.Equal(t, "pending", result.Refund.Status)
+	mockBookingRepo.AssertExpectations(t)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CancelBooking_PaidRefundedThroughGateway(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockPaymentRepo := new(MockPaymentRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Payment: mockPaymentRepo,
+		Auth:    mockAuthRepo,
+	}
+	gateways := gateway.NewDefaultRegistry(gateway.ModeSuccess)
+	usecase := &BookingUseCase{Repo: repo, Gateways: gateways, CancelCutoff: time.Hour}
+
+	// Charge through the simulator so the refund has a transaction to reverse
+	gw, _ := gateways.Get(gateway.SimulatorName)
+	charge, _ := gw.Charge(context.Background(), gateway.ChargeRequest{PaymentID: 9, BookingID: 5, Amount: 100000})
+
+	booking := entity.Booking{ID: 5, UserID: 1, ShowtimeID: 1, Status: "paid", TotalAmount: 100000}
+	showtime := newCancelTestShowtime(time.Now().Add(48 * time.Hour))
+	payment := entity.Payment{ID: 9, BookingID: 5, PaymentMethodID: 1, TransactionID: charge.TransactionID}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 5).Return(booking, nil)
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 5).Return(payment, nil)
+	mockBookingRepo.On("CancelBooking", mock.Anything, 5, "paid", mock.Anything).Return(3, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1, Gateway: gateway.SimulatorName}, nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 3, "processing").Return(nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 3, "completed").Return(nil)
+	mockPaymentRepo.On("UpdatePaymentStatus", mock.Anything, 9, "refunded").Return(nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.CancelBooking(context.Background(), 1, 5, dto.CancelBookingRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", result.Refund.Status)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CancelBooking_PendingWithoutRefund(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	mockAuthRepo := new(MockAuthRepoForBooking)
+
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+		Auth:    mockAuthRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo}
+
+	booking := entity.Booking{ID: 5, UserID: 1, ShowtimeID: 1, Status: "pending"}
+	showtime := newCancelTestShowtime(time.Now().Add(2 * time.Hour))
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 5).Return(booking, nil)
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockBookingRepo.On("CancelBooking", mock.Anything, 5, "pending", (*entity.Refund)(nil)).Return(0, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.CancelBooking(context.Background(), 1, 5, dto.CancelBookingRequest{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.Refund)
+	mockBookingRepo.AssertExpectations(t)
+}
+
+func TestBookingUseCase_CancelBooking_NotOwner(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	repo := &repository.Repository{Booking: mockBookingRepo}
+	usecase := &BookingUseCase{Repo: repo}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 5).Return(entity.Booking{ID: 5, UserID: 2, Status: "paid"}, nil)
+
+	_, err := usecase.CancelBooking(context.Background(), 1, 5, dto.CancelBookingRequest{})
+
+	assert.ErrorIs(t, err, ErrBookingNotOwned)
+}
+
+func TestBookingUseCase_CancelBooking_AlreadyCancelled(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	repo := &repository.Repository{Booking: mockBookingRepo}
+	usecase := &BookingUseCase{Repo: repo}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 5).Return(entity.Booking{ID: 5, UserID: 1, Status: "cancelled"}, nil)
+
+	_, err := usecase.CancelBooking(context.Background(), 1, 5, dto.CancelBookingRequest{})
+
+	assert.ErrorIs(t, err, ErrBookingNotCancellable)
+}
+
+func TestBookingUseCase_CancelBooking_CutoffPassed(t *testing.T) {
+	mockBookingRepo := new(MockBookingRepo)
+	mockSeatRepo := new(MockSeatRepoForBooking)
+	repo := &repository.Repository{
+		Booking: mockBookingRepo,
+		Seat:    mockSeatRepo,
+	}
+	usecase := &BookingUseCase{Repo: repo, CancelCutoff: time.Hour}
+
+	booking := entity.Booking{ID: 5, UserID: 1, ShowtimeID: 1, Status: "paid"}
+	showtime := newCancelTestShowtime(time.Now().Add(30 * time.Minute))
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 5).Return(booking, nil)
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+
+	_, err := usecase.CancelBooking(context.Background(), 1, 5, dto.CancelBookingRequest{})
+
+	assert.ErrorIs(t, err, ErrCancellationClosed)
+	mockBookingRepo.AssertNotCalled(t, "CancelBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
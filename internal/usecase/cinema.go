@@ -2,13 +2,25 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type CinemaUseCaseInterface interface {
 	GetAllCinemas(ctx context.Context, page, limit int) ([]dto.CinemaResponse, dto.Pagination, error)
 	GetCinemaByID(ctx context.Context, id int) (dto.CinemaResponse, error)
+	CreateCinema(ctx context.Context, req dto.CinemaRequest) (dto.CinemaResponse, error)
+	UpdateCinema(ctx context.Context, id int, req dto.CinemaRequest) (dto.CinemaResponse, error)
+	CreateStudio(ctx context.Context, cinemaID int, req dto.StudioRequest) (dto.StudioResponse, error)
+	UpdateStudio(ctx context.Context, id int, req dto.StudioRequest) (dto.StudioResponse, error)
+	GenerateSeatLayout(ctx context.Context, studioID int, req dto.SeatLayoutRequest) (dto.SeatLayoutResponse, error)
 }
 
 type CinemaUseCase struct {
@@ -93,3 +105,170 @@ func (u *CinemaUseCase) GetCinemaByID(ctx context.Context, id int) (dto.CinemaRe
 		CreatedAt: cinema.CreatedAt,
 	}, nil
 }
+
+// CreateCinema adds a new cinema location
+func (u *CinemaUseCase) CreateCinema(ctx context.Context, req dto.CinemaRequest) (dto.CinemaResponse, error) {
+	cinema, err := u.Repo.Cinema.CreateCinema(ctx, entity.Cinema{Name: req.Name, Location: req.Location})
+	if err != nil {
+		return dto.CinemaResponse{}, err
+	}
+
+	return dto.CinemaResponse{
+		ID:        cinema.ID,
+		Name:      cinema.Name,
+		Location:  cinema.Location,
+		CreatedAt: cinema.CreatedAt,
+	}, nil
+}
+
+// UpdateCinema changes the name and location of a cinema
+func (u *CinemaUseCase) UpdateCinema(ctx context.Context, id int, req dto.CinemaRequest) (dto.CinemaResponse, error) {
+	cinema, err := u.Repo.Cinema.UpdateCinema(ctx, entity.Cinema{ID: id, Name: req.Name, Location: req.Location})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.CinemaResponse{}, ErrCinemaNotFound
+	}
+	if err != nil {
+		return dto.CinemaResponse{}, err
+	}
+
+	return dto.CinemaResponse{
+		ID:        cinema.ID,
+		Name:      cinema.Name,
+		Location:  cinema.Location,
+		CreatedAt: cinema.CreatedAt,
+	}, nil
+}
+
+// CreateStudio adds an empty studio to a cinema
+func (u *CinemaUseCase) CreateStudio(ctx context.Context, cinemaID int, req dto.StudioRequest) (dto.StudioResponse, error) {
+	if _, err := u.Repo.Cinema.GetCinemaByID(ctx, cinemaID); err != nil {
+		return dto.StudioResponse{}, ErrCinemaNotFound
+	}
+
+	studio, err := u.Repo.Cinema.CreateStudio(ctx, entity.Studio{CinemaID: cinemaID, Name: req.Name})
+	if err != nil {
+		return dto.StudioResponse{}, err
+	}
+
+	return dto.StudioResponse{
+		ID:         studio.ID,
+		Name:       studio.Name,
+		TotalSeats: studio.TotalSeats,
+	}, nil
+}
+
+// UpdateStudio renames a studio
+func (u *CinemaUseCase) UpdateStudio(ctx context.Context, id int, req dto.StudioRequest) (dto.StudioResponse, error) {
+	studio, err := u.Repo.Cinema.UpdateStudio(ctx, entity.Studio{ID: id, Name: req.Name})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.StudioResponse{}, ErrStudioNotFound
+	}
+	if err != nil {
+		return dto.StudioResponse{}, err
+	}
+
+	return dto.StudioResponse{
+		ID:         studio.ID,
+		Name:       studio.Name,
+		TotalSeats: studio.TotalSeats,
+	}, nil
+}
+
+// GenerateSeatLayout replaces a studio's seats with the ones described by the layout
+func (u *CinemaUseCase) GenerateSeatLayout(ctx context.Context, studioID int, req dto.SeatLayoutRequest) (dto.SeatLayoutResponse, error) {
+	seats, err := buildSeatLayout(req)
+	if err != nil {
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	if len(req.RowCategories) > 0 {
+		categories, err := u.Repo.Cinema.GetSeatCategories(ctx)
+		if err != nil {
+			return dto.SeatLayoutResponse{}, err
+		}
+		for _, name := range req.RowCategories {
+			if !slices.ContainsFunc(categories, func(c entity.SeatCategory) bool { return c.Name == strings.ToLower(name) }) {
+				return dto.SeatLayoutResponse{}, fmt.Errorf("unknown seat category: %s", name)
+			}
+		}
+	}
+
+	total, err := u.Repo.Cinema.ReplaceStudioSeats(ctx, studioID, seats)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.SeatLayoutResponse{}, ErrStudioNotFound
+	}
+	if err != nil {
+		return dto.SeatLayoutResponse{}, err
+	}
+
+	codes := make([]string, len(seats))
+	for i, seat := range seats {
+		codes[i] = seat.SeatCode
+	}
+
+	return dto.SeatLayoutResponse{
+		StudioID:   studioID,
+		TotalSeats: total,
+		SeatCodes:  codes,
+	}, nil
+}
+
+// buildSeatLayout expands a layout into seats. Rows are lettered from A and seats numbered
+// from 1; each aisle shifts the physical column of the seats after it by one.
+func buildSeatLayout(req dto.SeatLayoutRequest) ([]entity.Seat, error) {
+	for _, aisle := range req.Aisles {
+		if aisle < 1 || aisle >= req.SeatsPerRow {
+			return nil, fmt.Errorf("aisle after seat %d is outside the row", aisle)
+		}
+	}
+
+	rowCategories := make(map[string]string, len(req.RowCategories))
+	for label, category := range req.RowCategories {
+		label = strings.ToUpper(label)
+		if label < "A" || label >= string(rune('A'+req.Rows)) {
+			return nil, fmt.Errorf("row %s is not in layout", label)
+		}
+		rowCategories[label] = strings.ToLower(category)
+	}
+
+	disabled := make(map[string]bool, len(req.Disabled))
+	for _, code := range req.Disabled {
+		disabled[strings.ToUpper(code)] = true
+	}
+
+	var seats []entity.Seat
+	for row := 0; row < req.Rows; row++ {
+		label := string(rune('A' + row))
+		category := rowCategories[label]
+		if category == "" {
+			category = entity.SeatCategoryRegular
+		}
+		column := 0
+		for number := 1; number <= req.SeatsPerRow; number++ {
+			column++
+			code := fmt.Sprintf("%s%d", label, number)
+			if disabled[code] {
+				delete(disabled, code)
+			} else {
+				seats = append(seats, entity.Seat{SeatCode: code, RowLabel: label, ColumnNumber: column, Category: category})
+			}
+			if slices.Contains(req.Aisles, number) {
+				column++
+			}
+		}
+	}
+
+	if len(disabled) > 0 {
+		unknown := make([]string, 0, len(disabled))
+		for code := range disabled {
+			unknown = append(unknown, code)
+		}
+		slices.Sort(unknown)
+		return nil, fmt.Errorf("disabled seats not in layout: %s", strings.Join(unknown, ", "))
+	}
+	if len(seats) == 0 {
+		return nil, errors.New("layout has no seats")
+	}
+
+	return seats, nil
+}
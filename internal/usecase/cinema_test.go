@@ -5,9 +5,11 @@ import (
 	"errors"
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -40,6 +42,41 @@ func (m *MockCinemaRepo) CountCinemas(ctx context.Context) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockCinemaRepo) CreateCinema(ctx context.Context, cinema entity.Cinema) (entity.Cinema, error) {
+	args := m.Called(ctx, cinema)
+	return args.Get(0).(entity.Cinema), args.Error(1)
+}
+
+func (m *MockCinemaRepo) UpdateCinema(ctx context.Context, cinema entity.Cinema) (entity.Cinema, error) {
+	args := m.Called(ctx, cinema)
+	return args.Get(0).(entity.Cinema), args.Error(1)
+}
+
+func (m *MockCinemaRepo) GetStudioByID(ctx context.Context, id int) (entity.Studio, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Studio), args.Error(1)
+}
+
+func (m *MockCinemaRepo) CreateStudio(ctx context.Context, studio entity.Studio) (entity.Studio, error) {
+	args := m.Called(ctx, studio)
+	return args.Get(0).(entity.Studio), args.Error(1)
+}
+
+func (m *MockCinemaRepo) UpdateStudio(ctx context.Context, studio entity.Studio) (entity.Studio, error) {
+	args := m.Called(ctx, studio)
+	return args.Get(0).(entity.Studio), args.Error(1)
+}
+
+func (m *MockCinemaRepo) GetSeatCategories(ctx context.Context) ([]entity.SeatCategory, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.SeatCategory), args.Error(1)
+}
+
+func (m *MockCinemaRepo) ReplaceStudioSeats(ctx context.Context, studioID int, seats []entity.Seat) (int, error) {
+	args := m.Called(ctx, studioID, seats)
+	return args.Int(0), args.Error(1)
+}
+
 // =====================
 // Cinema UseCase Tests
 // =====================
@@ -190,3 +227,142 @@ func TestCinemaUseCase_GetCinemaByID_StudiosError(t *testing.T) {
 	assert.Equal(t, 0, result.ID)
 	mockCinemaRepo.AssertExpectations(t)
 }
+
+func TestCinemaUseCase_UpdateCinema_NotFound(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo}
+	usecase := &CinemaUseCase{Repo: repo}
+
+	mockCinemaRepo.On("UpdateCinema", mock.Anything, entity.Cinema{ID: 999, Name: "X", Location: "Y"}).Return(entity.Cinema{}, pgx.ErrNoRows)
+
+	_, err := usecase.UpdateCinema(context.Background(), 999, dto.CinemaRequest{Name: "X", Location: "Y"})
+
+	assert.ErrorIs(t, err, ErrCinemaNotFound)
+	mockCinemaRepo.AssertExpectations(t)
+}
+
+func TestCinemaUseCase_CreateStudio_CinemaNotFound(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo}
+	usecase := &CinemaUseCase{Repo: repo}
+
+	mockCinemaRepo.On("GetCinemaByID", mock.Anything, 999).Return(entity.Cinema{}, pgx.ErrNoRows)
+
+	_, err := usecase.CreateStudio(context.Background(), 999, dto.StudioRequest{Name: "Studio 9"})
+
+	assert.ErrorIs(t, err, ErrCinemaNotFound)
+	mockCinemaRepo.AssertNotCalled(t, "CreateStudio", mock.Anything, mock.Anything)
+}
+
+func TestCinemaUseCase_CreateStudio_Success(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo}
+	usecase := &CinemaUseCase{Repo: repo}
+
+	mockCinemaRepo.On("GetCinemaByID", mock.Anything, 1).Return(entity.Cinema{ID: 1}, nil)
+	mockCinemaRepo.On("CreateStudio", mock.Anything, entity.Studio{CinemaID: 1, Name: "Studio 2"}).
+		Return(entity.Studio{ID: 2, CinemaID: 1, Name: "Studio 2"}, nil)
+
+	result, err := usecase.CreateStudio(context.Background(), 1, dto.StudioRequest{Name: "Studio 2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.ID)
+	assert.Equal(t, 0, result.TotalSeats)
+	mockCinemaRepo.AssertExpectations(t)
+}
+
+func TestBuildSeatLayout(t *testing.T) {
+	t.Run("Aisles Shift Columns And Disabled Seats Are Skipped", func(t *testing.T) {
+		seats, err := buildSeatLayout(dto.SeatLayoutRequest{
+			Rows:        2,
+			SeatsPerRow: 4,
+			Aisles:      []int{2},
+			Disabled:    []string{"b4"},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, seats, 7)
+		assert.Equal(t, entity.Seat{SeatCode: "A1", RowLabel: "A", ColumnNumber: 1, Category: "regular"}, seats[0])
+		assert.Equal(t, entity.Seat{SeatCode: "A2", RowLabel: "A", ColumnNumber: 2, Category: "regular"}, seats[1])
+		assert.Equal(t, entity.Seat{SeatCode: "A3", RowLabel: "A", ColumnNumber: 4, Category: "regular"}, seats[2])
+		assert.Equal(t, entity.Seat{SeatCode: "B3", RowLabel: "B", ColumnNumber: 4, Category: "regular"}, seats[6])
+	})
+
+	t.Run("Row Categories", func(t *testing.T) {
+		seats, err := buildSeatLayout(dto.SeatLayoutRequest{
+			Rows:          3,
+			SeatsPerRow:   2,
+			RowCategories: map[string]string{"c": "Couple", "B": "vip"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "regular", seats[0].Category)
+		assert.Equal(t, "vip", seats[2].Category)
+		assert.Equal(t, "couple", seats[5].Category)
+	})
+
+	t.Run("Category For Row Outside Layout", func(t *testing.T) {
+		_, err := buildSeatLayout(dto.SeatLayoutRequest{Rows: 2, SeatsPerRow: 2, RowCategories: map[string]string{"C": "vip"}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "row C")
+	})
+
+	t.Run("Aisle Outside Row", func(t *testing.T) {
+		_, err := buildSeatLayout(dto.SeatLayoutRequest{Rows: 1, SeatsPerRow: 4, Aisles: []int{4}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown Disabled Seat", func(t *testing.T) {
+		_, err := buildSeatLayout(dto.SeatLayoutRequest{Rows: 1, SeatsPerRow: 4, Disabled: []string{"C1"}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "C1")
+	})
+
+	t.Run("Every Seat Disabled", func(t *testing.T) {
+		_, err := buildSeatLayout(dto.SeatLayoutRequest{Rows: 1, SeatsPerRow: 1, Disabled: []string{"A1"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestCinemaUseCase_GenerateSeatLayout(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo}
+	usecase := &CinemaUseCase{Repo: repo}
+
+	req := dto.SeatLayoutRequest{Rows: 1, SeatsPerRow: 3}
+	mockCinemaRepo.On("ReplaceStudioSeats", mock.Anything, 1, mock.AnythingOfType("[]entity.Seat")).Return(3, nil)
+	mockCinemaRepo.On("ReplaceStudioSeats", mock.Anything, 2, mock.Anything).Return(0, repository.ErrSeatsInUse)
+	mockCinemaRepo.On("ReplaceStudioSeats", mock.Anything, 999, mock.Anything).Return(0, pgx.ErrNoRows)
+
+	result, err := usecase.GenerateSeatLayout(context.Background(), 1, req)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.TotalSeats)
+	assert.Equal(t, []string{"A1", "A2", "A3"}, result.SeatCodes)
+
+	_, err = usecase.GenerateSeatLayout(context.Background(), 2, req)
+	assert.ErrorIs(t, err, ErrSeatsInUse)
+
+	_, err = usecase.GenerateSeatLayout(context.Background(), 999, req)
+	assert.ErrorIs(t, err, ErrStudioNotFound)
+}
+
+func TestCinemaUseCase_GenerateSeatLayout_UnknownCategory(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo}
+	usecase := &CinemaUseCase{Repo: repo}
+
+	mockCinemaRepo.On("GetSeatCategories", mock.Anything).Return([]entity.SeatCategory{
+		{ID: 1, Name: "regular", PriceMultiplier: 1},
+		{ID: 2, Name: "vip", PriceMultiplier: 1.5},
+	}, nil)
+
+	_, err := usecase.GenerateSeatLayout(context.Background(), 1, dto.SeatLayoutRequest{
+		Rows:          2,
+		SeatsPerRow:   2,
+		RowCategories: map[string]string{"B": "lounge"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "lounge")
+	mockCinemaRepo.AssertNotCalled(t, "ReplaceStudioSeats", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ConcessionUseCaseInterface interface {
+	GetConcessions(ctx context.Context, cinemaID int, includeInactive bool) ([]dto.ConcessionResponse, error)
+	CreateConcession(ctx context.Context, cinemaID int, req dto.ConcessionRequest) (dto.ConcessionResponse, error)
+	UpdateConcession(ctx context.Context, id int, req dto.ConcessionRequest) (dto.ConcessionResponse, error)
+}
+
+type ConcessionUseCase struct {
+	Repo *repository.Repository
+}
+
+func NewConcessionUseCase(repo *repository.Repository) ConcessionUseCaseInterface {
+	return &ConcessionUseCase{Repo: repo}
+}
+
+// GetConcessions lists a cinema's concessions; customers only see the ones on sale
+func (u *ConcessionUseCase) GetConcessions(ctx context.Context, cinemaID int, includeInactive bool) ([]dto.ConcessionResponse, error) {
+	if _, err := u.Repo.Cinema.GetCinemaByID(ctx, cinemaID); err != nil {
+		return nil, ErrCinemaNotFound
+	}
+
+	concessions, err := u.Repo.Concession.GetConcessionsByCinema(ctx, cinemaID, !includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.ConcessionResponse{}
+	for _, c := range concessions {
+		response = append(response, toConcessionResponse(c))
+	}
+	return response, nil
+}
+
+// CreateConcession adds a food or beverage item to a cinema's catalogue
+func (u *ConcessionUseCase) CreateConcession(ctx context.Context, cinemaID int, req dto.ConcessionRequest) (dto.ConcessionResponse, error) {
+	if _, err := u.Repo.Cinema.GetCinemaByID(ctx, cinemaID); err != nil {
+		return dto.ConcessionResponse{}, ErrCinemaNotFound
+	}
+
+	concession := concessionFromRequest(req)
+	concession.CinemaID = cinemaID
+
+	created, err := u.Repo.Concession.CreateConcession(ctx, concession)
+	if err != nil {
+		return dto.ConcessionResponse{}, err
+	}
+
+	return toConcessionResponse(created), nil
+}
+
+// UpdateConcession replaces a concession's details and restocks it; bookings that
+// already ordered it keep their price
+func (u *ConcessionUseCase) UpdateConcession(ctx context.Context, id int, req dto.ConcessionRequest) (dto.ConcessionResponse, error) {
+	concession := concessionFromRequest(req)
+	concession.ID = id
+
+	updated, err := u.Repo.Concession.UpdateConcession(ctx, concession)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.ConcessionResponse{}, ErrConcessionNotFound
+	}
+	if err != nil {
+		return dto.ConcessionResponse{}, err
+	}
+
+	return toConcessionResponse(updated), nil
+}
+
+// concessionFromRequest maps a create or update request onto a concession.
+// Concessions are on sale unless the request says otherwise.
+func concessionFromRequest(req dto.ConcessionRequest) entity.Concession {
+	return entity.Concession{
+		Name:        strings.TrimSpace(req.Name),
+		Category:    req.Category,
+		Description: strings.TrimSpace(req.Description),
+		Price:       req.Price,
+		Stock:       req.Stock,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+}
+
+func toConcessionResponse(c entity.Concession) dto.ConcessionResponse {
+	return dto.ConcessionResponse{
+		ID:          c.ID,
+		CinemaID:    c.CinemaID,
+		Name:        c.Name,
+		Category:    c.Category,
+		Description: c.Description,
+		Price:       c.Price,
+		Stock:       c.Stock,
+		IsActive:    c.IsActive,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Concession
+// =====================
+
+type MockConcessionRepo struct {
+	mock.Mock
+}
+
+func (m *MockConcessionRepo) GetConcessionsByCinema(ctx context.Context, cinemaID int, activeOnly bool) ([]entity.Concession, error) {
+	args := m.Called(ctx, cinemaID, activeOnly)
+	return args.Get(0).([]entity.Concession), args.Error(1)
+}
+
+func (m *MockConcessionRepo) GetConcessionByID(ctx context.Context, id int) (entity.Concession, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Concession), args.Error(1)
+}
+
+func (m *MockConcessionRepo) CreateConcession(ctx context.Context, concession entity.Concession) (entity.Concession, error) {
+	args := m.Called(ctx, concession)
+	return args.Get(0).(entity.Concession), args.Error(1)
+}
+
+func (m *MockConcessionRepo) UpdateConcession(ctx context.Context, concession entity.Concession) (entity.Concession, error) {
+	args := m.Called(ctx, concession)
+	return args.Get(0).(entity.Concession), args.Error(1)
+}
+
+// =====================
+// Concession UseCase Tests
+// =====================
+
+func TestConcessionUseCase_GetConcessions(t *testing.T) {
+	t.Run("Success - On Sale Only", func(t *testing.T) {
+		mockCinemaRepo := new(MockCinemaRepo)
+		mockConcessionRepo := new(MockConcessionRepo)
+		usecase := &ConcessionUseCase{Repo: &repository.Repository{Cinema: mockCinemaRepo, Concession: mockConcessionRepo}}
+
+		mockCinemaRepo.On("GetCinemaByID", mock.Anything, 1).Return(entity.Cinema{ID: 1}, nil)
+		mockConcessionRepo.On("GetConcessionsByCinema", mock.Anything, 1, true).Return([]entity.Concession{
+			{ID: 2, CinemaID: 1, Name: "Popcorn Combo", Category: entity.ConcessionCombo, Price: 65000, Stock: 40, IsActive: true},
+		}, nil)
+
+		result, err := usecase.GetConcessions(context.Background(), 1, false)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "Popcorn Combo", result[0].Name)
+		mockConcessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Cinema Not Found", func(t *testing.T) {
+		mockCinemaRepo := new(MockCinemaRepo)
+		mockConcessionRepo := new(MockConcessionRepo)
+		usecase := &ConcessionUseCase{Repo: &repository.Repository{Cinema: mockCinemaRepo, Concession: mockConcessionRepo}}
+
+		mockCinemaRepo.On("GetCinemaByID", mock.Anything, 999).Return(entity.Cinema{}, pgx.ErrNoRows)
+
+		_, err := usecase.GetConcessions(context.Background(), 999, true)
+		assert.ErrorIs(t, err, ErrCinemaNotFound)
+		mockConcessionRepo.AssertNotCalled(t, "GetConcessionsByCinema", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConcessionUseCase_CreateConcession(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	mockConcessionRepo := new(MockConcessionRepo)
+	usecase := &ConcessionUseCase{Repo: &repository.Repository{Cinema: mockCinemaRepo, Concession: mockConcessionRepo}}
+
+	mockCinemaRepo.On("GetCinemaByID", mock.Anything, 1).Return(entity.Cinema{ID: 1}, nil)
+	mockConcessionRepo.On("CreateConcession", mock.Anything, entity.Concession{
+		CinemaID: 1, Name: "Mineral Water", Category: entity.ConcessionDrink, Price: 10000, Stock: 100, IsActive: true,
+	}).Return(entity.Concession{
+		ID: 5, CinemaID: 1, Name: "Mineral Water", Category: entity.ConcessionDrink, Price: 10000, Stock: 100, IsActive: true,
+	}, nil)
+
+	result, err := usecase.CreateConcession(context.Background(), 1, dto.ConcessionRequest{
+		Name: " Mineral Water ", Category: "drink", Price: 10000, Stock: 100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.ID)
+	assert.True(t, result.IsActive)
+	mockConcessionRepo.AssertExpectations(t)
+}
+
+func TestConcessionUseCase_UpdateConcession_NotFound(t *testing.T) {
+	mockConcessionRepo := new(MockConcessionRepo)
+	usecase := &ConcessionUseCase{Repo: &repository.Repository{Concession: mockConcessionRepo}}
+
+	inactive := false
+	mockConcessionRepo.On("UpdateConcession", mock.Anything, entity.Concession{
+		ID: 99, Name: "Nachos", Category: entity.ConcessionSnack, Price: 30000,
+	}).Return(entity.Concession{}, pgx.ErrNoRows)
+
+	_, err := usecase.UpdateConcession(context.Background(), 99, dto.ConcessionRequest{
+		Name: "Nachos", Category: "snack", Price: 30000, IsActive: &inactive,
+	})
+	assert.ErrorIs(t, err, ErrConcessionNotFound)
+}
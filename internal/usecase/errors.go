@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"errors"
+	"project-app-bioskop/internal/data/repository"
+)
+
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrSeatTaken is returned when a requested seat is already booked for the showtime
+	ErrSeatTaken = repository.ErrSeatTaken
+
+	// ErrBookingNotFound is returned when a booking does not exist
+	ErrBookingNotFound = errors.New("booking not found")
+
+	// ErrBookingNotOwned is returned when a user acts on another user's booking
+	ErrBookingNotOwned = errors.New("booking does not belong to user")
+
+	// ErrBookingNotCancellable is returned when a booking's status does not allow cancellation
+	ErrBookingNotCancellable = errors.New("booking cannot be cancelled")
+
+	// ErrCancellationClosed is returned when the cancellation cutoff before the showtime has passed
+	ErrCancellationClosed = errors.New("cancellation window for this showtime has closed")
+
+	// ErrInvalidWebhookSignature is returned when a callback is unsigned, wrongly signed or
+	// targets a payment method without a webhook secret
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+	// ErrPaymentNotFound is returned when a callback references an unknown transaction
+	ErrPaymentNotFound = repository.ErrPaymentNotFound
+
+	// ErrPaymentInProgress is returned when a booking already has a payment awaiting confirmation
+	ErrPaymentInProgress = repository.ErrPaymentInProgress
+
+	// ErrWebhookDuplicate is returned when a callback for the transaction was already applied
+	ErrWebhookDuplicate = repository.ErrWebhookDuplicate
+
+	// ErrMovieNotFound is returned when a movie does not exist or was deleted
+	ErrMovieNotFound = errors.New("movie not found")
+
+	// ErrMovieHasShowtimes is returned when deleting a movie that still has upcoming showtimes
+	ErrMovieHasShowtimes = repository.ErrMovieHasShowtimes
+
+	// ErrCinemaNotFound is returned when a cinema does not exist
+	ErrCinemaNotFound = errors.New("cinema not found")
+
+	// ErrStudioNotFound is returned when a studio does not exist
+	ErrStudioNotFound = errors.New("studio not found")
+
+	// ErrSeatsInUse is returned when a layout change would remove seats that bookings reference
+	ErrSeatsInUse = repository.ErrSeatsInUse
+
+	// ErrShowtimeNotFound is returned when a showtime does not exist or is no longer scheduled
+	ErrShowtimeNotFound = errors.New("showtime not found")
+
+	// ErrShowtimeCancelled is returned when booking a showtime that was cancelled
+	ErrShowtimeCancelled = errors.New("showtime has been cancelled")
+
+	// ErrShowtimeConflict is returned when a showtime overlaps another one in the same studio
+	ErrShowtimeConflict = repository.ErrShowtimeConflict
+
+	// ErrShowtimeInPast is returned when scheduling a showtime that would already have started
+	ErrShowtimeInPast = errors.New("showtime must start in the future")
+
+	// ErrInvalidShowDate is returned when a showtime date is not YYYY-MM-DD
+	ErrInvalidShowDate = errors.New("date must be in YYYY-MM-DD format")
+
+	// ErrInvalidShowTime is returned when a showtime time is not HH:MM or HH:MM:SS
+	ErrInvalidShowTime = errors.New("time must be in HH:MM or HH:MM:SS format")
+
+	// ErrInvalidDateRange is returned when a date range ends before it starts
+	ErrInvalidDateRange = errors.New("date_to must not be before date_from")
+
+	// ErrAmbiguousShowtime is returned when a cinema, date and time match showtimes in several studios
+	ErrAmbiguousShowtime = errors.New("several showtimes match, look up seats by showtime id")
+
+	// ErrNotEnoughSeats is returned when a showtime has fewer available seats than requested
+	ErrNotEnoughSeats = errors.New("not enough seats available")
+
+	// ErrDuplicateSeat is returned when a booking lists the same seat more than once
+	ErrDuplicateSeat = repository.ErrDuplicateSeat
+
+	// ErrReviewNotFound is returned when a review does not exist
+	ErrReviewNotFound = errors.New("review not found")
+
+	// ErrReviewNotOwned is returned when a user edits or deletes another user's review
+	ErrReviewNotOwned = errors.New("review does not belong to user")
+
+	// ErrReviewNotAllowed is returned when a user reviews a movie they have no paid booking for a past showtime of
+	ErrReviewNotAllowed = errors.New("only viewers with a paid booking for a past showtime can review this movie")
+
+	// ErrReviewExists is returned when a user reviews a movie a second time
+	ErrReviewExists = repository.ErrReviewExists
+
+	// ErrAgeRestricted is returned when a customer is younger than a movie's age classification allows
+	ErrAgeRestricted = errors.New("customer is below the minimum age for this movie's classification")
+
+	// ErrBirthDateRequired is returned when booking an age-restricted movie without a date of birth on the profile
+	ErrBirthDateRequired = errors.New("date of birth is required to book this age-restricted movie")
+
+	// ErrInvalidBirthDate is returned when a date of birth is not a past YYYY-MM-DD date
+	ErrInvalidBirthDate = errors.New("date of birth must be a past date in YYYY-MM-DD format")
+
+	// ErrPromoNotFound is returned when a promo code or promotion does not exist
+	ErrPromoNotFound = errors.New("promo code not found")
+
+	// ErrPromoNotApplicable is returned, wrapped with the reason, when a booking does not meet a promotion's conditions
+	ErrPromoNotApplicable = errors.New("promo code does not apply to this booking")
+
+	// ErrPromoUnavailable is returned when a promotion is inactive or outside its validity window
+	ErrPromoUnavailable = repository.ErrPromoUnavailable
+
+	// ErrPromoExhausted is returned when a promotion has reached its usage limit
+	ErrPromoExhausted = repository.ErrPromoExhausted
+
+	// ErrPromoUserLimit is returned when a user has reached a promotion's per-user limit
+	ErrPromoUserLimit = repository.ErrPromoUserLimit
+
+	// ErrPromoCodeExists is returned when a promotion's code is already taken
+	ErrPromoCodeExists = repository.ErrPromoCodeExists
+
+	// ErrInvalidPromotion is returned when a promotion's validity window or percentage is out of range
+	ErrInvalidPromotion = errors.New("valid_until must be after valid_from and a percentage may not exceed 100")
+
+	// ErrPricingRuleNotFound is returned when a pricing rule does not exist
+	ErrPricingRuleNotFound = errors.New("pricing rule not found")
+
+	// ErrInvalidPricingRule is returned when a pricing rule's time window or percentage is out of range
+	ErrInvalidPricingRule = errors.New("time_until must be after time_from and a percentage adjustment must be above -100")
+
+	// ErrConcessionNotFound is returned when a concession does not exist
+	ErrConcessionNotFound = errors.New("concession not found")
+
+	// ErrConcessionUnavailable is returned when an ordered concession is not on sale at the cinema or is out of stock
+	ErrConcessionUnavailable = repository.ErrConcessionUnavailable
+
+	// ErrInsufficientPoints is returned when a booking redeems more loyalty points than the user has
+	ErrInsufficientPoints = repository.ErrInsufficientPoints
+
+	// ErrTicketsDisabled is returned by the e-ticket endpoints while no signing secret is configured
+	ErrTicketsDisabled = errors.New("e-tickets are not available")
+
+	// ErrTicketsNotIssued is returned when asking for the tickets of a booking that is not paid
+	ErrTicketsNotIssued = errors.New("tickets are only issued for paid bookings")
+
+	// ErrTicketNotFound is returned when a ticket does not exist or belongs to another user
+	ErrTicketNotFound = errors.New("ticket not found")
+
+	// ErrInvalidTicket is returned when a scanned ticket token is malformed or its signature does not match
+	ErrInvalidTicket = errors.New("invalid ticket")
+
+	// ErrTicketVoid is returned when a scanned ticket's booking was cancelled or refunded, or its showtime was cancelled
+	ErrTicketVoid = errors.New("ticket is no longer valid")
+
+	// ErrTicketOutsideEntryWindow is returned when a ticket is scanned before entry opens or after the showing ended
+	ErrTicketOutsideEntryWindow = errors.New("ticket is outside its entry window")
+
+	// ErrTicketAlreadyAdmitted is returned when a ticket is scanned again after admitting its holder
+	ErrTicketAlreadyAdmitted = repository.ErrTicketAlreadyAdmitted
+
+	// ErrIdempotencyKeyReused is returned when an Idempotency-Key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+
+	// ErrIdempotencyInProgress is returned when the original request for an Idempotency-Key has not finished
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still being processed")
+)
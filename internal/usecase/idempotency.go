@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/pkg/utils"
+	"time"
+)
+
+// defaultIdempotencyTTL is how long a key is honored when no TTL is configured
+const defaultIdempotencyTTL = 24 * time.Hour
+
+type IdempotencyUseCaseInterface interface {
+	Begin(ctx context.Context, userID int, key, requestHash string) (entity.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, id, statusCode int, body []byte) error
+	Release(ctx context.Context, id int) error
+}
+
+type IdempotencyUseCase struct {
+	Repo *repository.Repository
+	TTL  time.Duration
+}
+
+func NewIdempotencyUseCase(repo *repository.Repository, config utils.Configuration) IdempotencyUseCaseInterface {
+	return &IdempotencyUseCase{
+		Repo: repo,
+		TTL:  time.Duration(config.Idempotency.TTLHours) * time.Hour,
+	}
+}
+
+func (u *IdempotencyUseCase) ttl() time.Duration {
+	if u.TTL <= 0 {
+		return defaultIdempotencyTTL
+	}
+	return u.TTL
+}
+
+// Begin reserves the key for a new request, or returns the stored record with replay set
+// when the same request already completed under this key
+func (u *IdempotencyUseCase) Begin(ctx context.Context, userID int, key, requestHash string) (entity.IdempotencyKey, bool, error) {
+	record, reserved, err := u.Repo.Idempotency.ReserveIdempotencyKey(ctx, entity.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+	}, time.Now().Add(-u.ttl()))
+	if err != nil {
+		return entity.IdempotencyKey{}, false, err
+	}
+	if reserved {
+		return record, false, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return entity.IdempotencyKey{}, false, ErrIdempotencyKeyReused
+	}
+	if record.StatusCode == 0 {
+		return entity.IdempotencyKey{}, false, ErrIdempotencyInProgress
+	}
+	return record, true, nil
+}
+
+// Complete stores the response of a reserved key for later replays
+func (u *IdempotencyUseCase) Complete(ctx context.Context, id, statusCode int, body []byte) error {
+	return u.Repo.Idempotency.SaveIdempotencyResponse(ctx, id, statusCode, body)
+}
+
+// Release forgets a reserved key so that a failed request can be retried with it
+func (u *IdempotencyUseCase) Release(ctx context.Context, id int) error {
+	return u.Repo.Idempotency.DeleteIdempotencyKey(ctx, id)
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Idempotency
+// =====================
+
+type MockIdempotencyRepo struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, key entity.IdempotencyKey, expiredBefore time.Time) (entity.IdempotencyKey, bool, error) {
+	args := m.Called(ctx, key, expiredBefore)
+	return args.Get(0).(entity.IdempotencyKey), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIdempotencyRepo) SaveIdempotencyResponse(ctx context.Context, id, statusCode int, body []byte) error {
+	args := m.Called(ctx, id, statusCode, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepo) DeleteIdempotencyKey(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// =====================
+// Idempotency UseCase Tests
+// =====================
+
+func TestIdempotencyUseCase_Begin_NewKey(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	repo := &repository.Repository{Idempotency: mockRepo}
+	usecase := &IdempotencyUseCase{Repo: repo, TTL: time.Hour}
+
+	mockRepo.On("ReserveIdempotencyKey", mock.Anything, entity.IdempotencyKey{UserID: 1, Key: "key-1", RequestHash: "hash-1"}, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+	})).Return(entity.IdempotencyKey{ID: 5, UserID: 1, Key: "key-1", RequestHash: "hash-1"}, true, nil)
+
+	record, replay, err := usecase.Begin(context.Background(), 1, "key-1", "hash-1")
+
+	assert.NoError(t, err)
+	assert.False(t, replay)
+	assert.Equal(t, 5, record.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIdempotencyUseCase_Begin_ReplayCompleted(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	repo := &repository.Repository{Idempotency: mockRepo}
+	usecase := &IdempotencyUseCase{Repo: repo}
+
+	stored := entity.IdempotencyKey{ID: 5, UserID: 1, Key: "key-1", RequestHash: "hash-1", StatusCode: 201, ResponseBody: []byte(`{"status":true}`)}
+	mockRepo.On("ReserveIdempotencyKey", mock.Anything, mock.Anything, mock.Anything).Return(stored, false, nil)
+
+	record, replay, err := usecase.Begin(context.Background(), 1, "key-1", "hash-1")
+
+	assert.NoError(t, err)
+	assert.True(t, replay)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.Equal(t, `{"status":true}`, string(record.ResponseBody))
+}
+
+func TestIdempotencyUseCase_Begin_DifferentRequest(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	repo := &repository.Repository{Idempotency: mockRepo}
+	usecase := &IdempotencyUseCase{Repo: repo}
+
+	stored := entity.IdempotencyKey{ID: 5, UserID: 1, Key: "key-1", RequestHash: "hash-1", StatusCode: 201}
+	mockRepo.On("ReserveIdempotencyKey", mock.Anything, mock.Anything, mock.Anything).Return(stored, false, nil)
+
+	_, replay, err := usecase.Begin(context.Background(), 1, "key-1", "hash-2")
+
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+	assert.False(t, replay)
+}
+
+func TestIdempotencyUseCase_Begin_InProgress(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	repo := &repository.Repository{Idempotency: mockRepo}
+	usecase := &IdempotencyUseCase{Repo: repo}
+
+	stored := entity.IdempotencyKey{ID: 5, UserID: 1, Key: "key-1", RequestHash: "hash-1"}
+	mockRepo.On("ReserveIdempotencyKey", mock.Anything, mock.Anything, mock.Anything).Return(stored, false, nil)
+
+	_, _, err := usecase.Begin(context.Background(), 1, "key-1", "hash-1")
+
+	assert.ErrorIs(t, err, ErrIdempotencyInProgress)
+}
+
+func TestIdempotencyUseCase_Begin_RepoError(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	repo := &repository.Repository{Idempotency: mockRepo}
+	usecase := &IdempotencyUseCase{Repo: repo}
+
+	mockRepo.On("ReserveIdempotencyKey", mock.Anything, mock.Anything, mock.Anything).Return(entity.IdempotencyKey{}, false, errors.New("database error"))
+
+	_, _, err := usecase.Begin(context.Background(), 1, "key-1", "hash-1")
+
+	assert.Error(t, err)
+}
+
+func TestIdempotencyUseCase_CompleteAndRelease(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	repo := &repository.Repository{Idempotency: mockRepo}
+	usecase := &IdempotencyUseCase{Repo: repo}
+
+	body := []byte(`{"status":true}`)
+	mockRepo.On("SaveIdempotencyResponse", mock.Anything, 5, 201, body).Return(nil)
+	mockRepo.On("DeleteIdempotencyKey", mock.Anything, 6).Return(nil)
+
+	assert.NoError(t, usecase.Complete(context.Background(), 5, 201, body))
+	assert.NoError(t, usecase.Release(context.Background(), 6))
+	mockRepo.AssertExpectations(t)
+}
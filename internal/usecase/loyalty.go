@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/utils"
+)
+
+// Loyalty defaults used when the program is not configured: one point per Rp 1.000
+// paid, each point worth Rp 1 at checkout, expiring after a year
+const (
+	defaultAmountPerPoint = 1000
+	defaultPointValue     = 1
+	defaultPointExpiry    = 365
+)
+
+// loyaltySettings fills in the defaults for loyalty settings left unconfigured
+func loyaltySettings(config utils.LoyaltyConfig) utils.LoyaltyConfig {
+	if config.AmountPerPoint <= 0 {
+		config.AmountPerPoint = defaultAmountPerPoint
+	}
+	if config.PointValue <= 0 {
+		config.PointValue = defaultPointValue
+	}
+	if config.ExpiryDays <= 0 {
+		config.ExpiryDays = defaultPointExpiry
+	}
+	return config
+}
+
+// earnBookingPoints credits the points of a booking that was just paid. Points are a
+// bonus on top of the payment, so a failure is not reported to the payer.
+func earnBookingPoints(ctx context.Context, repo *repository.Repository, config utils.LoyaltyConfig, bookingID int) int {
+	settings := loyaltySettings(config)
+	points, err := repo.Loyalty.EarnPoints(ctx, bookingID, settings.AmountPerPoint, settings.ExpiryDays)
+	if err != nil {
+		return 0
+	}
+	return points
+}
+
+type LoyaltyUseCaseInterface interface {
+	GetBalance(ctx context.Context, userID int) (dto.LoyaltyBalanceResponse, error)
+	GetHistory(ctx context.Context, userID, page, limit int) ([]dto.LoyaltyEntryResponse, dto.Pagination, error)
+}
+
+type LoyaltyUseCase struct {
+	Repo    *repository.Repository
+	Loyalty utils.LoyaltyConfig
+}
+
+func NewLoyaltyUseCase(repo *repository.Repository, config utils.Configuration) LoyaltyUseCaseInterface {
+	return &LoyaltyUseCase{
+		Repo:    repo,
+		Loyalty: config.Loyalty,
+	}
+}
+
+// GetBalance returns the user's spendable points and what they take off a booking
+func (u *LoyaltyUseCase) GetBalance(ctx context.Context, userID int) (dto.LoyaltyBalanceResponse, error) {
+	balance, err := u.Repo.Loyalty.GetPointsBalance(ctx, userID)
+	if err != nil {
+		return dto.LoyaltyBalanceResponse{}, err
+	}
+
+	pointValue := loyaltySettings(u.Loyalty).PointValue
+	return dto.LoyaltyBalanceResponse{
+		Balance:    balance,
+		PointValue: pointValue,
+		Worth:      math.Round(float64(balance)*pointValue*100) / 100,
+	}, nil
+}
+
+// GetHistory lists the user's loyalty ledger, newest first
+func (u *LoyaltyUseCase) GetHistory(ctx context.Context, userID, page, limit int) ([]dto.LoyaltyEntryResponse, dto.Pagination, error) {
+	entries, pagination, err := u.Repo.Loyalty.GetLedger(ctx, userID, page, limit)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	response := make([]dto.LoyaltyEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, toLoyaltyEntryResponse(e))
+	}
+
+	return response, pagination, nil
+}
+
+func toLoyaltyEntryResponse(e entity.LoyaltyEntry) dto.LoyaltyEntryResponse {
+	return dto.LoyaltyEntryResponse{
+		ID:        e.ID,
+		BookingID: e.BookingID,
+		EntryType: e.EntryType,
+		Points:    e.Points,
+		Remaining: e.Remaining,
+		ExpiresAt: e.ExpiresAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Loyalty
+// =====================
+
+type MockLoyaltyRepo struct {
+	mock.Mock
+}
+
+func (m *MockLoyaltyRepo) GetPointsBalance(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockLoyaltyRepo) GetLedger(ctx context.Context, userID, page, limit int) ([]entity.LoyaltyEntry, dto.Pagination, error) {
+	args := m.Called(ctx, userID, page, limit)
+	return args.Get(0).([]entity.LoyaltyEntry), args.Get(1).(dto.Pagination), args.Error(2)
+}
+
+func (m *MockLoyaltyRepo) EarnPoints(ctx context.Context, bookingID int, amountPerPoint float64, expiryDays int) (int, error) {
+	args := m.Called(ctx, bookingID, amountPerPoint, expiryDays)
+	return args.Int(0), args.Error(1)
+}
+
+// =====================
+// Loyalty UseCase Tests
+// =====================
+
+func TestLoyaltyUseCase_GetBalance(t *testing.T) {
+	t.Run("Success - Configured Point Value", func(t *testing.T) {
+		mockLoyaltyRepo := new(MockLoyaltyRepo)
+		usecase := &LoyaltyUseCase{
+			Repo:    &repository.Repository{Loyalty: mockLoyaltyRepo},
+			Loyalty: utils.LoyaltyConfig{PointValue: 2.5},
+		}
+		mockLoyaltyRepo.On("GetPointsBalance", mock.Anything, 1).Return(120, nil)
+
+		result, err := usecase.GetBalance(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 120, result.Balance)
+		assert.Equal(t, 2.5, result.PointValue)
+		assert.Equal(t, 300.0, result.Worth)
+		mockLoyaltyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Database Error", func(t *testing.T) {
+		mockLoyaltyRepo := new(MockLoyaltyRepo)
+		usecase := &LoyaltyUseCase{Repo: &repository.Repository{Loyalty: mockLoyaltyRepo}}
+		mockLoyaltyRepo.On("GetPointsBalance", mock.Anything, 1).Return(0, errors.New("database error"))
+
+		_, err := usecase.GetBalance(context.Background(), 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoyaltyUseCase_GetHistory(t *testing.T) {
+	mockLoyaltyRepo := new(MockLoyaltyRepo)
+	usecase := &LoyaltyUseCase{Repo: &repository.Repository{Loyalty: mockLoyaltyRepo}}
+
+	now := time.Now()
+	bookingID := 4
+	entries := []entity.LoyaltyEntry{
+		{ID: 2, UserID: 1, BookingID: &bookingID, EntryType: entity.LoyaltyEarn, Points: 100, Remaining: 100, ExpiresAt: &now, CreatedAt: now},
+	}
+	pagination := dto.Pagination{CurrentPage: 1, TotalPages: 1, TotalRecords: 1, Limit: 10}
+	mockLoyaltyRepo.On("GetLedger", mock.Anything, 1, 1, 10).Return(entries, pagination, nil)
+
+	result, resultPagination, err := usecase.GetHistory(context.Background(), 1, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, entity.LoyaltyEarn, result[0].EntryType)
+	assert.Equal(t, &bookingID, result[0].BookingID)
+	assert.Equal(t, 1, resultPagination.TotalRecords)
+	mockLoyaltyRepo.AssertExpectations(t)
+}
+
+func TestLoyaltySettings(t *testing.T) {
+	t.Run("Defaults When Unconfigured", func(t *testing.T) {
+		settings := loyaltySettings(utils.LoyaltyConfig{})
+		assert.Equal(t, float64(defaultAmountPerPoint), settings.AmountPerPoint)
+		assert.Equal(t, float64(defaultPointValue), settings.PointValue)
+		assert.Equal(t, defaultPointExpiry, settings.ExpiryDays)
+	})
+
+	t.Run("Keeps Configured Values", func(t *testing.T) {
+		config := utils.LoyaltyConfig{AmountPerPoint: 500, PointValue: 2, ExpiryDays: 90}
+		assert.Equal(t, config, loyaltySettings(config))
+	})
+}
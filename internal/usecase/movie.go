@@ -1,14 +1,25 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type MovieUseCaseInterface interface {
-	GetAllMovies(ctx context.Context, page, limit int) ([]dto.MovieResponse, dto.Pagination, error)
+	GetAllMovies(ctx context.Context, req dto.MovieQueryRequest, page, limit int) ([]dto.MovieResponse, dto.Pagination, error)
 	GetMovieByID(ctx context.Context, id int) (dto.MovieResponse, error)
+	GetMovieSchedule(ctx context.Context, id int, req dto.MovieScheduleRequest) (dto.MovieScheduleResponse, error)
+	CreateMovie(ctx context.Context, req dto.MovieRequest) (dto.MovieResponse, error)
+	UpdateMovie(ctx context.Context, id int, req dto.MovieRequest) (dto.MovieResponse, error)
+	DeleteMovie(ctx context.Context, id int) error
 }
 
 type MovieUseCase struct {
@@ -19,9 +30,17 @@ func NewMovieUseCase(repo *repository.Repository) MovieUseCaseInterface {
 	return &MovieUseCase{Repo: repo}
 }
 
-// GetAllMovies retrieves all movies with pagination
-func (u *MovieUseCase) GetAllMovies(ctx context.Context, page, limit int) ([]dto.MovieResponse, dto.Pagination, error) {
-	movies, pagination, err := u.Repo.Movie.GetAllMovies(ctx, page, limit)
+// GetAllMovies retrieves movies matching the search and filters with pagination
+func (u *MovieUseCase) GetAllMovies(ctx context.Context, req dto.MovieQueryRequest, page, limit int) ([]dto.MovieResponse, dto.Pagination, error) {
+	filter := entity.MovieFilter{
+		Search:        strings.TrimSpace(req.Search),
+		Genres:        req.Genres,
+		ReleaseStatus: req.ReleaseStatus,
+		MinRating:     req.MinRating,
+		Sort:          req.Sort,
+	}
+
+	movies, pagination, err := u.Repo.Movie.GetAllMovies(ctx, filter, page, limit)
 	if err != nil {
 		return nil, dto.Pagination{}, err
 	}
@@ -34,26 +53,205 @@ func (u *MovieUseCase) GetAllMovies(ctx context.Context, page, limit int) ([]dto
 			PosterURL:       m.PosterURL,
 			Genres:          m.Genres,
 			Rating:          m.Rating,
+			ReviewCount:     m.ReviewCount,
 			DurationMinutes: m.DurationMinutes,
+			ReleaseDate:     m.ReleaseDate.Format("2006-01-02"),
+			ReleaseStatus:   m.ReleaseStatus,
+			AgeRating:       m.AgeRating,
 		})
 	}
 
 	return response, pagination, nil
 }
 
-// GetMovieByID retrieves a movie by ID
+// GetMovieByID retrieves the full detail of a movie including its cast and crew
 func (u *MovieUseCase) GetMovieByID(ctx context.Context, id int) (dto.MovieResponse, error) {
 	movie, err := u.Repo.Movie.GetMovieByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.MovieResponse{}, ErrMovieNotFound
+	}
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
-	return dto.MovieResponse{
-		ID:              movie.ID,
-		Title:           movie.Title,
-		PosterURL:       movie.PosterURL,
-		Genres:          movie.Genres,
-		Rating:          movie.Rating,
-		DurationMinutes: movie.DurationMinutes,
-	}, nil
+	movie.Cast, movie.Crew, err = u.Repo.Movie.GetMovieCredits(ctx, id)
+	if err != nil {
+		return dto.MovieResponse{}, err
+	}
+
+	return toMovieDetailResponse(movie), nil
+}
+
+// GetMovieSchedule lists where and when a movie plays, grouped by date and then cinema
+func (u *MovieUseCase) GetMovieSchedule(ctx context.Context, id int, req dto.MovieScheduleRequest) (dto.MovieScheduleResponse, error) {
+	if req.DateFrom != "" && req.DateTo != "" && req.DateTo < req.DateFrom {
+		return dto.MovieScheduleResponse{}, ErrInvalidDateRange
+	}
+
+	movie, err := u.Repo.Movie.GetMovieByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.MovieScheduleResponse{}, ErrMovieNotFound
+	}
+	if err != nil {
+		return dto.MovieScheduleResponse{}, err
+	}
+
+	listings, err := u.Repo.Showtime.GetMovieSchedule(ctx, id, entity.MovieScheduleFilter{
+		DateFrom: req.DateFrom,
+		DateTo:   req.DateTo,
+		Location: strings.TrimSpace(req.Location),
+	})
+	if err != nil {
+		return dto.MovieScheduleResponse{}, err
+	}
+
+	response := dto.MovieScheduleResponse{
+		Movie: toMovieDetailResponse(movie),
+		Dates: []dto.ScheduleDateResponse{},
+	}
+	// Listings arrive ordered by date and cinema, so each group is the last one appended
+	for _, l := range listings {
+		dates := response.Dates
+		if len(dates) == 0 || dates[len(dates)-1].Date != l.Showtime.ShowDate {
+			response.Dates = append(response.Dates, dto.ScheduleDateResponse{Date: l.Showtime.ShowDate})
+		}
+		date := &response.Dates[len(response.Dates)-1]
+
+		cinemas := date.Cinemas
+		if len(cinemas) == 0 || cinemas[len(cinemas)-1].ID != l.Cinema.ID {
+			date.Cinemas = append(date.Cinemas, dto.ScheduleCinemaResponse{
+				ID:       l.Cinema.ID,
+				Name:     l.Cinema.Name,
+				Location: l.Cinema.Location,
+			})
+		}
+		cinema := &date.Cinemas[len(date.Cinemas)-1]
+
+		showtime := dto.ScheduleShowtimeResponse{
+			ID:             l.Showtime.ID,
+			ShowTime:       l.Showtime.ShowTime,
+			Price:          l.Showtime.Price,
+			RemainingSeats: l.RemainingSeats,
+		}
+		if l.Showtime.Studio != nil {
+			showtime.Studio = dto.StudioResponse{
+				ID:         l.Showtime.Studio.ID,
+				Name:       l.Showtime.Studio.Name,
+				TotalSeats: l.Showtime.Studio.TotalSeats,
+			}
+		}
+		cinema.Showtimes = append(cinema.Showtimes, showtime)
+	}
+
+	return response, nil
+}
+
+// movieFromRequest maps a create or update request onto a movie entity
+func movieFromRequest(req dto.MovieRequest) (entity.Movie, error) {
+	releaseDate, err := time.Parse("2006-01-02", req.ReleaseDate)
+	if err != nil {
+		return entity.Movie{}, errors.New("invalid release date format, use YYYY-MM-DD")
+	}
+
+	movie := entity.Movie{
+		Title:           req.Title,
+		PosterURL:       req.PosterURL,
+		Genres:          req.Genres,
+		DurationMinutes: req.DurationMinutes,
+		ReleaseDate:     releaseDate,
+		ReleaseStatus:   req.ReleaseStatus,
+		Synopsis:        strings.TrimSpace(req.Synopsis),
+		AgeRating:       cmp.Or(req.AgeRating, entity.AgeRatingAll),
+		Language:        req.Language,
+		TrailerURL:      req.TrailerURL,
+	}
+	// The cast is billed in the order it is sent
+	for i, c := range req.Cast {
+		movie.Cast = append(movie.Cast, entity.MovieCastMember{
+			Name:          c.Name,
+			CharacterName: c.CharacterName,
+			BillingOrder:  i + 1,
+		})
+	}
+	for _, c := range req.Crew {
+		movie.Crew = append(movie.Crew, entity.MovieCrewMember{Name: c.Name, Job: c.Job})
+	}
+
+	return movie, nil
+}
+
+// toMovieDetailResponse maps a movie including its release information and any loaded credits
+func toMovieDetailResponse(m entity.Movie) dto.MovieResponse {
+	response := dto.MovieResponse{
+		ID:              m.ID,
+		Title:           m.Title,
+		PosterURL:       m.PosterURL,
+		Genres:          m.Genres,
+		Rating:          m.Rating,
+		ReviewCount:     m.ReviewCount,
+		DurationMinutes: m.DurationMinutes,
+		ReleaseDate:     m.ReleaseDate.Format("2006-01-02"),
+		ReleaseStatus:   m.ReleaseStatus,
+		AgeRating:       m.AgeRating,
+		Synopsis:        m.Synopsis,
+		Language:        m.Language,
+		TrailerURL:      m.TrailerURL,
+	}
+	for _, c := range m.Cast {
+		response.Cast = append(response.Cast, dto.MovieCastResponse{Name: c.Name, CharacterName: c.CharacterName})
+	}
+	// Several directors are joined in credit order
+	var directors []string
+	for _, c := range m.Crew {
+		response.Crew = append(response.Crew, dto.MovieCrewResponse{Name: c.Name, Job: c.Job})
+		if strings.EqualFold(c.Job, entity.CrewJobDirector) {
+			directors = append(directors, c.Name)
+		}
+	}
+	response.Director = strings.Join(directors, ", ")
+
+	return response
+}
+
+// CreateMovie adds a movie to the catalogue
+func (u *MovieUseCase) CreateMovie(ctx context.Context, req dto.MovieRequest) (dto.MovieResponse, error) {
+	movie, err := movieFromRequest(req)
+	if err != nil {
+		return dto.MovieResponse{}, err
+	}
+
+	created, err := u.Repo.Movie.CreateMovie(ctx, movie)
+	if err != nil {
+		return dto.MovieResponse{}, err
+	}
+
+	return toMovieDetailResponse(created), nil
+}
+
+// UpdateMovie replaces the details of an existing movie
+func (u *MovieUseCase) UpdateMovie(ctx context.Context, id int, req dto.MovieRequest) (dto.MovieResponse, error) {
+	movie, err := movieFromRequest(req)
+	if err != nil {
+		return dto.MovieResponse{}, err
+	}
+	movie.ID = id
+
+	updated, err := u.Repo.Movie.UpdateMovie(ctx, movie)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.MovieResponse{}, ErrMovieNotFound
+	}
+	if err != nil {
+		return dto.MovieResponse{}, err
+	}
+
+	return toMovieDetailResponse(updated), nil
+}
+
+// DeleteMovie soft deletes a movie without upcoming showtimes
+func (u *MovieUseCase) DeleteMovie(ctx context.Context, id int) error {
+	err := u.Repo.Movie.DeleteMovie(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrMovieNotFound
+	}
+	return err
 }
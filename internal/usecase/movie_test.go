@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -21,8 +22,8 @@ type MockMovieRepo struct {
 	mock.Mock
 }
 
-func (m *MockMovieRepo) GetAllMovies(ctx context.Context, page, limit int) ([]entity.Movie, dto.Pagination, error) {
-	args := m.Called(ctx, page, limit)
+func (m *MockMovieRepo) GetAllMovies(ctx context.Context, filter entity.MovieFilter, page, limit int) ([]entity.Movie, dto.Pagination, error) {
+	args := m.Called(ctx, filter, page, limit)
 	return args.Get(0).([]entity.Movie), args.Get(1).(dto.Pagination), args.Error(2)
 }
 
@@ -31,6 +32,26 @@ func (m *MockMovieRepo) GetMovieByID(ctx context.Context, id int) (entity.Movie,
 	return args.Get(0).(entity.Movie), args.Error(1)
 }
 
+func (m *MockMovieRepo) CreateMovie(ctx context.Context, movie entity.Movie) (entity.Movie, error) {
+	args := m.Called(ctx, movie)
+	return args.Get(0).(entity.Movie), args.Error(1)
+}
+
+func (m *MockMovieRepo) UpdateMovie(ctx context.Context, movie entity.Movie) (entity.Movie, error) {
+	args := m.Called(ctx, movie)
+	return args.Get(0).(entity.Movie), args.Error(1)
+}
+
+func (m *MockMovieRepo) DeleteMovie(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMovieRepo) GetMovieCredits(ctx context.Context, movieID int) ([]entity.MovieCastMember, []entity.MovieCrewMember, error) {
+	args := m.Called(ctx, movieID)
+	return args.Get(0).([]entity.MovieCastMember), args.Get(1).([]entity.MovieCrewMember), args.Error(2)
+}
+
 // =====================
 // Movie UseCase Tests
 // =====================
@@ -47,9 +68,9 @@ func TestMovieUseCase_GetAllMovies_Success(t *testing.T) {
 	}
 	pagination := dto.Pagination{CurrentPage: 1, TotalPages: 1, TotalRecords: 2, Limit: 10}
 
-	mockMovieRepo.On("GetAllMovies", mock.Anything, 1, 10).Return(movies, pagination, nil)
+	mockMovieRepo.On("GetAllMovies", mock.Anything, entity.MovieFilter{}, 1, 10).Return(movies, pagination, nil)
 
-	result, pag, err := usecase.GetAllMovies(context.Background(), 1, 10)
+	result, pag, err := usecase.GetAllMovies(context.Background(), dto.MovieQueryRequest{}, 1, 10)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
@@ -64,9 +85,9 @@ func TestMovieUseCase_GetAllMovies_Error(t *testing.T) {
 	repo := &repository.Repository{Movie: mockMovieRepo}
 	usecase := &MovieUseCase{Repo: repo}
 
-	mockMovieRepo.On("GetAllMovies", mock.Anything, 1, 10).Return([]entity.Movie{}, dto.Pagination{}, errors.New("database error"))
+	mockMovieRepo.On("GetAllMovies", mock.Anything, entity.MovieFilter{}, 1, 10).Return([]entity.Movie{}, dto.Pagination{}, errors.New("database error"))
 
-	result, _, err := usecase.GetAllMovies(context.Background(), 1, 10)
+	result, _, err := usecase.GetAllMovies(context.Background(), dto.MovieQueryRequest{}, 1, 10)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -79,9 +100,9 @@ func TestMovieUseCase_GetAllMovies_Empty(t *testing.T) {
 	repo := &repository.Repository{Movie: mockMovieRepo}
 	usecase := &MovieUseCase{Repo: repo}
 
-	mockMovieRepo.On("GetAllMovies", mock.Anything, 1, 10).Return([]entity.Movie{}, dto.Pagination{TotalRecords: 0}, nil)
+	mockMovieRepo.On("GetAllMovies", mock.Anything, entity.MovieFilter{}, 1, 10).Return([]entity.Movie{}, dto.Pagination{TotalRecords: 0}, nil)
 
-	result, pag, err := usecase.GetAllMovies(context.Background(), 1, 10)
+	result, pag, err := usecase.GetAllMovies(context.Background(), dto.MovieQueryRequest{}, 1, 10)
 
 	assert.NoError(t, err)
 	assert.Nil(t, result)
@@ -89,6 +110,112 @@ func TestMovieUseCase_GetAllMovies_Empty(t *testing.T) {
 	mockMovieRepo.AssertExpectations(t)
 }
 
+func TestMovieUseCase_GetAllMovies_WithFilters(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	filter := entity.MovieFilter{
+		Search:        "avengers",
+		Genres:        []string{"Action"},
+		ReleaseStatus: "now_playing",
+		MinRating:     4,
+		Sort:          entity.MovieSortTitle,
+	}
+	movies := []entity.Movie{{ID: 1, Title: "Avengers", Genres: []string{"Action"}, ReleaseStatus: "now_playing"}}
+	mockMovieRepo.On("GetAllMovies", mock.Anything, filter, 1, 10).Return(movies, dto.Pagination{TotalRecords: 1}, nil)
+
+	result, pag, err := usecase.GetAllMovies(context.Background(), dto.MovieQueryRequest{
+		Search:        "  avengers ",
+		Genres:        []string{"Action"},
+		ReleaseStatus: "now_playing",
+		MinRating:     4,
+		Sort:          "title",
+	}, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "now_playing", result[0].ReleaseStatus)
+	assert.Equal(t, 1, pag.TotalRecords)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestMovieUseCase_GetMovieSchedule_GroupsByDateAndCinema(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo, Showtime: mockShowtimeRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	listing := func(id int, date, clock string, cinema entity.Cinema, remaining int) entity.ShowtimeListing {
+		return entity.ShowtimeListing{
+			Showtime: entity.Showtime{
+				ID:       id,
+				CinemaID: cinema.ID,
+				ShowDate: date,
+				ShowTime: clock,
+				Price:    50000,
+				Studio:   &entity.Studio{ID: id, Name: "Studio 1", TotalSeats: 100},
+			},
+			Cinema:         cinema,
+			RemainingSeats: remaining,
+		}
+	}
+	central := entity.Cinema{ID: 1, Name: "Central", Location: "Jakarta"}
+	plaza := entity.Cinema{ID: 2, Name: "Plaza", Location: "Jakarta"}
+	listings := []entity.ShowtimeListing{
+		listing(1, "2030-01-15", "13:00:00", central, 80),
+		listing(2, "2030-01-15", "19:00:00", central, 12),
+		listing(3, "2030-01-15", "19:00:00", plaza, 0),
+		listing(4, "2030-01-16", "13:00:00", central, 100),
+	}
+	filter := entity.MovieScheduleFilter{DateFrom: "2030-01-15", DateTo: "2030-01-16", Location: "jakarta"}
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1, Title: "Avengers"}, nil)
+	mockShowtimeRepo.On("GetMovieSchedule", mock.Anything, 1, filter).Return(listings, nil)
+
+	result, err := usecase.GetMovieSchedule(context.Background(), 1, dto.MovieScheduleRequest{
+		DateFrom: "2030-01-15",
+		DateTo:   "2030-01-16",
+		Location: " jakarta ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Avengers", result.Movie.Title)
+	assert.Len(t, result.Dates, 2)
+	assert.Equal(t, "2030-01-15", result.Dates[0].Date)
+	assert.Len(t, result.Dates[0].Cinemas, 2)
+	assert.Len(t, result.Dates[0].Cinemas[0].Showtimes, 2)
+	assert.Equal(t, 12, result.Dates[0].Cinemas[0].Showtimes[1].RemainingSeats)
+	assert.Equal(t, "Plaza", result.Dates[0].Cinemas[1].Name)
+	assert.Len(t, result.Dates[1].Cinemas, 1)
+	mockMovieRepo.AssertExpectations(t)
+	mockShowtimeRepo.AssertExpectations(t)
+}
+
+func TestMovieUseCase_GetMovieSchedule_NotFound(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 99).Return(entity.Movie{}, pgx.ErrNoRows)
+
+	_, err := usecase.GetMovieSchedule(context.Background(), 99, dto.MovieScheduleRequest{})
+
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestMovieUseCase_GetMovieSchedule_InvalidRange(t *testing.T) {
+	usecase := &MovieUseCase{Repo: &repository.Repository{}}
+
+	_, err := usecase.GetMovieSchedule(context.Background(), 1, dto.MovieScheduleRequest{
+		DateFrom: "2030-01-16",
+		DateTo:   "2030-01-15",
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
+
 func TestMovieUseCase_GetMovieByID_Success(t *testing.T) {
 	mockMovieRepo := new(MockMovieRepo)
 	repo := &repository.Repository{Movie: mockMovieRepo}
@@ -101,11 +228,28 @@ func TestMovieUseCase_GetMovieByID_Success(t *testing.T) {
 		PosterURL:       "http://poster.url",
 		Genres:          []string{"Action", "Sci-Fi"},
 		Rating:          8.5,
+		ReviewCount:     42,
 		DurationMinutes: 180,
+		ReleaseDate:     time.Date(2019, 4, 24, 0, 0, 0, 0, time.UTC),
+		ReleaseStatus:   "now_playing",
+		Synopsis:        "The Avengers assemble once more.",
+		AgeRating:       entity.AgeRating13,
+		Language:        "English",
+		TrailerURL:      "https://example.com/trailer",
 		CreatedAt:       now,
 	}
+	cast := []entity.MovieCastMember{
+		{ID: 1, MovieID: 1, Name: "Robert Downey Jr.", CharacterName: "Tony Stark", BillingOrder: 1},
+		{ID: 2, MovieID: 1, Name: "Chris Evans", CharacterName: "Steve Rogers", BillingOrder: 2},
+	}
+	crew := []entity.MovieCrewMember{
+		{ID: 1, MovieID: 1, Name: "Anthony Russo", Job: "Director"},
+		{ID: 2, MovieID: 1, Name: "Kevin Feige", Job: "Producer"},
+		{ID: 3, MovieID: 1, Name: "Joe Russo", Job: "Director"},
+	}
 
 	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(movie, nil)
+	mockMovieRepo.On("GetMovieCredits", mock.Anything, 1).Return(cast, crew, nil)
 
 	result, err := usecase.GetMovieByID(context.Background(), 1)
 
@@ -113,9 +257,35 @@ func TestMovieUseCase_GetMovieByID_Success(t *testing.T) {
 	assert.Equal(t, 1, result.ID)
 	assert.Equal(t, "Avengers", result.Title)
 	assert.Equal(t, 8.5, result.Rating)
+	assert.Equal(t, 42, result.ReviewCount)
+	assert.Equal(t, "2019-04-24", result.ReleaseDate)
+	assert.Equal(t, "now_playing", result.ReleaseStatus)
+	assert.Equal(t, "13+", result.AgeRating)
+	assert.Equal(t, "The Avengers assemble once more.", result.Synopsis)
+	assert.Equal(t, "English", result.Language)
+	assert.Equal(t, "https://example.com/trailer", result.TrailerURL)
+	assert.Equal(t, "Anthony Russo, Joe Russo", result.Director)
+	assert.Equal(t, []dto.MovieCastResponse{
+		{Name: "Robert Downey Jr.", CharacterName: "Tony Stark"},
+		{Name: "Chris Evans", CharacterName: "Steve Rogers"},
+	}, result.Cast)
+	assert.Len(t, result.Crew, 3)
 	mockMovieRepo.AssertExpectations(t)
 }
 
+func TestMovieUseCase_GetMovieByID_MissingMovie(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 999).Return(entity.Movie{}, pgx.ErrNoRows)
+
+	_, err := usecase.GetMovieByID(context.Background(), 999)
+
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	mockMovieRepo.AssertNotCalled(t, "GetMovieCredits", mock.Anything, mock.Anything)
+}
+
 func TestMovieUseCase_GetMovieByID_NotFound(t *testing.T) {
 	mockMovieRepo := new(MockMovieRepo)
 	repo := &repository.Repository{Movie: mockMovieRepo}
@@ -130,3 +300,105 @@ func TestMovieUseCase_GetMovieByID_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "not found")
 	mockMovieRepo.AssertExpectations(t)
 }
+
+func newMovieRequest() dto.MovieRequest {
+	return dto.MovieRequest{
+		Title:           "New Movie",
+		PosterURL:       "public/images/new.png",
+		Genres:          []string{"Drama"},
+		DurationMinutes: 110,
+		ReleaseDate:     "2026-05-01",
+		ReleaseStatus:   "coming_soon",
+	}
+}
+
+func TestMovieUseCase_CreateMovie_Success(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	mockMovieRepo.On("CreateMovie", mock.Anything, mock.MatchedBy(func(m entity.Movie) bool {
+		return m.Title == "New Movie" && m.ReleaseDate.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(entity.Movie{
+		ID:              11,
+		Title:           "New Movie",
+		Genres:          []string{"Drama"},
+		DurationMinutes: 110,
+		ReleaseDate:     time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+		ReleaseStatus:   "coming_soon",
+	}, nil)
+
+	result, err := usecase.CreateMovie(context.Background(), newMovieRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, result.ID)
+	assert.Equal(t, "2026-05-01", result.ReleaseDate)
+	assert.Equal(t, "coming_soon", result.ReleaseStatus)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestMovieUseCase_CreateMovie_WithCredits(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	req := newMovieRequest()
+	req.Cast = []dto.MovieCastRequest{
+		{Name: "Lead Actor", CharacterName: "Hero"},
+		{Name: "Supporting Actor"},
+	}
+	req.Crew = []dto.MovieCrewRequest{{Name: "Some Director", Job: "Director"}}
+
+	// No age rating defaults to all ages and the cast is billed in request order
+	mockMovieRepo.On("CreateMovie", mock.Anything, mock.MatchedBy(func(m entity.Movie) bool {
+		return m.AgeRating == entity.AgeRatingAll && len(m.Cast) == 2 &&
+			m.Cast[0].BillingOrder == 1 && m.Cast[1].BillingOrder == 2 && len(m.Crew) == 1
+	})).Return(entity.Movie{
+		ID:        12,
+		Title:     "New Movie",
+		AgeRating: entity.AgeRatingAll,
+		Cast: []entity.MovieCastMember{
+			{Name: "Lead Actor", CharacterName: "Hero", BillingOrder: 1},
+			{Name: "Supporting Actor", BillingOrder: 2},
+		},
+		Crew: []entity.MovieCrewMember{{Name: "Some Director", Job: "Director"}},
+	}, nil)
+
+	result, err := usecase.CreateMovie(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SU", result.AgeRating)
+	assert.Equal(t, "Some Director", result.Director)
+	assert.Len(t, result.Cast, 2)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestMovieUseCase_UpdateMovie_NotFound(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	mockMovieRepo.On("UpdateMovie", mock.Anything, mock.MatchedBy(func(m entity.Movie) bool {
+		return m.ID == 999
+	})).Return(entity.Movie{}, pgx.ErrNoRows)
+
+	_, err := usecase.UpdateMovie(context.Background(), 999, newMovieRequest())
+
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestMovieUseCase_DeleteMovie(t *testing.T) {
+	mockMovieRepo := new(MockMovieRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo}
+	usecase := &MovieUseCase{Repo: repo}
+
+	mockMovieRepo.On("DeleteMovie", mock.Anything, 1).Return(nil)
+	mockMovieRepo.On("DeleteMovie", mock.Anything, 2).Return(repository.ErrMovieHasShowtimes)
+	mockMovieRepo.On("DeleteMovie", mock.Anything, 999).Return(pgx.ErrNoRows)
+
+	assert.NoError(t, usecase.DeleteMovie(context.Background(), 1))
+	assert.ErrorIs(t, usecase.DeleteMovie(context.Background(), 2), ErrMovieHasShowtimes)
+	assert.ErrorIs(t, usecase.DeleteMovie(context.Background(), 999), ErrMovieNotFound)
+	mockMovieRepo.AssertExpectations(t)
+}
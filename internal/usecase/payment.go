@@ -8,31 +8,54 @@ import (
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 type PaymentUseCaseInterface interface {
 	GetPaymentMethods(ctx context.Context) ([]dto.PaymentMethodResponse, error)
 	ProcessPayment(ctx context.Context, userID int, req dto.PayRequest) (dto.PaymentResponse, error)
+	HandleWebhook(ctx context.Context, methodID int, signature string, payload []byte, req dto.PaymentWebhookRequest) (dto.PaymentWebhookResponse, error)
+	ReconcilePendingPayments(ctx context.Context) (int, error)
 }
 
 type PaymentUseCase struct {
 	Repo         *repository.Repository
 	EmailService *utils.EmailService
+	Gateways     *gateway.Registry
+	SeatHub      *realtime.Hub
+	Loyalty      utils.LoyaltyConfig
+	Logger       *zap.Logger
 }
 
-func NewPaymentUseCase(repo *repository.Repository) PaymentUseCaseInterface {
+func NewPaymentUseCase(repo *repository.Repository, config utils.Configuration, gateways *gateway.Registry, seatHub *realtime.Hub, logger *zap.Logger) PaymentUseCaseInterface {
 	return &PaymentUseCase{
 		Repo:         repo,
 		EmailService: utils.NewEmailService(),
+		Gateways:     gateways,
+		SeatHub:      seatHub,
+		Loyalty:      config.Loyalty,
+		Logger:       logger,
 	}
 }
 
+// logger returns the configured logger, or one that discards everything when not set
+func (u *PaymentUseCase) logger() *zap.Logger {
+	if u.Logger == nil {
+		return zap.NewNop()
+	}
+	return u.Logger
+}
+
 // sendPaymentConfirmation sends payment confirmation email asynchronously (GOROUTINE)
-func (u *PaymentUseCase) sendPaymentConfirmation(email, username, paymentMethod string, amount float64) {
+func (u *PaymentUseCase) sendPaymentConfirmation(email, username, paymentMethod string, amount float64, bookingID int) {
 	message := fmt.Sprintf(
-		"Dear %s,\n\nYour payment has been processed successfully!\n\nPayment Method: %s\nAmount: Rp %.0f\n\nThank you for your purchase!",
-		username, paymentMethod, amount,
+		"Dear %s,\n\nYour payment has been processed successfully!\n\nPayment Method: %s\nAmount: Rp %.0f\n\nYour e-tickets are ready at /api/bookings/%d/tickets, show their QR codes at the entrance.\n\nThank you for your purchase!",
+		username, paymentMethod, amount, bookingID,
 	)
 	u.EmailService.SendOTP(email, username, message)
 }
@@ -55,7 +78,8 @@ func (u *PaymentUseCase) GetPaymentMethods(ctx context.Context) ([]dto.PaymentMe
 	return response, nil
 }
 
-// ProcessPayment processes payment for a booking
+// ProcessPayment charges a booking through the gateway of the chosen payment method
+// and records the pending, failed or completed outcome
 func (u *PaymentUseCase) ProcessPayment(ctx context.Context, userID int, req dto.PayRequest) (dto.PaymentResponse, error) {
 	// Verify booking exists and belongs to user
 	booking, err := u.Repo.Booking.GetBookingByID(ctx, req.BookingID)
@@ -75,12 +99,37 @@ func (u *PaymentUseCase) ProcessPayment(ctx context.Context, userID int, req dto
 		return dto.PaymentResponse{}, errors.New("booking is cancelled")
 	}
 
-	// Verify payment method exists
+	if booking.Status == "expired" || (booking.ExpiresAt != nil && time.Now().After(*booking.ExpiresAt)) {
+		return dto.PaymentResponse{}, errors.New("booking hold has expired")
+	}
+
+	// Verify payment method exists and has a gateway
 	method, err := u.Repo.Payment.GetPaymentMethodByID(ctx, req.PaymentMethod)
 	if err != nil {
 		return dto.PaymentResponse{}, errors.New("invalid payment method")
 	}
 
+	gw, err := u.Gateways.Get(method.Gateway)
+	if err != nil {
+		return dto.PaymentResponse{}, err
+	}
+
+	// A promotion tied to a payment method was checked against the method chosen at booking
+	if booking.PromotionID != nil {
+		promotion, err := u.Repo.Promotion.GetPromotionByID(ctx, *booking.PromotionID)
+		if err != nil {
+			return dto.PaymentResponse{}, err
+		}
+		if promotion.PaymentMethodID != nil && *promotion.PaymentMethodID != req.PaymentMethod {
+			return dto.PaymentResponse{}, fmt.Errorf("%w: not valid for this payment method", ErrPromoNotApplicable)
+		}
+	}
+
+	// Only one attempt may wait for provider confirmation at a time
+	if existing, err := u.Repo.Payment.GetPaymentByBookingID(ctx, req.BookingID); err == nil && existing.Status == gateway.StatusPending {
+		return dto.PaymentResponse{}, ErrPaymentInProgress
+	}
+
 	// Convert payment details to JSON format for jsonb column
 	var paymentDetailsJSON []byte
 	if req.PaymentDetails != "" {
@@ -91,11 +140,11 @@ func (u *PaymentUseCase) ProcessPayment(ctx context.Context, userID int, req dto
 		paymentDetailsJSON = []byte("{}")
 	}
 
-	// Create payment record
+	// Create pending payment record before contacting the gateway
 	payment := entity.Payment{
 		BookingID:       req.BookingID,
 		PaymentMethodID: req.PaymentMethod,
-		Status:          "completed",
+		Status:          gateway.StatusPending,
 		PaymentDetails:  string(paymentDetailsJSON),
 	}
 
@@ -104,25 +153,215 @@ func (u *PaymentUseCase) ProcessPayment(ctx context.Context, userID int, req dto
 		return dto.PaymentResponse{}, err
 	}
 
-	// Update booking status to paid
-	if err := u.Repo.Booking.UpdateBookingStatus(ctx, req.BookingID, "paid"); err != nil {
+	result, err := gw.Charge(ctx, gateway.ChargeRequest{
+		PaymentID: paymentID,
+		BookingID: booking.ID,
+		Amount:    booking.TotalAmount,
+		Details:   req.PaymentDetails,
+	})
+	if err != nil {
+		u.Repo.Payment.UpdatePaymentStatus(ctx, paymentID, gateway.StatusFailed)
+		return dto.PaymentResponse{}, fmt.Errorf("payment gateway error: %w", err)
+	}
+
+	// Record the transition; a completed charge also marks the booking paid
+	err = u.Repo.Payment.SettlePayment(ctx, paymentID, booking.ID, result.Status, result.TransactionID)
+	if errors.Is(err, repository.ErrBookingStatusChanged) {
+		// Booking expired or was cancelled while charging, give the money back
+		payment.ID = paymentID
+		payment.TransactionID = result.TransactionID
+		if u.refundUnpayableCharge(ctx, payment) != "completed" {
+			return dto.PaymentResponse{}, errors.New("booking is no longer payable, the refund of your payment is being processed")
+		}
+		return dto.PaymentResponse{}, errors.New("booking is no longer payable, payment has been refunded")
+	}
+	if err != nil {
 		return dto.PaymentResponse{}, err
 	}
 
+	response := dto.PaymentResponse{
+		ID:            paymentID,
+		PaymentMethod: method.Name,
+		Status:        result.Status,
+		TransactionID: result.TransactionID,
+		Message:       result.Message,
+	}
+
+	if result.Status != gateway.StatusCompleted {
+		return response, nil
+	}
+
 	createdPayment, _ := u.Repo.Payment.GetPaymentByBookingID(ctx, req.BookingID)
+	response.PaidAt = createdPayment.PaidAt
+	response.PointsEarned = earnBookingPoints(ctx, u.Repo, u.Loyalty, booking.ID)
+
+	publishBookingSeats(ctx, u.Repo, u.SeatHub, booking, entity.SeatBooked, realtime.ReasonBookingPaid)
 
 	// Send payment confirmation email asynchronously using GOROUTINE
 	// This allows the API to respond immediately without waiting for email to be sent
 	user, _ := u.Repo.Auth.GetUserByID(ctx, userID)
 	if user.Email != "" {
 		// GOROUTINE: Non-blocking email notification after payment
-		go u.sendPaymentConfirmation(user.Email, user.Username, method.Name, booking.TotalAmount)
+		go u.sendPaymentConfirmation(user.Email, user.Username, method.Name, booking.TotalAmount, booking.ID)
 	}
 
-	return dto.PaymentResponse{
-		ID:            paymentID,
-		PaymentMethod: method.Name,
-		Status:        "completed",
-		PaidAt:        createdPayment.PaidAt,
+	return response, nil
+}
+
+// HandleWebhook verifies a provider callback against the payment method secret and
+// applies the reported outcome to the pending payment and its booking
+func (u *PaymentUseCase) HandleWebhook(ctx context.Context, methodID int, signature string, payload []byte, req dto.PaymentWebhookRequest) (dto.PaymentWebhookResponse, error) {
+	method, err := u.Repo.Payment.GetPaymentMethodByID(ctx, methodID)
+	if err != nil || !gateway.VerifySignature(method.WebhookSecret, payload, signature) {
+		return dto.PaymentWebhookResponse{}, ErrInvalidWebhookSignature
+	}
+
+	event := entity.PaymentWebhookEvent{
+		PaymentMethodID: methodID,
+		TransactionID:   req.TransactionID,
+		Status:          req.Status,
+		Payload:         string(payload),
+	}
+
+	payment, err := u.Repo.Payment.ApplyWebhookEvent(ctx, event)
+	if errors.Is(err, repository.ErrBookingStatusChanged) {
+		// Booking expired or was cancelled before the provider confirmed, give the money back
+		response := dto.PaymentWebhookResponse{
+			PaymentID:    payment.ID,
+			BookingID:    payment.BookingID,
+			Status:       payment.Status,
+			RefundStatus: u.refundUnpayableCharge(ctx, payment),
+		}
+		if response.RefundStatus == "completed" {
+			response.Status = gateway.StatusRefunded
+		}
+		return response, nil
+	}
+	if err != nil {
+		return dto.PaymentWebhookResponse{}, err
+	}
+
+	if payment.Status == gateway.StatusCompleted {
+		earnBookingPoints(ctx, u.Repo, u.Loyalty, payment.BookingID)
+
+		booking, err := u.Repo.Booking.GetBookingByID(ctx, payment.BookingID)
+		if err == nil {
+			publishBookingSeats(ctx, u.Repo, u.SeatHub, booking, entity.SeatBooked, realtime.ReasonBookingPaid)
+
+			user, _ := u.Repo.Auth.GetUserByID(ctx, booking.UserID)
+			if user.Email != "" {
+				// GOROUTINE: Non-blocking email notification after payment
+				go u.sendPaymentConfirmation(user.Email, user.Username, method.Name, booking.TotalAmount, booking.ID)
+			}
+		}
+	}
+
+	return dto.PaymentWebhookResponse{
+		PaymentID: payment.ID,
+		BookingID: payment.BookingID,
+		Status:    payment.Status,
 	}, nil
 }
+
+// ReconcilePendingPayments sends refunds the gateway did not complete again and asks the
+// provider for the outcome of pending payments whose booking hold has passed, for when
+// their webhook is lost. Completed charges settle the booking, or are refunded if it is
+// no longer payable, and failed ones are recorded so the booking can expire. Payments
+// the provider cannot answer for yet and refunds failing again are left for the next
+// run. It returns how many payments were settled or refunded.
+func (u *PaymentUseCase) ReconcilePendingPayments(ctx context.Context) (int, error) {
+	refunds, err := u.Repo.Payment.GetFailedRefunds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, refund := range refunds {
+		payment, err := u.Repo.Payment.GetPaymentByID(ctx, refund.PaymentID)
+		if err != nil {
+			continue
+		}
+		if u.sendRefund(ctx, refund, payment) == "completed" {
+			settled++
+		}
+	}
+
+	payments, err := u.Repo.Payment.GetStalePendingPayments(ctx)
+	if err != nil {
+		return settled, err
+	}
+
+	for _, payment := range payments {
+		method, err := u.Repo.Payment.GetPaymentMethodByID(ctx, payment.PaymentMethodID)
+		if err != nil {
+			continue
+		}
+		gw, err := u.Gateways.Get(method.Gateway)
+		if err != nil {
+			continue
+		}
+		result, err := gw.QueryStatus(ctx, payment.TransactionID)
+		if err != nil || (result.Status != gateway.StatusCompleted && result.Status != gateway.StatusFailed) {
+			continue
+		}
+
+		booking, err := u.Repo.Booking.GetBookingByID(ctx, payment.BookingID)
+		if err != nil {
+			continue
+		}
+
+		err = u.Repo.Payment.SettlePayment(ctx, payment.ID, payment.BookingID, result.Status, payment.TransactionID)
+		if errors.Is(err, repository.ErrBookingStatusChanged) {
+			// Booking expired or was cancelled meanwhile, give the money back
+			u.refundUnpayableCharge(ctx, payment)
+			settled++
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		settled++
+
+		if result.Status != gateway.StatusCompleted {
+			continue
+		}
+		earnBookingPoints(ctx, u.Repo, u.Loyalty, booking.ID)
+		publishBookingSeats(ctx, u.Repo, u.SeatHub, booking, entity.SeatBooked, realtime.ReasonBookingPaid)
+
+		user, _ := u.Repo.Auth.GetUserByID(ctx, booking.UserID)
+		if user.Email != "" {
+			// GOROUTINE: Non-blocking email notification after payment
+			go u.sendPaymentConfirmation(user.Email, user.Username, method.Name, booking.TotalAmount, booking.ID)
+		}
+	}
+	return settled, nil
+}
+
+// refundUnpayableCharge sends the refund queued when a charge completed after its booking
+// stopped being payable and returns its status. A refund the gateway does not complete
+// stays recorded as failed for ReconcilePendingPayments to send again.
+func (u *PaymentUseCase) refundUnpayableCharge(ctx context.Context, payment entity.Payment) string {
+	refund, err := u.Repo.Payment.GetRefundByBookingID(ctx, payment.BookingID)
+	if err != nil {
+		u.logger().Error("refund of charge for unpayable booking not recorded",
+			zap.Int("payment_id", payment.ID), zap.Int("booking_id", payment.BookingID),
+			zap.String("transaction_id", payment.TransactionID), zap.Error(err))
+		return "pending"
+	}
+	return u.sendRefund(ctx, refund, payment)
+}
+
+// sendRefund processes a recorded refund through the payment's gateway and logs the outcome
+func (u *PaymentUseCase) sendRefund(ctx context.Context, refund entity.Refund, payment entity.Payment) string {
+	status := processRefund(ctx, u.Repo, u.Gateways, refund.ID, payment, refund.Amount)
+	fields := []zap.Field{
+		zap.Int("refund_id", refund.ID), zap.Int("payment_id", payment.ID), zap.Int("booking_id", payment.BookingID),
+		zap.String("transaction_id", payment.TransactionID), zap.Float64("amount", refund.Amount), zap.String("status", status),
+	}
+	if status == "completed" {
+		u.logger().Info("refund completed", fields...)
+	} else {
+		u.logger().Warn("refund not completed, it will be retried", fields...)
+	}
+	return status
+}
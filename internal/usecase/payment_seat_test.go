@@ -6,10 +6,14 @@ import (
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -47,6 +51,41 @@ func (m *MockPaymentRepo) UpdatePaymentStatus(ctx context.Context, paymentID int
 	return args.Error(0)
 }
 
+func (m *MockPaymentRepo) SettlePayment(ctx context.Context, paymentID, bookingID int, status, transactionID string) error {
+	args := m.Called(ctx, paymentID, bookingID, status, transactionID)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepo) GetStalePendingPayments(ctx context.Context) ([]entity.Payment, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepo) ApplyWebhookEvent(ctx context.Context, event entity.PaymentWebhookEvent) (entity.Payment, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepo) GetRefundByBookingID(ctx context.Context, bookingID int) (entity.Refund, error) {
+	args := m.Called(ctx, bookingID)
+	return args.Get(0).(entity.Refund), args.Error(1)
+}
+
+func (m *MockPaymentRepo) UpdateRefundStatus(ctx context.Context, refundID int, status string) error {
+	args := m.Called(ctx, refundID, status)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepo) GetPaymentByID(ctx context.Context, id int) (entity.Payment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepo) GetFailedRefunds(ctx context.Context) ([]entity.Refund, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Refund), args.Error(1)
+}
+
 // =====================
 // Mock Repository untuk Seat
 // =====================
@@ -60,11 +99,6 @@ func (m *MockSeatRepo) GetSeatsByShowtime(ctx context.Context, showtimeID int) (
 	return args.Get(0).([]entity.SeatAvailability), args.Error(1)
 }
 
-func (m *MockSeatRepo) GetShowtimeByParams(ctx context.Context, cinemaID int, date, time string) (entity.Showtime, error) {
-	args := m.Called(ctx, cinemaID, date, time)
-	return args.Get(0).(entity.Showtime), args.Error(1)
-}
-
 func (m *MockSeatRepo) GetShowtimeByID(ctx context.Context, id int) (entity.Showtime, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(entity.Showtime), args.Error(1)
@@ -93,8 +127,8 @@ type MockBookingRepoForPayment struct {
 	mock.Mock
 }
 
-func (m *MockBookingRepoForPayment) CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int, price float64) (int, error) {
-	args := m.Called(ctx, booking, seatIDs, price)
+func (m *MockBookingRepoForPayment) CreateBooking(ctx context.Context, booking entity.Booking, seatIDs []int) (int, error) {
+	args := m.Called(ctx, booking, seatIDs)
 	return args.Int(0), args.Error(1)
 }
 
@@ -113,11 +147,26 @@ func (m *MockBookingRepoForPayment) GetBookingSeats(ctx context.Context, booking
 	return args.Get(0).([]entity.BookingSeat), args.Error(1)
 }
 
+func (m *MockBookingRepoForPayment) GetBookingConcessions(ctx context.Context, bookingID int) ([]entity.BookingConcession, error) {
+	args := m.Called(ctx, bookingID)
+	return args.Get(0).([]entity.BookingConcession), args.Error(1)
+}
+
 func (m *MockBookingRepoForPayment) UpdateBookingStatus(ctx context.Context, bookingID int, status string) error {
 	args := m.Called(ctx, bookingID, status)
 	return args.Error(0)
 }
 
+func (m *MockBookingRepoForPayment) ExpirePendingBookings(ctx context.Context, paymentGrace time.Duration) ([]entity.Booking, error) {
+	args := m.Called(ctx, paymentGrace)
+	return args.Get(0).([]entity.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepoForPayment) CancelBooking(ctx context.Context, bookingID int, fromStatus string, refund *entity.Refund) (int, error) {
+	args := m.Called(ctx, bookingID, fromStatus, refund)
+	return args.Int(0), args.Error(1)
+}
+
 // =====================
 // Payment UseCase Tests
 // =====================
@@ -282,6 +331,37 @@ func TestPaymentUseCase_ProcessPayment_BookingCancelled(t *testing.T) {
 	mockBookingRepo.AssertExpectations(t)
 }
 
+func TestPaymentUseCase_ProcessPayment_HoldExpired(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	repo := &repository.Repository{
+		Payment: mockPaymentRepo,
+		Booking: mockBookingRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo}
+
+	expiredAt := time.Now().Add(-time.Minute)
+	booking := entity.Booking{
+		ID:        1,
+		UserID:    1,
+		Status:    "pending",
+		ExpiresAt: &expiredAt,
+	}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+
+	req := dto.PayRequest{
+		BookingID:     1,
+		PaymentMethod: 1,
+	}
+
+	_, err := usecase.ProcessPayment(context.Background(), 1, req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+	mockBookingRepo.AssertExpectations(t)
+}
+
 func TestPaymentUseCase_ProcessPayment_InvalidPaymentMethod(t *testing.T) {
 	mockPaymentRepo := new(MockPaymentRepo)
 	mockBookingRepo := new(MockBookingRepoForPayment)
@@ -319,54 +399,120 @@ func TestPaymentUseCase_ProcessPayment_InvalidPaymentMethod(t *testing.T) {
 
 func TestSeatUseCase_GetSeatAvailability_Success(t *testing.T) {
 	mockSeatRepo := new(MockSeatRepo)
-	repo := &repository.Repository{Seat: mockSeatRepo}
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo}
 	usecase := &SeatUseCase{Repo: repo}
 
 	showtime := entity.Showtime{
 		ID:       1,
 		CinemaID: 1,
+		StudioID: 1,
 		ShowDate: "2026-01-15",
 		ShowTime: "19:00",
 	}
 
 	seats := []entity.SeatAvailability{
-		{ID: 1, SeatCode: "A1", StudioID: 1, IsBooked: false},
-		{ID: 2, SeatCode: "A2", StudioID: 1, IsBooked: true},
-		{ID: 3, SeatCode: "A3", StudioID: 1, IsBooked: false},
+		{ID: 1, SeatCode: "A1", StudioID: 1, RowLabel: "A", ColumnNumber: 1, State: entity.SeatAvailable},
+		{ID: 2, SeatCode: "A2", StudioID: 1, RowLabel: "A", ColumnNumber: 3, State: entity.SeatHeld, IsBooked: true},
+		{ID: 3, SeatCode: "B1", StudioID: 1, RowLabel: "B", ColumnNumber: 1, State: entity.SeatBooked, IsBooked: true},
 	}
 
-	mockSeatRepo.On("GetShowtimeByParams", mock.Anything, 1, "2026-01-15", "19:00").Return(showtime, nil)
+	mockShowtimeRepo.On("SearchShowtimes", mock.Anything, entity.ShowtimeFilter{CinemaID: 1, ShowDate: "2026-01-15", ShowTime: "19:00:00"}).Return([]entity.Showtime{showtime}, nil)
 	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(seats, nil)
 
 	result, err := usecase.GetSeatAvailability(context.Background(), 1, "2026-01-15", "19:00")
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 3)
-	assert.Equal(t, "A1", result[0].SeatCode)
-	assert.False(t, result[0].IsBooked)
-	assert.True(t, result[1].IsBooked)
+	assert.Equal(t, 1, result.StudioID)
+	assert.Equal(t, 3, result.Columns)
+	assert.Len(t, result.Rows, 2)
+	assert.Equal(t, "A1", result.Rows[0].Cells[0].SeatCode)
+	assert.True(t, result.Rows[0].Cells[1].Gap)
+	assert.Equal(t, entity.SeatHeld, result.Rows[0].Cells[2].State)
+	assert.Equal(t, entity.SeatBooked, result.Rows[1].Cells[0].State)
 	mockSeatRepo.AssertExpectations(t)
 }
 
 func TestSeatUseCase_GetSeatAvailability_ShowtimeNotFound(t *testing.T) {
 	mockSeatRepo := new(MockSeatRepo)
-	repo := &repository.Repository{Seat: mockSeatRepo}
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo}
 	usecase := &SeatUseCase{Repo: repo}
 
-	mockSeatRepo.On("GetShowtimeByParams", mock.Anything, 1, "2026-01-15", "19:00").Return(entity.Showtime{}, errors.New("showtime not found"))
+	mockShowtimeRepo.On("SearchShowtimes", mock.Anything, entity.ShowtimeFilter{CinemaID: 1, ShowDate: "2026-01-15", ShowTime: "19:00:00"}).Return([]entity.Showtime{}, nil)
 
 	result, err := usecase.GetSeatAvailability(context.Background(), 1, "2026-01-15", "19:00")
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrShowtimeNotFound)
+	assert.Empty(t, result.Rows)
+	mockShowtimeRepo.AssertExpectations(t)
+}
+
+func TestSeatUseCase_GetSeatAvailability_Ambiguous(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo}
+	usecase := &SeatUseCase{Repo: repo}
+
+	// Two studios of the cinema start at 19:00; the seconds are optional in the query
+	showtimes := []entity.Showtime{
+		{ID: 1, CinemaID: 1, StudioID: 1, ShowDate: "2026-01-15", ShowTime: "19:00:00"},
+		{ID: 2, CinemaID: 1, StudioID: 2, ShowDate: "2026-01-15", ShowTime: "19:00:00"},
+	}
+	mockShowtimeRepo.On("SearchShowtimes", mock.Anything, entity.ShowtimeFilter{CinemaID: 1, ShowDate: "2026-01-15", ShowTime: "19:00:00"}).Return(showtimes, nil)
+
+	_, err := usecase.GetSeatAvailability(context.Background(), 1, "2026-01-15", "19:00:00")
+
+	assert.ErrorIs(t, err, ErrAmbiguousShowtime)
+	mockSeatRepo.AssertNotCalled(t, "GetSeatsByShowtime", mock.Anything, mock.Anything)
+	mockShowtimeRepo.AssertExpectations(t)
+}
+
+func TestSeatUseCase_GetSeatAvailability_InvalidTime(t *testing.T) {
+	usecase := &SeatUseCase{Repo: &repository.Repository{}}
+
+	_, err := usecase.GetSeatAvailability(context.Background(), 1, "2026-01-15", "7pm")
+
+	assert.ErrorIs(t, err, ErrInvalidShowTime)
+}
+
+func TestSeatUseCase_GetSeatMap_Success(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	usecase := &SeatUseCase{Repo: repo}
+
+	showtime := entity.Showtime{ID: 4, StudioID: 2, Status: entity.ShowtimeScheduled}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 4).Return(showtime, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 4).Return(layoutSeats("oxo"), nil)
+
+	result, err := usecase.GetSeatMap(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.ShowtimeID)
+	assert.Equal(t, 2, result.StudioID)
+	assert.Equal(t, entity.SeatBooked, result.Rows[0].Cells[1].State)
 	mockSeatRepo.AssertExpectations(t)
 }
 
-func TestSeatUseCase_GetSeatAvailability_SeatsError(t *testing.T) {
+func TestSeatUseCase_GetSeatMap_NotFound(t *testing.T) {
 	mockSeatRepo := new(MockSeatRepo)
 	repo := &repository.Repository{Seat: mockSeatRepo}
 	usecase := &SeatUseCase{Repo: repo}
 
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 99).Return(entity.Showtime{}, pgx.ErrNoRows)
+
+	_, err := usecase.GetSeatMap(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrShowtimeNotFound)
+	mockSeatRepo.AssertExpectations(t)
+}
+
+func TestSeatUseCase_GetSeatAvailability_SeatsError(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo}
+	usecase := &SeatUseCase{Repo: repo}
+
 	showtime := entity.Showtime{
 		ID:       1,
 		CinemaID: 1,
@@ -374,16 +520,242 @@ func TestSeatUseCase_GetSeatAvailability_SeatsError(t *testing.T) {
 		ShowTime: "19:00",
 	}
 
-	mockSeatRepo.On("GetShowtimeByParams", mock.Anything, 1, "2026-01-15", "19:00").Return(showtime, nil)
+	mockShowtimeRepo.On("SearchShowtimes", mock.Anything, entity.ShowtimeFilter{CinemaID: 1, ShowDate: "2026-01-15", ShowTime: "19:00:00"}).Return([]entity.Showtime{showtime}, nil)
 	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return([]entity.SeatAvailability{}, errors.New("database error"))
 
 	result, err := usecase.GetSeatAvailability(context.Background(), 1, "2026-01-15", "19:00")
 
 	assert.Error(t, err)
-	assert.Nil(t, result)
+	assert.Empty(t, result.Rows)
+	mockSeatRepo.AssertExpectations(t)
+}
+
+func TestBuildSeatMap(t *testing.T) {
+	showtime := entity.Showtime{ID: 1, StudioID: 2}
+
+	t.Run("Rows Sorted Naturally And Gaps Filled", func(t *testing.T) {
+		seats := []entity.SeatAvailability{
+			{ID: 1, SeatCode: "A10", RowLabel: "A", ColumnNumber: 11},
+			{ID: 2, SeatCode: "A2", RowLabel: "A", ColumnNumber: 2},
+			{ID: 3, SeatCode: "AA1", RowLabel: "AA", ColumnNumber: 1},
+			{ID: 4, SeatCode: "B1", RowLabel: "B", ColumnNumber: 1},
+		}
+
+		seatMap := buildSeatMap(showtime, seats)
+
+		assert.Equal(t, 11, seatMap.Columns)
+		assert.Equal(t, []string{"A", "B", "AA"}, []string{seatMap.Rows[0].Label, seatMap.Rows[1].Label, seatMap.Rows[2].Label})
+		assert.Len(t, seatMap.Rows[0].Cells, 11)
+		assert.True(t, seatMap.Rows[0].Cells[0].Gap)
+		assert.Equal(t, "A2", seatMap.Rows[0].Cells[1].SeatCode)
+		assert.Equal(t, "A10", seatMap.Rows[0].Cells[10].SeatCode)
+	})
+
+	t.Run("Seats Without Coordinates Use Their Code", func(t *testing.T) {
+		seats := []entity.SeatAvailability{
+			{ID: 1, SeatCode: "C2"},
+			{ID: 2, SeatCode: "C1"},
+			{ID: 3, SeatCode: "C"},
+		}
+
+		seatMap := buildSeatMap(showtime, seats)
+
+		assert.Len(t, seatMap.Rows, 1)
+		assert.Equal(t, "C1", seatMap.Rows[0].Cells[0].SeatCode)
+		assert.Equal(t, "C2", seatMap.Rows[0].Cells[1].SeatCode)
+		assert.Equal(t, "C", seatMap.Rows[0].Cells[2].SeatCode)
+	})
+}
+
+// layoutSeats builds seats from one string per row, where "o" is an available seat,
+// "x" a taken seat and "_" an aisle. Seat IDs count up across rows.
+func layoutSeats(rows ...string) []entity.SeatAvailability {
+	var seats []entity.SeatAvailability
+	for r, row := range rows {
+		label := string(rune('A' + r))
+		for c, cell := range row {
+			if cell == '_' {
+				continue
+			}
+			state := entity.SeatAvailable
+			if cell == 'x' {
+				state = entity.SeatBooked
+			}
+			seats = append(seats, entity.SeatAvailability{
+				ID:           len(seats) + 1,
+				SeatCode:     label + strconv.Itoa(c+1),
+				RowLabel:     label,
+				ColumnNumber: c + 1,
+				State:        state,
+			})
+		}
+	}
+	return seats
+}
+
+func seatCodes(seats []entity.SeatAvailability) []string {
+	codes := make([]string, 0, len(seats))
+	for _, seat := range seats {
+		codes = append(codes, seat.SeatCode)
+	}
+	return codes
+}
+
+func TestPickBestSeats(t *testing.T) {
+	t.Run("Centre Of Middle Row", func(t *testing.T) {
+		seats := layoutSeats("ooooooo", "ooooooo", "ooooooo")
+
+		assert.Equal(t, []string{"B3", "B4", "B5"}, seatCodes(pickBestSeats(seats, 3)))
+	})
+
+	t.Run("Block Does Not Cross Taken Seats Or Aisles", func(t *testing.T) {
+		seats := layoutSeats("oooxooo_oo")
+
+		assert.Equal(t, []string{"A5", "A6", "A7"}, seatCodes(pickBestSeats(seats, 3)))
+	})
+
+	t.Run("No Single Seat Left Behind", func(t *testing.T) {
+		// Both centred pairs would strand an end seat, so the pair moves to the edge
+		seats := layoutSeats("ooooo")
+
+		assert.Equal(t, []string{"A1", "A2"}, seatCodes(pickBestSeats(seats, 2)))
+	})
+
+	t.Run("Orphan Accepted When Unavoidable", func(t *testing.T) {
+		seats := layoutSeats("xooox")
+
+		assert.Equal(t, []string{"A2", "A3"}, seatCodes(pickBestSeats(seats, 2)))
+	})
+
+	t.Run("Split Across Rows When No Row Fits", func(t *testing.T) {
+		seats := layoutSeats("oxo", "xox")
+
+		assert.ElementsMatch(t, []string{"A1", "A3", "B2"}, seatCodes(pickBestSeats(seats, 3)))
+	})
+
+	t.Run("Not Enough Seats", func(t *testing.T) {
+		seats := layoutSeats("oxx", "xxo")
+
+		assert.Nil(t, pickBestSeats(seats, 3))
+	})
+}
+
+func TestSeatUseCase_SuggestSeats_Success(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	usecase := &SeatUseCase{Repo: repo}
+
+	showtime := entity.Showtime{ID: 1, StudioID: 1, Status: entity.ShowtimeScheduled}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(layoutSeats("xoooo"), nil)
+
+	result, err := usecase.SuggestSeats(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "A2", result[0].SeatCode)
+	assert.Equal(t, "A3", result[1].SeatCode)
 	mockSeatRepo.AssertExpectations(t)
 }
 
+func TestSeatUseCase_SuggestSeats_NotEnoughSeats(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	usecase := &SeatUseCase{Repo: repo}
+
+	showtime := entity.Showtime{ID: 1, StudioID: 1, Status: entity.ShowtimeScheduled}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(layoutSeats("xoxo"), nil)
+
+	_, err := usecase.SuggestSeats(context.Background(), 1, 3)
+
+	assert.ErrorIs(t, err, ErrNotEnoughSeats)
+	mockSeatRepo.AssertExpectations(t)
+}
+
+func TestSeatUseCase_SuggestSeats_ShowtimeNotFound(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	usecase := &SeatUseCase{Repo: repo}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 99).Return(entity.Showtime{}, pgx.ErrNoRows)
+
+	_, err := usecase.SuggestSeats(context.Background(), 99, 2)
+
+	assert.ErrorIs(t, err, ErrShowtimeNotFound)
+	mockSeatRepo.AssertExpectations(t)
+}
+
+func TestSeatUseCase_StreamSeats_Success(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	hub := realtime.NewHub()
+	usecase := &SeatUseCase{Repo: repo, SeatHub: hub}
+
+	showtime := entity.Showtime{ID: 1, StudioID: 1, Status: entity.ShowtimeScheduled}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(layoutSeats("oo"), nil)
+
+	seatMap, sub, err := usecase.StreamSeats(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, seatMap.Rows, 1)
+	assert.True(t, hub.HasSubscribers(1))
+
+	hub.Publish(realtime.SeatEvent{ShowtimeID: 1, SeatIDs: []int{2}, State: entity.SeatHeld, Reason: realtime.ReasonBookingCreated})
+	event := <-sub.C
+	assert.Equal(t, []int{2}, event.SeatIDs)
+
+	sub.Close()
+	sub.Close()
+	_, open := <-sub.C
+	assert.False(t, open)
+	assert.False(t, hub.HasSubscribers(1))
+	mockSeatRepo.AssertExpectations(t)
+}
+
+func TestSeatUseCase_StreamSeats_ShowtimeCancelled(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	hub := realtime.NewHub()
+	usecase := &SeatUseCase{Repo: repo, SeatHub: hub}
+
+	showtime := entity.Showtime{ID: 1, Status: entity.ShowtimeCancelled}
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(showtime, nil)
+
+	_, sub, err := usecase.StreamSeats(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrShowtimeCancelled)
+	assert.Nil(t, sub)
+	assert.False(t, hub.HasSubscribers(1))
+}
+
+func TestSeatUseCase_StreamSeats_SlowSubscriberDropped(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo}
+	hub := realtime.NewHub()
+	usecase := &SeatUseCase{Repo: repo, SeatHub: hub}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 1).Return(entity.Showtime{ID: 1}, nil)
+	mockSeatRepo.On("GetSeatsByShowtime", mock.Anything, 1).Return(layoutSeats("o"), nil)
+
+	_, sub, err := usecase.StreamSeats(context.Background(), 1)
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	// Never reading the channel fills its buffer until the hub gives up on it
+	for i := 0; i < 100; i++ {
+		hub.Publish(realtime.SeatEvent{ShowtimeID: 1, Reason: realtime.ReasonBookingCreated})
+	}
+
+	assert.False(t, hub.HasSubscribers(1))
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Less(t, received, 100)
+}
+
 func TestSeatUseCase_GetShowtimesByCinema_Success(t *testing.T) {
 	mockSeatRepo := new(MockSeatRepo)
 	repo := &repository.Repository{Seat: mockSeatRepo}
@@ -492,6 +864,16 @@ func (m *MockAuthRepoForPayment) UpdateUserVerified(ctx context.Context, userID
 	return args.Error(0)
 }
 
+func (m *MockAuthRepoForPayment) UpdateUserRole(ctx context.Context, userID int, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepoForPayment) UpdateUserBirthDate(ctx context.Context, userID int, dateOfBirth time.Time) error {
+	args := m.Called(ctx, userID, dateOfBirth)
+	return args.Error(0)
+}
+
 func (m *MockAuthRepoForPayment) CreateSession(ctx context.Context, session entity.Session) error {
 	args := m.Called(ctx, session)
 	return args.Error(0)
@@ -531,12 +913,19 @@ func TestPaymentUseCase_ProcessPayment_Success(t *testing.T) {
 	mockPaymentRepo := new(MockPaymentRepo)
 	mockBookingRepo := new(MockBookingRepoForPayment)
 	mockAuthRepo := new(MockAuthRepoForPayment)
+	mockLoyaltyRepo := new(MockLoyaltyRepo)
 	repo := &repository.Repository{
 		Payment: mockPaymentRepo,
 		Booking: mockBookingRepo,
 		Auth:    mockAuthRepo,
+		Loyalty: mockLoyaltyRepo,
+	}
+	usecase := &PaymentUseCase{
+		Repo:         repo,
+		EmailService: utils.NewEmailService(),
+		Gateways:     gateway.NewDefaultRegistry(gateway.ModeSuccess),
+		Loyalty:      utils.LoyaltyConfig{AmountPerPoint: 1000, ExpiryDays: 365},
 	}
-	usecase := &PaymentUseCase{Repo: repo, EmailService: utils.NewEmailService()}
 
 	now := time.Now()
 	booking := entity.Booking{
@@ -547,8 +936,9 @@ func TestPaymentUseCase_ProcessPayment_Success(t *testing.T) {
 	}
 
 	method := entity.PaymentMethod{
-		ID:   1,
-		Name: "Credit Card",
+		ID:      1,
+		Name:    "Credit Card",
+		Gateway: gateway.SimulatorName,
 	}
 
 	user := entity.User{
@@ -566,9 +956,11 @@ func TestPaymentUseCase_ProcessPayment_Success(t *testing.T) {
 
 	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
 	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(entity.Payment{}, errors.New("not found")).Once()
 	mockPaymentRepo.On("CreatePayment", mock.Anything, mock.AnythingOfType("entity.Payment")).Return(1, nil)
-	mockBookingRepo.On("UpdateBookingStatus", mock.Anything, 1, "paid").Return(nil)
-	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(payment, nil)
+	mockPaymentRepo.On("SettlePayment", mock.Anything, 1, 1, "completed", mock.AnythingOfType("string")).Return(nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(payment, nil).Once()
+	mockLoyaltyRepo.On("EarnPoints", mock.Anything, 1, 1000.0, 365).Return(100, nil)
 	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(user, nil)
 
 	req := dto.PayRequest{
@@ -582,6 +974,505 @@ func TestPaymentUseCase_ProcessPayment_Success(t *testing.T) {
 	assert.Equal(t, 1, result.ID)
 	assert.Equal(t, "completed", result.Status)
 	assert.Equal(t, "Credit Card", result.PaymentMethod)
+	assert.NotEmpty(t, result.TransactionID)
+	assert.Equal(t, 100, result.PointsEarned)
+	mockBookingRepo.AssertExpectations(t)
+	mockPaymentRepo.AssertExpectations(t)
+	mockLoyaltyRepo.AssertExpectations(t)
+}
+
+// completingGateway completes every charge and answers refunds with refundStatus
+type completingGateway struct {
+	refundStatus string
+}
+
+func (g completingGateway) Charge(ctx context.Context, req gateway.ChargeRequest) (gateway.Result, error) {
+	return gateway.Result{TransactionID: "TX-1", Status: gateway.StatusCompleted}, nil
+}
+
+func (g completingGateway) QueryStatus(ctx context.Context, transactionID string) (gateway.Result, error) {
+	return gateway.Result{TransactionID: transactionID, Status: gateway.StatusCompleted}, nil
+}
+
+func (g completingGateway) Refund(ctx context.Context, transactionID string, amount float64) (gateway.Result, error) {
+	return gateway.Result{TransactionID: transactionID, Status: g.refundStatus}, nil
+}
+
+func TestPaymentUseCase_ProcessPayment_BookingNoLongerPayable(t *testing.T) {
+	booking := entity.Booking{ID: 1, UserID: 1, Status: "pending", TotalAmount: 100000}
+	method := entity.PaymentMethod{ID: 1, Name: "Credit Card", Gateway: "completing"}
+	refund := entity.Refund{ID: 4, PaymentID: 1, BookingID: 1, Amount: 100000, Status: "pending"}
+
+	newUseCase := func(refundStatus string) (*PaymentUseCase, *MockPaymentRepo) {
+		mockPaymentRepo := new(MockPaymentRepo)
+		mockBookingRepo := new(MockBookingRepoForPayment)
+		registry := gateway.NewRegistry()
+		registry.Register("completing", completingGateway{refundStatus: refundStatus})
+
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+		mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(entity.Payment{}, errors.New("not found"))
+		mockPaymentRepo.On("CreatePayment", mock.Anything, mock.AnythingOfType("entity.Payment")).Return(1, nil)
+		mockPaymentRepo.On("SettlePayment", mock.Anything, 1, 1, gateway.StatusCompleted, "TX-1").Return(repository.ErrBookingStatusChanged)
+		mockPaymentRepo.On("GetRefundByBookingID", mock.Anything, 1).Return(refund, nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "processing").Return(nil)
+
+		return &PaymentUseCase{
+			Repo:     &repository.Repository{Payment: mockPaymentRepo, Booking: mockBookingRepo},
+			Gateways: registry,
+		}, mockPaymentRepo
+	}
+
+	t.Run("Refunded", func(t *testing.T) {
+		usecase, mockPaymentRepo := newUseCase(gateway.StatusRefunded)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "completed").Return(nil)
+		mockPaymentRepo.On("UpdatePaymentStatus", mock.Anything, 1, gateway.StatusRefunded).Return(nil)
+
+		_, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+		assert.EqualError(t, err, "booking is no longer payable, payment has been refunded")
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Refund Declined Is Kept For Retry", func(t *testing.T) {
+		usecase, mockPaymentRepo := newUseCase(gateway.StatusFailed)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "failed").Return(nil)
+
+		_, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+		assert.EqualError(t, err, "booking is no longer payable, the refund of your payment is being processed")
+		mockPaymentRepo.AssertExpectations(t)
+		mockPaymentRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, 1, gateway.StatusRefunded)
+	})
+}
+
+func TestPaymentUseCase_ProcessPayment_GatewayPending(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	repo := &repository.Repository{
+		Payment: mockPaymentRepo,
+		Booking: mockBookingRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModePending)}
+
+	booking := entity.Booking{ID: 1, UserID: 1, Status: "pending", TotalAmount: 100000}
+	method := entity.PaymentMethod{ID: 1, Name: "QRIS", Gateway: gateway.SimulatorName}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(entity.Payment{}, errors.New("not found"))
+	mockPaymentRepo.On("CreatePayment", mock.Anything, mock.AnythingOfType("entity.Payment")).Return(1, nil)
+	mockPaymentRepo.On("SettlePayment", mock.Anything, 1, 1, "pending", mock.AnythingOfType("string")).Return(nil)
+
+	result, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", result.Status)
+	assert.Nil(t, result.PaidAt)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_ProcessPayment_GatewayFailed(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	repo := &repository.Repository{
+		Payment: mockPaymentRepo,
+		Booking: mockBookingRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModeFail)}
+
+	booking := entity.Booking{ID: 1, UserID: 1, Status: "pending", TotalAmount: 100000}
+	method := entity.PaymentMethod{ID: 1, Name: "Credit Card", Gateway: gateway.SimulatorName}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(entity.Payment{}, errors.New("not found"))
+	mockPaymentRepo.On("CreatePayment", mock.Anything, mock.AnythingOfType("entity.Payment")).Return(1, nil)
+	mockPaymentRepo.On("SettlePayment", mock.Anything, 1, 1, "failed", mock.AnythingOfType("string")).Return(nil)
+
+	result, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", result.Status)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_ProcessPayment_AlreadyInProgress(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	repo := &repository.Repository{
+		Payment: mockPaymentRepo,
+		Booking: mockBookingRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModeSuccess)}
+
+	booking := entity.Booking{ID: 1, UserID: 1, Status: "pending"}
+	method := entity.PaymentMethod{ID: 1, Name: "QRIS", Gateway: gateway.SimulatorName}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(entity.Payment{ID: 4, Status: "pending"}, nil)
+
+	_, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+	assert.ErrorIs(t, err, ErrPaymentInProgress)
+	assert.Contains(t, err.Error(), "already in progress")
+	mockPaymentRepo.AssertNotCalled(t, "CreatePayment", mock.Anything, mock.Anything)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_ProcessPayment_ConcurrentAttempt(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	repo := &repository.Repository{
+		Payment: mockPaymentRepo,
+		Booking: mockBookingRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModeSuccess)}
+
+	booking := entity.Booking{ID: 1, UserID: 1, Status: "pending"}
+	method := entity.PaymentMethod{ID: 1, Name: "QRIS", Gateway: gateway.SimulatorName}
+
+	// Another request created its pending attempt after this one checked for it
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 1).Return(entity.Payment{}, pgx.ErrNoRows)
+	mockPaymentRepo.On("CreatePayment", mock.Anything, mock.AnythingOfType("entity.Payment")).Return(0, ErrPaymentInProgress)
+
+	_, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+	assert.ErrorIs(t, err, ErrPaymentInProgress)
+	mockPaymentRepo.AssertNotCalled(t, "SettlePayment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_ProcessPayment_PromoPaymentMethodMismatch(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	mockPromotionRepo := new(MockPromotionRepo)
+	repo := &repository.Repository{
+		Payment:   mockPaymentRepo,
+		Booking:   mockBookingRepo,
+		Promotion: mockPromotionRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModeSuccess)}
+
+	promotionID, bankTransferID := 3, 2
+	booking := entity.Booking{ID: 1, UserID: 1, Status: "pending", PromotionID: &promotionID}
+	method := entity.PaymentMethod{ID: 1, Name: "QRIS", Gateway: gateway.SimulatorName}
+
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(booking, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(method, nil)
+	mockPromotionRepo.On("GetPromotionByID", mock.Anything, 3).Return(entity.Promotion{ID: 3, PaymentMethodID: &bankTransferID}, nil)
+
+	_, err := usecase.ProcessPayment(context.Background(), 1, dto.PayRequest{BookingID: 1, PaymentMethod: 1})
+
+	assert.ErrorIs(t, err, ErrPromoNotApplicable)
+	mockPaymentRepo.AssertNotCalled(t, "CreatePayment", mock.Anything, mock.Anything)
+	mockPromotionRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_HandleWebhook_Completed(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockBookingRepo := new(MockBookingRepoForPayment)
+	mockAuthRepo := new(MockAuthRepoForPayment)
+	mockLoyaltyRepo := new(MockLoyaltyRepo)
+	repo := &repository.Repository{
+		Payment: mockPaymentRepo,
+		Booking: mockBookingRepo,
+		Auth:    mockAuthRepo,
+		Loyalty: mockLoyaltyRepo,
+	}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModePending)}
+
+	payload := []byte(`{"transaction_id":"SIM-1","status":"completed"}`)
+	req := dto.PaymentWebhookRequest{TransactionID: "SIM-1", Status: "completed"}
+	method := entity.PaymentMethod{ID: 2, Name: "QRIS", Gateway: gateway.SimulatorName, WebhookSecret: "secret"}
+
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+	mockPaymentRepo.On("ApplyWebhookEvent", mock.Anything, mock.MatchedBy(func(e entity.PaymentWebhookEvent) bool {
+		return e.PaymentMethodID == 2 && e.TransactionID == "SIM-1" && e.Status == "completed" && e.Payload == string(payload)
+	})).Return(entity.Payment{ID: 7, BookingID: 3, Status: "completed", TransactionID: "SIM-1"}, nil)
+	mockLoyaltyRepo.On("EarnPoints", mock.Anything, 3, float64(defaultAmountPerPoint), defaultPointExpiry).Return(100, nil)
+	mockBookingRepo.On("GetBookingByID", mock.Anything, 3).Return(entity.Booking{ID: 3, UserID: 1, TotalAmount: 100000}, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.HandleWebhook(context.Background(), 2, gateway.Sign("secret", payload), payload, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.PaymentID)
+	assert.Equal(t, 3, result.BookingID)
+	assert.Equal(t, "completed", result.Status)
+	mockPaymentRepo.AssertExpectations(t)
 	mockBookingRepo.AssertExpectations(t)
+	mockLoyaltyRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_HandleWebhook_InvalidSignature(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	repo := &repository.Repository{Payment: mockPaymentRepo}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModePending)}
+
+	payload := []byte(`{"transaction_id":"SIM-1","status":"completed"}`)
+	req := dto.PaymentWebhookRequest{TransactionID: "SIM-1", Status: "completed"}
+	method := entity.PaymentMethod{ID: 2, Name: "QRIS", Gateway: gateway.SimulatorName, WebhookSecret: "secret"}
+
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+
+	_, err := usecase.HandleWebhook(context.Background(), 2, gateway.Sign("other-secret", payload), payload, req)
+
+	assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+	mockPaymentRepo.AssertNotCalled(t, "ApplyWebhookEvent", mock.Anything, mock.Anything)
+}
+
+func TestPaymentUseCase_HandleWebhook_MethodWithoutSecret(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	repo := &repository.Repository{Payment: mockPaymentRepo}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModePending)}
+
+	payload := []byte(`{"transaction_id":"SIM-1","status":"completed"}`)
+	req := dto.PaymentWebhookRequest{TransactionID: "SIM-1", Status: "completed"}
+
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(entity.PaymentMethod{ID: 2, Gateway: gateway.SimulatorName}, nil)
+
+	_, err := usecase.HandleWebhook(context.Background(), 2, gateway.Sign("", payload), payload, req)
+
+	assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+}
+
+func TestPaymentUseCase_HandleWebhook_Duplicate(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	repo := &repository.Repository{Payment: mockPaymentRepo}
+	usecase := &PaymentUseCase{Repo: repo, Gateways: gateway.NewDefaultRegistry(gateway.ModePending)}
+
+	payload := []byte(`{"transaction_id":"SIM-1","status":"completed"}`)
+	req := dto.PaymentWebhookRequest{TransactionID: "SIM-1", Status: "completed"}
+	method := entity.PaymentMethod{ID: 2, Name: "QRIS", Gateway: gateway.SimulatorName, WebhookSecret: "secret"}
+
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+	mockPaymentRepo.On("ApplyWebhookEvent", mock.Anything, mock.Anything).Return(entity.Payment{}, repository.ErrWebhookDuplicate)
+
+	_, err := usecase.HandleWebhook(context.Background(), 2, gateway.Sign("secret", payload), payload, req)
+
+	assert.ErrorIs(t, err, ErrWebhookDuplicate)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentUseCase_HandleWebhook_BookingNoLongerPayable(t *testing.T) {
+	registry, _, transactionID := pendingSimulatorCharge(t)
+	mockPaymentRepo := new(MockPaymentRepo)
+	usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo}, Gateways: registry}
+
+	payload := []byte(`{"transaction_id":"` + transactionID + `","status":"completed"}`)
+	req := dto.PaymentWebhookRequest{TransactionID: transactionID, Status: "completed"}
+	method := entity.PaymentMethod{ID: 2, Name: "QRIS", Gateway: gateway.SimulatorName, WebhookSecret: "secret"}
+
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+	mockPaymentRepo.On("ApplyWebhookEvent", mock.Anything, mock.Anything).
+		Return(entity.Payment{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "completed", TransactionID: transactionID}, repository.ErrBookingStatusChanged)
+	mockPaymentRepo.On("GetRefundByBookingID", mock.Anything, 3).Return(entity.Refund{ID: 4, PaymentID: 7, BookingID: 3, Amount: 100000, Status: "pending"}, nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "processing").Return(nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "completed").Return(nil)
+	mockPaymentRepo.On("UpdatePaymentStatus", mock.Anything, 7, "refunded").Return(nil)
+
+	result, err := usecase.HandleWebhook(context.Background(), 2, gateway.Sign("secret", payload), payload, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "refunded", result.Status)
+	assert.Equal(t, "completed", result.RefundStatus)
 	mockPaymentRepo.AssertExpectations(t)
 }
+
+func TestPaymentUseCase_HandleWebhook_RefundDeclined(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepo)
+	registry := gateway.NewRegistry()
+	registry.Register("completing", completingGateway{refundStatus: gateway.StatusFailed})
+	usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo}, Gateways: registry}
+
+	payload := []byte(`{"transaction_id":"TX-1","status":"completed"}`)
+	req := dto.PaymentWebhookRequest{TransactionID: "TX-1", Status: "completed"}
+	method := entity.PaymentMethod{ID: 2, Name: "QRIS", Gateway: "completing", WebhookSecret: "secret"}
+
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+	mockPaymentRepo.On("ApplyWebhookEvent", mock.Anything, mock.Anything).
+		Return(entity.Payment{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "completed", TransactionID: "TX-1"}, repository.ErrBookingStatusChanged)
+	mockPaymentRepo.On("GetRefundByBookingID", mock.Anything, 3).Return(entity.Refund{ID: 4, PaymentID: 7, BookingID: 3, Amount: 100000, Status: "pending"}, nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "processing").Return(nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "failed").Return(nil)
+
+	result, err := usecase.HandleWebhook(context.Background(), 2, gateway.Sign("secret", payload), payload, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", result.Status)
+	assert.Equal(t, "failed", result.RefundStatus)
+	mockPaymentRepo.AssertExpectations(t)
+	mockPaymentRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, 7, "refunded")
+}
+
+// pendingSimulatorCharge registers a simulator that leaves charges pending and returns it
+// with the transaction ID of one such charge
+func pendingSimulatorCharge(t *testing.T) (*gateway.Registry, *gateway.Simulator, string) {
+	sim := gateway.NewSimulator(gateway.ModePending)
+	result, err := sim.Charge(context.Background(), gateway.ChargeRequest{PaymentID: 7, BookingID: 3, Amount: 100000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := gateway.NewRegistry()
+	registry.Register(gateway.SimulatorName, sim)
+	return registry, sim, result.TransactionID
+}
+
+func TestPaymentUseCase_ReconcilePendingPayments(t *testing.T) {
+	method := entity.PaymentMethod{ID: 2, Name: "QRIS", Gateway: gateway.SimulatorName}
+
+	t.Run("Success - Completed Charge Settles Booking", func(t *testing.T) {
+		registry, sim, transactionID := pendingSimulatorCharge(t)
+		sim.Resolve(transactionID, gateway.StatusCompleted)
+
+		mockPaymentRepo := new(MockPaymentRepo)
+		mockBookingRepo := new(MockBookingRepoForPayment)
+		mockAuthRepo := new(MockAuthRepoForPayment)
+		mockLoyaltyRepo := new(MockLoyaltyRepo)
+		repo := &repository.Repository{Payment: mockPaymentRepo, Booking: mockBookingRepo, Auth: mockAuthRepo, Loyalty: mockLoyaltyRepo}
+		usecase := &PaymentUseCase{Repo: repo, Gateways: registry}
+
+		mockPaymentRepo.On("GetFailedRefunds", mock.Anything).Return([]entity.Refund{}, nil)
+		mockPaymentRepo.On("GetStalePendingPayments", mock.Anything).Return([]entity.Payment{
+			{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "pending", TransactionID: transactionID},
+		}, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 3).Return(entity.Booking{ID: 3, UserID: 1, Status: "pending", TotalAmount: 100000}, nil)
+		mockPaymentRepo.On("SettlePayment", mock.Anything, 7, 3, gateway.StatusCompleted, transactionID).Return(nil)
+		mockLoyaltyRepo.On("EarnPoints", mock.Anything, 3, float64(defaultAmountPerPoint), defaultPointExpiry).Return(100, nil)
+		mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+		settled, err := usecase.ReconcilePendingPayments(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, settled)
+		mockPaymentRepo.AssertExpectations(t)
+		mockLoyaltyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Still Pending Is Left Alone", func(t *testing.T) {
+		registry, _, transactionID := pendingSimulatorCharge(t)
+
+		mockPaymentRepo := new(MockPaymentRepo)
+		usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo}, Gateways: registry}
+
+		mockPaymentRepo.On("GetFailedRefunds", mock.Anything).Return([]entity.Refund{}, nil)
+		mockPaymentRepo.On("GetStalePendingPayments", mock.Anything).Return([]entity.Payment{
+			{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "pending", TransactionID: transactionID},
+		}, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+
+		settled, err := usecase.ReconcilePendingPayments(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, settled)
+		mockPaymentRepo.AssertNotCalled(t, "SettlePayment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Failed Charge Is Recorded", func(t *testing.T) {
+		registry, sim, transactionID := pendingSimulatorCharge(t)
+		sim.Resolve(transactionID, gateway.StatusFailed)
+
+		mockPaymentRepo := new(MockPaymentRepo)
+		mockBookingRepo := new(MockBookingRepoForPayment)
+		usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo, Booking: mockBookingRepo}, Gateways: registry}
+
+		mockPaymentRepo.On("GetFailedRefunds", mock.Anything).Return([]entity.Refund{}, nil)
+		mockPaymentRepo.On("GetStalePendingPayments", mock.Anything).Return([]entity.Payment{
+			{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "pending", TransactionID: transactionID},
+		}, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 3).Return(entity.Booking{ID: 3, UserID: 1, Status: "pending"}, nil)
+		mockPaymentRepo.On("SettlePayment", mock.Anything, 7, 3, gateway.StatusFailed, transactionID).Return(nil)
+
+		settled, err := usecase.ReconcilePendingPayments(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, settled)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Completed After Booking Expired Is Refunded", func(t *testing.T) {
+		registry, sim, transactionID := pendingSimulatorCharge(t)
+		sim.Resolve(transactionID, gateway.StatusCompleted)
+
+		mockPaymentRepo := new(MockPaymentRepo)
+		mockBookingRepo := new(MockBookingRepoForPayment)
+		usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo, Booking: mockBookingRepo}, Gateways: registry}
+
+		mockPaymentRepo.On("GetFailedRefunds", mock.Anything).Return([]entity.Refund{}, nil)
+		mockPaymentRepo.On("GetStalePendingPayments", mock.Anything).Return([]entity.Payment{
+			{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "pending", TransactionID: transactionID},
+		}, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 3).Return(entity.Booking{ID: 3, UserID: 1, Status: "expired", TotalAmount: 100000}, nil)
+		mockPaymentRepo.On("SettlePayment", mock.Anything, 7, 3, gateway.StatusCompleted, transactionID).Return(repository.ErrBookingStatusChanged)
+		mockPaymentRepo.On("GetRefundByBookingID", mock.Anything, 3).Return(entity.Refund{ID: 4, PaymentID: 7, BookingID: 3, Amount: 100000, Status: "pending"}, nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "processing").Return(nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "completed").Return(nil)
+		mockPaymentRepo.On("UpdatePaymentStatus", mock.Anything, 7, gateway.StatusRefunded).Return(nil)
+
+		settled, err := usecase.ReconcilePendingPayments(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, settled)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Declined Refund Is Kept For Retry", func(t *testing.T) {
+		sim := gateway.NewSimulator(gateway.ModeFail)
+		charge, _ := sim.Charge(context.Background(), gateway.ChargeRequest{PaymentID: 7, BookingID: 3, Amount: 100000})
+		sim.Resolve(charge.TransactionID, gateway.StatusCompleted)
+		registry := gateway.NewRegistry()
+		registry.Register(gateway.SimulatorName, sim)
+
+		mockPaymentRepo := new(MockPaymentRepo)
+		mockBookingRepo := new(MockBookingRepoForPayment)
+		usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo, Booking: mockBookingRepo}, Gateways: registry}
+
+		mockPaymentRepo.On("GetFailedRefunds", mock.Anything).Return([]entity.Refund{}, nil)
+		mockPaymentRepo.On("GetStalePendingPayments", mock.Anything).Return([]entity.Payment{
+			{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "pending", TransactionID: charge.TransactionID},
+		}, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 3).Return(entity.Booking{ID: 3, UserID: 1, Status: "expired", TotalAmount: 100000}, nil)
+		mockPaymentRepo.On("SettlePayment", mock.Anything, 7, 3, gateway.StatusCompleted, charge.TransactionID).Return(repository.ErrBookingStatusChanged)
+		mockPaymentRepo.On("GetRefundByBookingID", mock.Anything, 3).Return(entity.Refund{ID: 4, PaymentID: 7, BookingID: 3, Amount: 100000, Status: "pending"}, nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "processing").Return(nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "failed").Return(nil)
+
+		_, err := usecase.ReconcilePendingPayments(context.Background())
+
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertExpectations(t)
+		mockPaymentRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, 7, gateway.StatusRefunded)
+	})
+
+	t.Run("Success - Failed Refund Is Sent Again", func(t *testing.T) {
+		registry, sim, transactionID := pendingSimulatorCharge(t)
+		sim.Resolve(transactionID, gateway.StatusCompleted)
+
+		mockPaymentRepo := new(MockPaymentRepo)
+		usecase := &PaymentUseCase{Repo: &repository.Repository{Payment: mockPaymentRepo}, Gateways: registry}
+
+		mockPaymentRepo.On("GetFailedRefunds", mock.Anything).Return([]entity.Refund{
+			{ID: 4, PaymentID: 7, BookingID: 3, Amount: 100000, Status: "failed"},
+		}, nil)
+		mockPaymentRepo.On("GetPaymentByID", mock.Anything, 7).Return(entity.Payment{ID: 7, BookingID: 3, PaymentMethodID: 2, Status: "completed", TransactionID: transactionID}, nil)
+		mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 2).Return(method, nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "processing").Return(nil)
+		mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 4, "completed").Return(nil)
+		mockPaymentRepo.On("UpdatePaymentStatus", mock.Anything, 7, gateway.StatusRefunded).Return(nil)
+		mockPaymentRepo.On("GetStalePendingPayments", mock.Anything).Return([]entity.Payment{}, nil)
+
+		settled, err := usecase.ReconcilePendingPayments(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, settled)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PricingUseCaseInterface interface {
+	GetPricingRules(ctx context.Context) ([]dto.PricingRuleResponse, error)
+	CreatePricingRule(ctx context.Context, req dto.PricingRuleRequest) (dto.PricingRuleResponse, error)
+	UpdatePricingRule(ctx context.Context, id int, req dto.PricingRuleRequest) (dto.PricingRuleResponse, error)
+	DeletePricingRule(ctx context.Context, id int) error
+}
+
+type PricingUseCase struct {
+	Repo *repository.Repository
+}
+
+func NewPricingUseCase(repo *repository.Repository) PricingUseCaseInterface {
+	return &PricingUseCase{Repo: repo}
+}
+
+// GetPricingRules lists all pricing rules in the order they are applied
+func (u *PricingUseCase) GetPricingRules(ctx context.Context) ([]dto.PricingRuleResponse, error) {
+	rules, err := u.Repo.Pricing.GetPricingRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.PricingRuleResponse{}
+	for _, r := range rules {
+		response = append(response, toPricingRuleResponse(r))
+	}
+	return response, nil
+}
+
+// CreatePricingRule adds a pricing rule, which applies to seats priced from then on
+func (u *PricingUseCase) CreatePricingRule(ctx context.Context, req dto.PricingRuleRequest) (dto.PricingRuleResponse, error) {
+	rule, err := pricingRuleFromRequest(req)
+	if err != nil {
+		return dto.PricingRuleResponse{}, err
+	}
+
+	if err := u.checkRuleCinema(ctx, rule); err != nil {
+		return dto.PricingRuleResponse{}, err
+	}
+
+	created, err := u.Repo.Pricing.CreatePricingRule(ctx, rule)
+	if err != nil {
+		return dto.PricingRuleResponse{}, err
+	}
+
+	return toPricingRuleResponse(created), nil
+}
+
+// UpdatePricingRule replaces a pricing rule; seats already booked keep their price
+func (u *PricingUseCase) UpdatePricingRule(ctx context.Context, id int, req dto.PricingRuleRequest) (dto.PricingRuleResponse, error) {
+	rule, err := pricingRuleFromRequest(req)
+	if err != nil {
+		return dto.PricingRuleResponse{}, err
+	}
+	rule.ID = id
+	if err := u.checkRuleCinema(ctx, rule); err != nil {
+		return dto.PricingRuleResponse{}, err
+	}
+
+	updated, err := u.Repo.Pricing.UpdatePricingRule(ctx, rule)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.PricingRuleResponse{}, ErrPricingRuleNotFound
+	}
+	if err != nil {
+		return dto.PricingRuleResponse{}, err
+	}
+
+	return toPricingRuleResponse(updated), nil
+}
+
+// DeletePricingRule removes a pricing rule
+func (u *PricingUseCase) DeletePricingRule(ctx context.Context, id int) error {
+	err := u.Repo.Pricing.DeletePricingRule(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPricingRuleNotFound
+	}
+	return err
+}
+
+// checkRuleCinema makes sure a rule scoped to a cinema names one that exists
+func (u *PricingUseCase) checkRuleCinema(ctx context.Context, rule entity.PricingRule) error {
+	if rule.CinemaID == nil {
+		return nil
+	}
+	if _, err := u.Repo.Cinema.GetCinemaByID(ctx, *rule.CinemaID); err != nil {
+		return ErrCinemaNotFound
+	}
+	return nil
+}
+
+// pricingRuleFromRequest maps a create or update request onto a pricing rule.
+// Rules are active unless the request says otherwise.
+func pricingRuleFromRequest(req dto.PricingRuleRequest) (entity.PricingRule, error) {
+	if req.TimeFrom != "" && req.TimeUntil != "" && req.TimeUntil <= req.TimeFrom {
+		return entity.PricingRule{}, ErrInvalidPricingRule
+	}
+	if req.AdjustmentType == entity.AdjustmentPercentage && req.AdjustmentValue <= -100 {
+		return entity.PricingRule{}, ErrInvalidPricingRule
+	}
+
+	rule := entity.PricingRule{
+		Name:            strings.TrimSpace(req.Name),
+		CinemaID:        req.CinemaID,
+		DaysOfWeek:      req.DaysOfWeek,
+		Dates:           req.Dates,
+		TimeFrom:        req.TimeFrom,
+		TimeUntil:       req.TimeUntil,
+		MinOccupancy:    req.MinOccupancy,
+		AdjustmentType:  req.AdjustmentType,
+		AdjustmentValue: req.AdjustmentValue,
+		Priority:        req.Priority,
+		IsActive:        req.IsActive == nil || *req.IsActive,
+	}
+	if rule.DaysOfWeek == nil {
+		rule.DaysOfWeek = []int{}
+	}
+	if rule.Dates == nil {
+		rule.Dates = []string{}
+	}
+
+	return rule, nil
+}
+
+func toPricingRuleResponse(r entity.PricingRule) dto.PricingRuleResponse {
+	return dto.PricingRuleResponse{
+		ID:              r.ID,
+		Name:            r.Name,
+		CinemaID:        r.CinemaID,
+		DaysOfWeek:      r.DaysOfWeek,
+		Dates:           r.Dates,
+		TimeFrom:        r.TimeFrom,
+		TimeUntil:       r.TimeUntil,
+		MinOccupancy:    r.MinOccupancy,
+		AdjustmentType:  r.AdjustmentType,
+		AdjustmentValue: r.AdjustmentValue,
+		Priority:        r.Priority,
+		IsActive:        r.IsActive,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Pricing
+// =====================
+
+type MockPricingRepo struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepo) GetPricingRules(ctx context.Context) ([]entity.PricingRule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) CreatePricingRule(ctx context.Context, rule entity.PricingRule) (entity.PricingRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(entity.PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) UpdatePricingRule(ctx context.Context, rule entity.PricingRule) (entity.PricingRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(entity.PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) DeletePricingRule(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// =====================
+// Pricing UseCase Tests
+// =====================
+
+func newPricingUseCase() (*PricingUseCase, *MockPricingRepo) {
+	mockPricingRepo := new(MockPricingRepo)
+	return &PricingUseCase{Repo: &repository.Repository{Pricing: mockPricingRepo}}, mockPricingRepo
+}
+
+func TestPricingUseCase_CreatePricingRule(t *testing.T) {
+	t.Run("Success - Defaults To Active", func(t *testing.T) {
+		usecase, mockPricingRepo := newPricingUseCase()
+		expected := entity.PricingRule{
+			Name: "Weekend", DaysOfWeek: []int{0, 6}, Dates: []string{"2026-08-17"},
+			AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: 20, IsActive: true,
+		}
+		created := expected
+		created.ID = 1
+		mockPricingRepo.On("CreatePricingRule", mock.Anything, expected).Return(created, nil)
+
+		result, err := usecase.CreatePricingRule(context.Background(), dto.PricingRuleRequest{
+			Name: " Weekend ", DaysOfWeek: []int{0, 6}, Dates: []string{"2026-08-17"},
+			AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: 20,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ID)
+		assert.True(t, result.IsActive)
+		mockPricingRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Time Window Reversed", func(t *testing.T) {
+		usecase, mockPricingRepo := newPricingUseCase()
+
+		_, err := usecase.CreatePricingRule(context.Background(), dto.PricingRuleRequest{
+			Name: "Matinee", TimeFrom: "17:00", TimeUntil: "10:00",
+			AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: -25,
+		})
+		assert.ErrorIs(t, err, ErrInvalidPricingRule)
+		mockPricingRepo.AssertNotCalled(t, "CreatePricingRule", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Percentage Discount Of 100", func(t *testing.T) {
+		usecase, _ := newPricingUseCase()
+
+		_, err := usecase.CreatePricingRule(context.Background(), dto.PricingRuleRequest{
+			Name: "Free", AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: -100,
+		})
+		assert.ErrorIs(t, err, ErrInvalidPricingRule)
+	})
+
+	t.Run("Error - Cinema Not Found", func(t *testing.T) {
+		usecase, mockPricingRepo := newPricingUseCase()
+		mockCinemaRepo := new(MockCinemaRepo)
+		usecase.Repo.Cinema = mockCinemaRepo
+		mockCinemaRepo.On("GetCinemaByID", mock.Anything, 99).Return(entity.Cinema{}, pgx.ErrNoRows)
+
+		cinemaID := 99
+		_, err := usecase.CreatePricingRule(context.Background(), dto.PricingRuleRequest{
+			Name: "Weekend", CinemaID: &cinemaID, DaysOfWeek: []int{0, 6},
+			AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: 20,
+		})
+		assert.ErrorIs(t, err, ErrCinemaNotFound)
+		mockPricingRepo.AssertNotCalled(t, "CreatePricingRule", mock.Anything, mock.Anything)
+		mockCinemaRepo.AssertExpectations(t)
+	})
+}
+
+func TestPricingUseCase_UpdatePricingRule_CinemaNotFound(t *testing.T) {
+	usecase, mockPricingRepo := newPricingUseCase()
+	mockCinemaRepo := new(MockCinemaRepo)
+	usecase.Repo.Cinema = mockCinemaRepo
+	mockCinemaRepo.On("GetCinemaByID", mock.Anything, 99).Return(entity.Cinema{}, pgx.ErrNoRows)
+
+	cinemaID := 99
+	_, err := usecase.UpdatePricingRule(context.Background(), 1, dto.PricingRuleRequest{
+		Name: "Surge", CinemaID: &cinemaID, MinOccupancy: 80, AdjustmentType: entity.AdjustmentFixed, AdjustmentValue: 5000,
+	})
+
+	assert.ErrorIs(t, err, ErrCinemaNotFound)
+	mockPricingRepo.AssertNotCalled(t, "UpdatePricingRule", mock.Anything, mock.Anything)
+	mockCinemaRepo.AssertExpectations(t)
+}
+
+func TestPricingUseCase_UpdatePricingRule_NotFound(t *testing.T) {
+	usecase, mockPricingRepo := newPricingUseCase()
+	inactive := false
+	mockPricingRepo.On("UpdatePricingRule", mock.Anything, mock.MatchedBy(func(r entity.PricingRule) bool {
+		return r.ID == 99 && !r.IsActive
+	})).Return(entity.PricingRule{}, pgx.ErrNoRows)
+
+	_, err := usecase.UpdatePricingRule(context.Background(), 99, dto.PricingRuleRequest{
+		Name: "Surge", MinOccupancy: 80, AdjustmentType: entity.AdjustmentFixed, AdjustmentValue: 5000, IsActive: &inactive,
+	})
+
+	assert.ErrorIs(t, err, ErrPricingRuleNotFound)
+	mockPricingRepo.AssertExpectations(t)
+}
+
+func TestPricingUseCase_DeletePricingRule_NotFound(t *testing.T) {
+	usecase, mockPricingRepo := newPricingUseCase()
+	mockPricingRepo.On("DeletePricingRule", mock.Anything, 99).Return(pgx.ErrNoRows)
+
+	err := usecase.DeletePricingRule(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrPricingRuleNotFound)
+}
+
+func TestResolvePrice(t *testing.T) {
+	cinemaID, otherCinemaID := 1, 2
+	weekend := entity.PricingRule{
+		DaysOfWeek: []int{0, 6}, Dates: []string{"2026-08-17"},
+		AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: 20,
+	}
+	matinee := entity.PricingRule{TimeUntil: "17:00:00", AdjustmentType: entity.AdjustmentPercentage, AdjustmentValue: -25}
+	surge := entity.PricingRule{MinOccupancy: 80, AdjustmentType: entity.AdjustmentFixed, AdjustmentValue: 5000}
+	otherCinema := entity.PricingRule{CinemaID: &otherCinemaID, AdjustmentType: entity.AdjustmentFixed, AdjustmentValue: 10000}
+	rules := []entity.PricingRule{weekend, matinee, surge, otherCinema}
+
+	tests := []struct {
+		name     string
+		context  entity.PricingContext
+		expected float64
+	}{
+		// 2026-01-14 is a Wednesday and 2026-01-17 a Saturday
+		{"weekday evening", entity.PricingContext{CinemaID: cinemaID, ShowDate: "2026-01-14", ShowTime: "19:00:00"}, 50000},
+		{"weekend evening", entity.PricingContext{CinemaID: cinemaID, ShowDate: "2026-01-17", ShowTime: "19:00:00"}, 60000},
+		{"holiday on a weekday", entity.PricingContext{CinemaID: cinemaID, ShowDate: "2026-08-17", ShowTime: "19:00:00"}, 60000},
+		{"weekday matinee", entity.PricingContext{CinemaID: cinemaID, ShowDate: "2026-01-14", ShowTime: "13:00:00"}, 37500},
+		{"weekend matinee compounds", entity.PricingContext{CinemaID: cinemaID, ShowDate: "2026-01-17", ShowTime: "13:00:00"}, 45000},
+		{"surge at threshold", entity.PricingContext{CinemaID: cinemaID, ShowDate: "2026-01-14", ShowTime: "19:00:00", Occupancy: 80}, 55000},
+		{"scoped to its cinema", entity.PricingContext{CinemaID: otherCinemaID, ShowDate: "2026-01-14", ShowTime: "19:00:00"}, 60000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, entity.ResolvePrice(50000, rules, tt.context))
+		})
+	}
+
+	t.Run("never negative", func(t *testing.T) {
+		discount := []entity.PricingRule{{AdjustmentType: entity.AdjustmentFixed, AdjustmentValue: -80000}}
+		assert.Equal(t, 0.0, entity.ResolvePrice(50000, discount, entity.PricingContext{ShowDate: "2026-01-14"}))
+	})
+}
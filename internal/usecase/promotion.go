@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PromotionUseCaseInterface interface {
+	GetPromotions(ctx context.Context, page, limit int) ([]dto.PromotionResponse, dto.Pagination, error)
+	CreatePromotion(ctx context.Context, req dto.PromotionRequest) (dto.PromotionResponse, error)
+	UpdatePromotion(ctx context.Context, id int, req dto.PromotionRequest) (dto.PromotionResponse, error)
+}
+
+type PromotionUseCase struct {
+	Repo *repository.Repository
+}
+
+func NewPromotionUseCase(repo *repository.Repository) PromotionUseCaseInterface {
+	return &PromotionUseCase{Repo: repo}
+}
+
+// GetPromotions lists all promotions with how often each is in use
+func (u *PromotionUseCase) GetPromotions(ctx context.Context, page, limit int) ([]dto.PromotionResponse, dto.Pagination, error) {
+	promotions, pagination, err := u.Repo.Promotion.GetPromotions(ctx, page, limit)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	response := []dto.PromotionResponse{}
+	for _, p := range promotions {
+		response = append(response, toPromotionResponse(p))
+	}
+
+	return response, pagination, nil
+}
+
+// CreatePromotion adds a promo code
+func (u *PromotionUseCase) CreatePromotion(ctx context.Context, req dto.PromotionRequest) (dto.PromotionResponse, error) {
+	promotion, err := promotionFromRequest(req)
+	if err != nil {
+		return dto.PromotionResponse{}, err
+	}
+
+	created, err := u.Repo.Promotion.CreatePromotion(ctx, promotion)
+	if err != nil {
+		return dto.PromotionResponse{}, err
+	}
+
+	return toPromotionResponse(created), nil
+}
+
+// UpdatePromotion replaces the settings of a promo code
+func (u *PromotionUseCase) UpdatePromotion(ctx context.Context, id int, req dto.PromotionRequest) (dto.PromotionResponse, error) {
+	promotion, err := promotionFromRequest(req)
+	if err != nil {
+		return dto.PromotionResponse{}, err
+	}
+	promotion.ID = id
+
+	updated, err := u.Repo.Promotion.UpdatePromotion(ctx, promotion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.PromotionResponse{}, ErrPromoNotFound
+	}
+	if err != nil {
+		return dto.PromotionResponse{}, err
+	}
+
+	return toPromotionResponse(updated), nil
+}
+
+// promotionFromRequest maps a create or update request onto a promotion entity.
+// Promotions are active unless the request says otherwise.
+func promotionFromRequest(req dto.PromotionRequest) (entity.Promotion, error) {
+	validFrom, err := time.Parse(time.RFC3339, req.ValidFrom)
+	if err != nil {
+		return entity.Promotion{}, ErrInvalidPromotion
+	}
+	validUntil, err := time.Parse(time.RFC3339, req.ValidUntil)
+	if err != nil {
+		return entity.Promotion{}, ErrInvalidPromotion
+	}
+	if !validUntil.After(validFrom) {
+		return entity.Promotion{}, ErrInvalidPromotion
+	}
+	if req.DiscountType == entity.PromotionPercentage && req.DiscountValue > 100 {
+		return entity.Promotion{}, ErrInvalidPromotion
+	}
+
+	promotion := entity.Promotion{
+		Code:            strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description:     req.Description,
+		DiscountType:    req.DiscountType,
+		DiscountValue:   req.DiscountValue,
+		MaxDiscount:     req.MaxDiscount,
+		MovieID:         req.MovieID,
+		CinemaID:        req.CinemaID,
+		PaymentMethodID: req.PaymentMethodID,
+		DaysOfWeek:      req.DaysOfWeek,
+		MinSeats:        req.MinSeats,
+		UsageLimit:      req.UsageLimit,
+		PerUserLimit:    req.PerUserLimit,
+		ValidFrom:       validFrom,
+		ValidUntil:      validUntil,
+		IsActive:        req.IsActive == nil || *req.IsActive,
+	}
+	if promotion.DaysOfWeek == nil {
+		promotion.DaysOfWeek = []int{}
+	}
+
+	return promotion, nil
+}
+
+func toPromotionResponse(p entity.Promotion) dto.PromotionResponse {
+	return dto.PromotionResponse{
+		ID:              p.ID,
+		Code:            p.Code,
+		Description:     p.Description,
+		DiscountType:    p.DiscountType,
+		DiscountValue:   p.DiscountValue,
+		MaxDiscount:     p.MaxDiscount,
+		MovieID:         p.MovieID,
+		CinemaID:        p.CinemaID,
+		PaymentMethodID: p.PaymentMethodID,
+		DaysOfWeek:      p.DaysOfWeek,
+		MinSeats:        p.MinSeats,
+		UsageLimit:      p.UsageLimit,
+		PerUserLimit:    p.PerUserLimit,
+		UsedCount:       p.UsedCount,
+		ValidFrom:       p.ValidFrom,
+		ValidUntil:      p.ValidUntil,
+		IsActive:        p.IsActive,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Promotion
+// =====================
+
+type MockPromotionRepo struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepo) GetPromotionByID(ctx context.Context, id int) (entity.Promotion, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepo) GetPromotionByCode(ctx context.Context, code string) (entity.Promotion, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(entity.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepo) GetPromotions(ctx context.Context, page, limit int) ([]entity.Promotion, dto.Pagination, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]entity.Promotion), args.Get(1).(dto.Pagination), args.Error(2)
+}
+
+func (m *MockPromotionRepo) CreatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	args := m.Called(ctx, promotion)
+	return args.Get(0).(entity.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepo) UpdatePromotion(ctx context.Context, promotion entity.Promotion) (entity.Promotion, error) {
+	args := m.Called(ctx, promotion)
+	return args.Get(0).(entity.Promotion), args.Error(1)
+}
+
+// =====================
+// Promotion UseCase Tests
+// =====================
+
+func newPromotionUseCase() (*PromotionUseCase, *MockPromotionRepo) {
+	mockPromotionRepo := new(MockPromotionRepo)
+	return &PromotionUseCase{Repo: &repository.Repository{Promotion: mockPromotionRepo}}, mockPromotionRepo
+}
+
+func TestPromotionUseCase_CreatePromotion(t *testing.T) {
+	validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	req := dto.PromotionRequest{
+		Code:          " hemat10 ",
+		DiscountType:  entity.PromotionPercentage,
+		DiscountValue: 10,
+		MaxDiscount:   20000,
+		ValidFrom:     "2026-01-01T00:00:00Z",
+		ValidUntil:    "2026-02-01T00:00:00Z",
+	}
+
+	t.Run("Success - Defaults To Active", func(t *testing.T) {
+		usecase, mockPromotionRepo := newPromotionUseCase()
+		expected := entity.Promotion{
+			Code: "HEMAT10", DiscountType: entity.PromotionPercentage, DiscountValue: 10, MaxDiscount: 20000,
+			DaysOfWeek: []int{}, ValidFrom: validFrom, ValidUntil: validUntil, IsActive: true,
+		}
+		created := expected
+		created.ID = 3
+		mockPromotionRepo.On("CreatePromotion", mock.Anything, expected).Return(created, nil)
+
+		result, err := usecase.CreatePromotion(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ID)
+		assert.Equal(t, "HEMAT10", result.Code)
+		assert.True(t, result.IsActive)
+		mockPromotionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Window Ends Before It Starts", func(t *testing.T) {
+		usecase, mockPromotionRepo := newPromotionUseCase()
+		invalid := req
+		invalid.ValidUntil = "2025-12-31T00:00:00Z"
+
+		_, err := usecase.CreatePromotion(context.Background(), invalid)
+		assert.ErrorIs(t, err, ErrInvalidPromotion)
+		mockPromotionRepo.AssertNotCalled(t, "CreatePromotion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Percentage Above 100", func(t *testing.T) {
+		usecase, _ := newPromotionUseCase()
+		invalid := req
+		invalid.DiscountValue = 150
+
+		_, err := usecase.CreatePromotion(context.Background(), invalid)
+		assert.ErrorIs(t, err, ErrInvalidPromotion)
+	})
+
+	t.Run("Error - Code Exists", func(t *testing.T) {
+		usecase, mockPromotionRepo := newPromotionUseCase()
+		mockPromotionRepo.On("CreatePromotion", mock.Anything, mock.AnythingOfType("entity.Promotion")).
+			Return(entity.Promotion{}, repository.ErrPromoCodeExists)
+
+		_, err := usecase.CreatePromotion(context.Background(), req)
+		assert.ErrorIs(t, err, ErrPromoCodeExists)
+	})
+}
+
+func TestPromotionUseCase_UpdatePromotion_NotFound(t *testing.T) {
+	usecase, mockPromotionRepo := newPromotionUseCase()
+	inactive := false
+	mockPromotionRepo.On("UpdatePromotion", mock.Anything, mock.MatchedBy(func(p entity.Promotion) bool {
+		return p.ID == 99 && !p.IsActive
+	})).Return(entity.Promotion{}, pgx.ErrNoRows)
+
+	_, err := usecase.UpdatePromotion(context.Background(), 99, dto.PromotionRequest{
+		Code:          "HEMAT",
+		DiscountType:  entity.PromotionFixed,
+		DiscountValue: 15000,
+		ValidFrom:     "2026-01-01T00:00:00+07:00",
+		ValidUntil:    "2026-02-01T00:00:00+07:00",
+		IsActive:      &inactive,
+	})
+
+	assert.ErrorIs(t, err, ErrPromoNotFound)
+	mockPromotionRepo.AssertExpectations(t)
+}
+
+func TestPromotion_Discount(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion entity.Promotion
+		subtotal  float64
+		expected  float64
+	}{
+		{"percentage", entity.Promotion{DiscountType: entity.PromotionPercentage, DiscountValue: 10}, 125000, 12500},
+		{"percentage capped", entity.Promotion{DiscountType: entity.PromotionPercentage, DiscountValue: 50, MaxDiscount: 30000}, 125000, 30000},
+		{"percentage rounded to cents", entity.Promotion{DiscountType: entity.PromotionPercentage, DiscountValue: 15}, 33.33, 5},
+		{"fixed", entity.Promotion{DiscountType: entity.PromotionFixed, DiscountValue: 20000}, 125000, 20000},
+		{"fixed above subtotal", entity.Promotion{DiscountType: entity.PromotionFixed, DiscountValue: 80000}, 50000, 50000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.promotion.Discount(tt.subtotal))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReviewUseCaseInterface interface {
+	GetMovieReviews(ctx context.Context, movieID, page, limit int) ([]dto.ReviewResponse, dto.Pagination, error)
+	CreateReview(ctx context.Context, userID, movieID int, req dto.ReviewRequest) (dto.ReviewResponse, error)
+	UpdateReview(ctx context.Context, userID, reviewID int, req dto.ReviewRequest) (dto.ReviewResponse, error)
+	DeleteReview(ctx context.Context, userID, reviewID int) error
+}
+
+type ReviewUseCase struct {
+	Repo *repository.Repository
+}
+
+func NewReviewUseCase(repo *repository.Repository) ReviewUseCaseInterface {
+	return &ReviewUseCase{Repo: repo}
+}
+
+// GetMovieReviews lists the reviews of a movie, newest first
+func (u *ReviewUseCase) GetMovieReviews(ctx context.Context, movieID, page, limit int) ([]dto.ReviewResponse, dto.Pagination, error) {
+	_, err := u.Repo.Movie.GetMovieByID(ctx, movieID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, dto.Pagination{}, ErrMovieNotFound
+	}
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	reviews, pagination, err := u.Repo.Review.GetReviewsByMovie(ctx, movieID, page, limit)
+	if err != nil {
+		return nil, dto.Pagination{}, err
+	}
+
+	response := make([]dto.ReviewResponse, 0, len(reviews))
+	for _, rv := range reviews {
+		response = append(response, toReviewResponse(rv))
+	}
+
+	return response, pagination, nil
+}
+
+// CreateReview posts a user's review of a movie they watched
+func (u *ReviewUseCase) CreateReview(ctx context.Context, userID, movieID int, req dto.ReviewRequest) (dto.ReviewResponse, error) {
+	_, err := u.Repo.Movie.GetMovieByID(ctx, movieID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.ReviewResponse{}, ErrMovieNotFound
+	}
+	if err != nil {
+		return dto.ReviewResponse{}, err
+	}
+
+	watched, err := u.Repo.Review.HasWatchedMovie(ctx, userID, movieID)
+	if err != nil {
+		return dto.ReviewResponse{}, err
+	}
+	if !watched {
+		return dto.ReviewResponse{}, ErrReviewNotAllowed
+	}
+
+	created, err := u.Repo.Review.CreateReview(ctx, entity.Review{
+		MovieID: movieID,
+		UserID:  userID,
+		Rating:  req.Rating,
+		Comment: strings.TrimSpace(req.Comment),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.ReviewResponse{}, ErrMovieNotFound
+	}
+	if err != nil {
+		return dto.ReviewResponse{}, err
+	}
+
+	return toReviewResponse(created), nil
+}
+
+// UpdateReview changes the rating and comment of a user's own review
+func (u *ReviewUseCase) UpdateReview(ctx context.Context, userID, reviewID int, req dto.ReviewRequest) (dto.ReviewResponse, error) {
+	review, err := u.ownReview(ctx, userID, reviewID)
+	if err != nil {
+		return dto.ReviewResponse{}, err
+	}
+
+	review.Rating = req.Rating
+	review.Comment = strings.TrimSpace(req.Comment)
+	updated, err := u.Repo.Review.UpdateReview(ctx, review)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.ReviewResponse{}, ErrReviewNotFound
+	}
+	if err != nil {
+		return dto.ReviewResponse{}, err
+	}
+
+	return toReviewResponse(updated), nil
+}
+
+// DeleteReview removes a user's own review
+func (u *ReviewUseCase) DeleteReview(ctx context.Context, userID, reviewID int) error {
+	review, err := u.ownReview(ctx, userID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	err = u.Repo.Review.DeleteReview(ctx, review)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrReviewNotFound
+	}
+	return err
+}
+
+// ownReview loads a review that the user wrote
+func (u *ReviewUseCase) ownReview(ctx context.Context, userID, reviewID int) (entity.Review, error) {
+	review, err := u.Repo.Review.GetReviewByID(ctx, reviewID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Review{}, ErrReviewNotFound
+	}
+	if err != nil {
+		return entity.Review{}, err
+	}
+	if review.UserID != userID {
+		return entity.Review{}, ErrReviewNotOwned
+	}
+	return review, nil
+}
+
+func toReviewResponse(rv entity.Review) dto.ReviewResponse {
+	return dto.ReviewResponse{
+		ID:        rv.ID,
+		MovieID:   rv.MovieID,
+		UserID:    rv.UserID,
+		Username:  rv.Username,
+		Rating:    rv.Rating,
+		Comment:   rv.Comment,
+		CreatedAt: rv.CreatedAt,
+		UpdatedAt: rv.UpdatedAt,
+	}
+}
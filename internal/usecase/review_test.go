@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Review
+// =====================
+
+type MockReviewRepo struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepo) HasWatchedMovie(ctx context.Context, userID, movieID int) (bool, error) {
+	args := m.Called(ctx, userID, movieID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReviewRepo) GetReviewByID(ctx context.Context, id int) (entity.Review, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Review), args.Error(1)
+}
+
+func (m *MockReviewRepo) GetReviewsByMovie(ctx context.Context, movieID, page, limit int) ([]entity.Review, dto.Pagination, error) {
+	args := m.Called(ctx, movieID, page, limit)
+	return args.Get(0).([]entity.Review), args.Get(1).(dto.Pagination), args.Error(2)
+}
+
+func (m *MockReviewRepo) CreateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	args := m.Called(ctx, review)
+	return args.Get(0).(entity.Review), args.Error(1)
+}
+
+func (m *MockReviewRepo) UpdateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	args := m.Called(ctx, review)
+	return args.Get(0).(entity.Review), args.Error(1)
+}
+
+func (m *MockReviewRepo) DeleteReview(ctx context.Context, review entity.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+// =====================
+// Review UseCase Tests
+// =====================
+
+func newReviewUseCase() (*ReviewUseCase, *MockMovieRepo, *MockReviewRepo) {
+	mockMovieRepo := new(MockMovieRepo)
+	mockReviewRepo := new(MockReviewRepo)
+	repo := &repository.Repository{Movie: mockMovieRepo, Review: mockReviewRepo}
+	return &ReviewUseCase{Repo: repo}, mockMovieRepo, mockReviewRepo
+}
+
+func TestReviewUseCase_CreateReview_Success(t *testing.T) {
+	usecase, mockMovieRepo, mockReviewRepo := newReviewUseCase()
+
+	now := time.Now()
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+	mockReviewRepo.On("HasWatchedMovie", mock.Anything, 5, 1).Return(true, nil)
+	mockReviewRepo.On("CreateReview", mock.Anything, entity.Review{MovieID: 1, UserID: 5, Rating: 4, Comment: "Seru"}).
+		Return(entity.Review{ID: 9, MovieID: 1, UserID: 5, Rating: 4, Comment: "Seru", CreatedAt: now, UpdatedAt: now}, nil)
+
+	result, err := usecase.CreateReview(context.Background(), 5, 1, dto.ReviewRequest{Rating: 4, Comment: "  Seru "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, result.ID)
+	assert.Equal(t, 4, result.Rating)
+	mockReviewRepo.AssertExpectations(t)
+}
+
+func TestReviewUseCase_CreateReview_NotWatched(t *testing.T) {
+	usecase, mockMovieRepo, mockReviewRepo := newReviewUseCase()
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+	mockReviewRepo.On("HasWatchedMovie", mock.Anything, 5, 1).Return(false, nil)
+
+	_, err := usecase.CreateReview(context.Background(), 5, 1, dto.ReviewRequest{Rating: 4})
+
+	assert.ErrorIs(t, err, ErrReviewNotAllowed)
+	mockReviewRepo.AssertNotCalled(t, "CreateReview", mock.Anything, mock.Anything)
+}
+
+func TestReviewUseCase_CreateReview_MovieNotFound(t *testing.T) {
+	usecase, mockMovieRepo, mockReviewRepo := newReviewUseCase()
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 99).Return(entity.Movie{}, pgx.ErrNoRows)
+
+	_, err := usecase.CreateReview(context.Background(), 5, 99, dto.ReviewRequest{Rating: 4})
+
+	assert.ErrorIs(t, err, ErrMovieNotFound)
+	mockReviewRepo.AssertNotCalled(t, "HasWatchedMovie", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReviewUseCase_CreateReview_AlreadyReviewed(t *testing.T) {
+	usecase, mockMovieRepo, mockReviewRepo := newReviewUseCase()
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+	mockReviewRepo.On("HasWatchedMovie", mock.Anything, 5, 1).Return(true, nil)
+	mockReviewRepo.On("CreateReview", mock.Anything, mock.Anything).Return(entity.Review{}, repository.ErrReviewExists)
+
+	_, err := usecase.CreateReview(context.Background(), 5, 1, dto.ReviewRequest{Rating: 4})
+
+	assert.ErrorIs(t, err, ErrReviewExists)
+}
+
+func TestReviewUseCase_UpdateReview_Success(t *testing.T) {
+	usecase, _, mockReviewRepo := newReviewUseCase()
+
+	existing := entity.Review{ID: 9, MovieID: 1, UserID: 5, Username: "budi", Rating: 4, Comment: "Seru"}
+	mockReviewRepo.On("GetReviewByID", mock.Anything, 9).Return(existing, nil)
+	mockReviewRepo.On("UpdateReview", mock.Anything, mock.MatchedBy(func(rv entity.Review) bool {
+		return rv.ID == 9 && rv.MovieID == 1 && rv.Rating == 2 && rv.Comment == "Biasa saja"
+	})).Return(entity.Review{ID: 9, MovieID: 1, UserID: 5, Username: "budi", Rating: 2, Comment: "Biasa saja"}, nil)
+
+	result, err := usecase.UpdateReview(context.Background(), 5, 9, dto.ReviewRequest{Rating: 2, Comment: "Biasa saja"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Rating)
+	assert.Equal(t, "budi", result.Username)
+	mockReviewRepo.AssertExpectations(t)
+}
+
+func TestReviewUseCase_UpdateReview_NotOwned(t *testing.T) {
+	usecase, _, mockReviewRepo := newReviewUseCase()
+
+	mockReviewRepo.On("GetReviewByID", mock.Anything, 9).Return(entity.Review{ID: 9, MovieID: 1, UserID: 6}, nil)
+
+	_, err := usecase.UpdateReview(context.Background(), 5, 9, dto.ReviewRequest{Rating: 2})
+
+	assert.ErrorIs(t, err, ErrReviewNotOwned)
+	mockReviewRepo.AssertNotCalled(t, "UpdateReview", mock.Anything, mock.Anything)
+}
+
+func TestReviewUseCase_DeleteReview(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, _, mockReviewRepo := newReviewUseCase()
+
+		review := entity.Review{ID: 9, MovieID: 1, UserID: 5}
+		mockReviewRepo.On("GetReviewByID", mock.Anything, 9).Return(review, nil)
+		mockReviewRepo.On("DeleteReview", mock.Anything, review).Return(nil)
+
+		err := usecase.DeleteReview(context.Background(), 5, 9)
+
+		assert.NoError(t, err)
+		mockReviewRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		usecase, _, mockReviewRepo := newReviewUseCase()
+
+		mockReviewRepo.On("GetReviewByID", mock.Anything, 9).Return(entity.Review{}, pgx.ErrNoRows)
+
+		err := usecase.DeleteReview(context.Background(), 5, 9)
+
+		assert.ErrorIs(t, err, ErrReviewNotFound)
+	})
+}
+
+func TestReviewUseCase_GetMovieReviews(t *testing.T) {
+	usecase, mockMovieRepo, mockReviewRepo := newReviewUseCase()
+
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+	mockReviewRepo.On("GetReviewsByMovie", mock.Anything, 1, 1, 10).Return([]entity.Review{
+		{ID: 3, MovieID: 1, UserID: 5, Username: "budi", Rating: 5},
+	}, dto.Pagination{CurrentPage: 1, Limit: 10, TotalPages: 1, TotalRecords: 1}, nil)
+
+	result, pagination, err := usecase.GetMovieReviews(context.Background(), 1, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "budi", result[0].Username)
+	assert.Equal(t, 1, pagination.TotalRecords)
+}
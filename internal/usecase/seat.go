@@ -1,46 +1,78 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"math"
+	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/realtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type SeatUseCaseInterface interface {
-	GetSeatAvailability(ctx context.Context, cinemaID int, date, time string) ([]dto.SeatResponse, error)
+	GetSeatAvailability(ctx context.Context, cinemaID int, date, time string) (dto.SeatMapResponse, error)
+	GetSeatMap(ctx context.Context, showtimeID int) (dto.SeatMapResponse, error)
 	GetShowtimesByCinema(ctx context.Context, cinemaID int) ([]dto.ShowtimeResponse, error)
+	SuggestSeats(ctx context.Context, showtimeID, count int) ([]dto.SeatResponse, error)
+	StreamSeats(ctx context.Context, showtimeID int) (dto.SeatMapResponse, *realtime.Subscription, error)
 }
 
 type SeatUseCase struct {
-	Repo *repository.Repository
+	Repo    *repository.Repository
+	SeatHub *realtime.Hub
 }
 
-func NewSeatUseCase(repo *repository.Repository) SeatUseCaseInterface {
-	return &SeatUseCase{Repo: repo}
+func NewSeatUseCase(repo *repository.Repository, seatHub *realtime.Hub) SeatUseCaseInterface {
+	return &SeatUseCase{Repo: repo, SeatHub: seatHub}
 }
 
-// GetSeatAvailability retrieves seat availability for a showtime
-func (u *SeatUseCase) GetSeatAvailability(ctx context.Context, cinemaID int, date, time string) ([]dto.SeatResponse, error) {
-	showtime, err := u.Repo.Seat.GetShowtimeByParams(ctx, cinemaID, date, time)
+// GetSeatAvailability retrieves the seat map of the showtime a cinema runs at a date and time.
+// It fails with ErrAmbiguousShowtime when several studios show at that time.
+func (u *SeatUseCase) GetSeatAvailability(ctx context.Context, cinemaID int, date, time string) (dto.SeatMapResponse, error) {
+	filter, err := showtimeFilter(dto.ShowtimeSearchRequest{CinemaID: cinemaID, Date: date, Time: time})
 	if err != nil {
-		return nil, err
+		return dto.SeatMapResponse{}, err
 	}
 
-	seats, err := u.Repo.Seat.GetSeatsByShowtime(ctx, showtime.ID)
+	showtimes, err := u.Repo.Showtime.SearchShowtimes(ctx, filter)
 	if err != nil {
-		return nil, err
+		return dto.SeatMapResponse{}, err
+	}
+	switch {
+	case len(showtimes) == 0:
+		return dto.SeatMapResponse{}, ErrShowtimeNotFound
+	case len(showtimes) > 1:
+		return dto.SeatMapResponse{}, ErrAmbiguousShowtime
 	}
 
-	var response []dto.SeatResponse
-	for _, s := range seats {
-		response = append(response, dto.SeatResponse{
-			ID:       s.ID,
-			SeatCode: s.SeatCode,
-			IsBooked: s.IsBooked,
-		})
+	seats, err := u.Repo.Seat.GetSeatsByShowtime(ctx, showtimes[0].ID)
+	if err != nil {
+		return dto.SeatMapResponse{}, err
 	}
 
-	return response, nil
+	return buildSeatMap(showtimes[0], seats), nil
+}
+
+// GetSeatMap retrieves the seat map of a showtime
+func (u *SeatUseCase) GetSeatMap(ctx context.Context, showtimeID int) (dto.SeatMapResponse, error) {
+	showtime, err := u.scheduledShowtime(ctx, showtimeID)
+	if err != nil {
+		return dto.SeatMapResponse{}, err
+	}
+
+	seats, err := u.Repo.Seat.GetSeatsByShowtime(ctx, showtimeID)
+	if err != nil {
+		return dto.SeatMapResponse{}, err
+	}
+
+	return buildSeatMap(showtime, seats), nil
 }
 
 // GetShowtimesByCinema retrieves all showtimes for a cinema
@@ -52,36 +84,246 @@ func (u *SeatUseCase) GetShowtimesByCinema(ctx context.Context, cinemaID int) ([
 
 	var response []dto.ShowtimeResponse
 	for _, st := range showtimes {
-		showtimeResp := dto.ShowtimeResponse{
-			ID:       st.ID,
-			ShowDate: st.ShowDate,
-			ShowTime: st.ShowTime,
-			Price:    st.Price,
+		response = append(response, toShowtimeResponse(st))
+	}
+
+	return response, nil
+}
+
+// scheduledShowtime loads a showtime that can still be booked
+func (u *SeatUseCase) scheduledShowtime(ctx context.Context, showtimeID int) (entity.Showtime, error) {
+	showtime, err := u.Repo.Seat.GetShowtimeByID(ctx, showtimeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Showtime{}, ErrShowtimeNotFound
+	}
+	if err != nil {
+		return entity.Showtime{}, err
+	}
+	if showtime.Status == entity.ShowtimeCancelled {
+		return entity.Showtime{}, ErrShowtimeCancelled
+	}
+	return showtime, nil
+}
+
+// SuggestSeats picks the best available seats of a showtime for a group without booking them
+func (u *SeatUseCase) SuggestSeats(ctx context.Context, showtimeID, count int) ([]dto.SeatResponse, error) {
+	if _, err := u.scheduledShowtime(ctx, showtimeID); err != nil {
+		return nil, err
+	}
+
+	seats, err := u.Repo.Seat.GetSeatsByShowtime(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := pickBestSeats(seats, count)
+	if picked == nil {
+		return nil, ErrNotEnoughSeats
+	}
+
+	response := make([]dto.SeatResponse, 0, len(picked))
+	for _, seat := range picked {
+		response = append(response, dto.SeatResponse{
+			ID:       seat.ID,
+			SeatCode: seat.SeatCode,
+			Category: seat.Category,
+			Price:    seat.Price,
+		})
+	}
+
+	return response, nil
+}
+
+// StreamSeats subscribes to the seat changes of a showtime and returns the current seat map.
+// The subscription is opened before the map is read so no change falls in between;
+// the caller must close it.
+func (u *SeatUseCase) StreamSeats(ctx context.Context, showtimeID int) (dto.SeatMapResponse, *realtime.Subscription, error) {
+	showtime, err := u.scheduledShowtime(ctx, showtimeID)
+	if err != nil {
+		return dto.SeatMapResponse{}, nil, err
+	}
+
+	sub := u.SeatHub.Subscribe(showtimeID)
+	seats, err := u.Repo.Seat.GetSeatsByShowtime(ctx, showtimeID)
+	if err != nil {
+		sub.Close()
+		return dto.SeatMapResponse{}, nil, err
+	}
+
+	return buildSeatMap(showtime, seats), sub, nil
+}
+
+// buildSeatMap arranges seats into rows ordered by label with one cell per physical column
+func buildSeatMap(showtime entity.Showtime, seats []entity.SeatAvailability) dto.SeatMapResponse {
+	grid := arrangeSeats(seats)
+
+	response := dto.SeatMapResponse{
+		ShowtimeID: showtime.ID,
+		StudioID:   showtime.StudioID,
+		Columns:    grid.columns,
+		Rows:       make([]dto.SeatRowResponse, 0, len(grid.labels)),
+	}
+	for _, label := range grid.labels {
+		row := dto.SeatRowResponse{Label: label, Cells: make([]dto.SeatCellResponse, grid.columns)}
+		for column := 1; column <= grid.columns; column++ {
+			seat, ok := grid.rows[label][column]
+			if !ok {
+				row.Cells[column-1] = dto.SeatCellResponse{Column: column, Gap: true}
+				continue
+			}
+			row.Cells[column-1] = dto.SeatCellResponse{
+				Column:   column,
+				ID:       seat.ID,
+				SeatCode: seat.SeatCode,
+				Category: seat.Category,
+				Price:    seat.Price,
+				State:    seat.State,
+			}
 		}
+		response.Rows = append(response.Rows, row)
+	}
+
+	return response
+}
+
+// seatGrid holds a studio's seats by row label and physical column
+type seatGrid struct {
+	rows    map[string]map[int]entity.SeatAvailability
+	labels  []string
+	columns int
+}
+
+// arrangeSeats places seats on the studio grid. Seats without stored coordinates are
+// placed from their seat code, or after the last seat of their row when the code
+// cannot be parsed.
+func arrangeSeats(seats []entity.SeatAvailability) seatGrid {
+	grid := seatGrid{rows: make(map[string]map[int]entity.SeatAvailability)}
+	var unplaced []entity.SeatAvailability
 
-		// Add movie info if available
-		if st.Movie != nil {
-			showtimeResp.Movie = dto.MovieResponse{
-				ID:              st.Movie.ID,
-				Title:           st.Movie.Title,
-				PosterURL:       st.Movie.PosterURL,
-				Genres:          st.Movie.Genres,
-				Rating:          st.Movie.Rating,
-				DurationMinutes: st.Movie.DurationMinutes,
+	place := func(label string, column int, seat entity.SeatAvailability) {
+		if grid.rows[label] == nil {
+			grid.rows[label] = make(map[int]entity.SeatAvailability)
+		}
+		grid.rows[label][column] = seat
+		grid.columns = max(grid.columns, column)
+	}
+
+	for _, seat := range seats {
+		label, column := seat.RowLabel, seat.ColumnNumber
+		if label == "" || column < 1 {
+			label, column = splitSeatCode(seat.SeatCode)
+		}
+		if _, taken := grid.rows[label][column]; column < 1 || taken {
+			unplaced = append(unplaced, seat)
+			continue
+		}
+		place(label, column, seat)
+	}
+	for _, seat := range unplaced {
+		label, _ := splitSeatCode(seat.SeatCode)
+		last := 0
+		for column := range grid.rows[label] {
+			last = max(last, column)
+		}
+		place(label, last+1, seat)
+	}
+
+	grid.labels = make([]string, 0, len(grid.rows))
+	for label := range grid.rows {
+		grid.labels = append(grid.labels, label)
+	}
+	// Shorter labels first so row Z comes before row AA
+	slices.SortFunc(grid.labels, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+
+	return grid
+}
+
+// orphanPenalty outweighs any distance from the centre, so a block that strands a single
+// seat is only chosen when every block in the studio would
+const orphanPenalty = 1000.0
+
+// pickBestSeats chooses count available seats, preferring one contiguous block in a row
+// as close to the centre of the studio as possible that leaves no lone seat beside it.
+// When no row has room for the whole group the seats closest to the centre are used.
+// It returns nil when fewer than count seats are available.
+func pickBestSeats(seats []entity.SeatAvailability, count int) []entity.SeatAvailability {
+	if count < 1 {
+		return nil
+	}
+
+	grid := arrangeSeats(seats)
+	centreColumn := float64(grid.columns+1) / 2
+	centreRow := float64(len(grid.labels)-1) / 2
+
+	var best []entity.SeatAvailability
+	bestScore := 0.0
+	var free []entity.SeatAvailability
+	freeScore := make(map[int]float64)
+
+	for r, label := range grid.labels {
+		rowDistance := math.Abs(float64(r) - centreRow)
+
+		// Walk runs of adjacent available seats; aisles and taken seats end a run
+		var run []int
+		flush := func() {
+			for start := 0; start+count <= len(run); start++ {
+				left, right := start, len(run)-start-count
+				score := math.Abs(float64(run[start]+run[start+count-1])/2-centreColumn) + rowDistance
+				if left == 1 {
+					score += orphanPenalty
+				}
+				if right == 1 {
+					score += orphanPenalty
+				}
+				if best == nil || score < bestScore {
+					best = make([]entity.SeatAvailability, 0, count)
+					for _, column := range run[start : start+count] {
+						best = append(best, grid.rows[label][column])
+					}
+					bestScore = score
+				}
 			}
+			run = run[:0]
 		}
 
-		// Add studio info if available
-		if st.Studio != nil {
-			showtimeResp.Studio = dto.StudioResponse{
-				ID:         st.Studio.ID,
-				Name:       st.Studio.Name,
-				TotalSeats: st.Studio.TotalSeats,
+		for column := 1; column <= grid.columns; column++ {
+			seat, ok := grid.rows[label][column]
+			if !ok || seat.State != entity.SeatAvailable {
+				flush()
+				continue
 			}
+			run = append(run, column)
+			free = append(free, seat)
+			freeScore[seat.ID] = math.Abs(float64(column)-centreColumn) + rowDistance
 		}
+		flush()
+	}
 
-		response = append(response, showtimeResp)
+	if best != nil {
+		return best
+	}
+	if len(free) < count {
+		return nil
 	}
+	slices.SortStableFunc(free, func(a, b entity.SeatAvailability) int {
+		return cmp.Compare(freeScore[a.ID], freeScore[b.ID])
+	})
+	return free[:count]
+}
 
-	return response, nil
+// splitSeatCode splits a code such as "A10" into its row label and seat number.
+// The number is 0 when the code does not end in digits.
+func splitSeatCode(code string) (string, int) {
+	code = strings.ToUpper(code)
+	i := strings.IndexFunc(code, func(r rune) bool { return r >= '0' && r <= '9' })
+	if i < 0 {
+		return code, 0
+	}
+	number, err := strconv.Atoi(code[i:])
+	if err != nil {
+		return code[:i], 0
+	}
+	return code[:i], number
 }
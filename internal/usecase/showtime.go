@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
+	"project-app-bioskop/pkg/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// defaultCancelShowtimeReason is stored on refunds when the admin gives no reason
+const defaultCancelShowtimeReason = "showtime cancelled"
+
+type ShowtimeUseCaseInterface interface {
+	CreateShowtime(ctx context.Context, req dto.ShowtimeRequest) (dto.ShowtimeResponse, error)
+	RescheduleShowtime(ctx context.Context, id int, req dto.RescheduleShowtimeRequest) (dto.ShowtimeResponse, error)
+	CancelShowtime(ctx context.Context, id int, req dto.CancelShowtimeRequest) (dto.CancelShowtimeResponse, error)
+	SearchShowtimes(ctx context.Context, req dto.ShowtimeSearchRequest) ([]dto.ShowtimeResponse, error)
+}
+
+type ShowtimeUseCase struct {
+	Repo           *repository.Repository
+	EmailService   *utils.EmailService
+	Gateways       *gateway.Registry
+	SeatHub        *realtime.Hub
+	CleaningBuffer int
+}
+
+func NewShowtimeUseCase(repo *repository.Repository, config utils.Configuration, gateways *gateway.Registry, seatHub *realtime.Hub) ShowtimeUseCaseInterface {
+	return &ShowtimeUseCase{
+		Repo:           repo,
+		EmailService:   utils.NewEmailService(),
+		Gateways:       gateways,
+		SeatHub:        seatHub,
+		CleaningBuffer: config.Showtime.CleaningBufferMinutes,
+	}
+}
+
+// bufferMinutes returns the configured gap kept free after each showtime
+func (u *ShowtimeUseCase) bufferMinutes() int {
+	if u.CleaningBuffer < 0 {
+		return 0
+	}
+	return u.CleaningBuffer
+}
+
+// sendRescheduleNotice sends showtime change email asynchronously (GOROUTINE)
+func (u *ShowtimeUseCase) sendRescheduleNotice(email, username, movieTitle string, bookingID int, showDate, showTime string) {
+	message := fmt.Sprintf(
+		"Dear %s,\n\nThe showtime of your booking #%d for %s has been moved.\n\nNew Date: %s\nNew Time: %s\n\nYour seats are kept for the new schedule.",
+		username, bookingID, movieTitle, showDate, showTime,
+	)
+	u.EmailService.SendOTP(email, username, message)
+}
+
+// sendShowtimeCancellationNotice sends showtime cancellation email asynchronously (GOROUTINE)
+func (u *ShowtimeUseCase) sendShowtimeCancellationNotice(email, username, movieTitle string, bookingID int, showDate, showTime string, refund *dto.RefundResponse) {
+	message := fmt.Sprintf(
+		"Dear %s,\n\nThe %s showing of %s at %s has been cancelled, so your booking #%d has been cancelled too.",
+		username, showDate, movieTitle, showTime, bookingID,
+	)
+	if refund != nil {
+		message += fmt.Sprintf("\n\nA refund of Rp %.0f has been requested and is currently %s.", refund.Amount, refund.Status)
+	}
+	u.EmailService.SendOTP(email, username, message)
+}
+
+// CreateShowtime schedules a movie in a studio
+func (u *ShowtimeUseCase) CreateShowtime(ctx context.Context, req dto.ShowtimeRequest) (dto.ShowtimeResponse, error) {
+	if _, err := u.Repo.Cinema.GetStudioByID(ctx, req.StudioID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ShowtimeResponse{}, ErrStudioNotFound
+		}
+		return dto.ShowtimeResponse{}, err
+	}
+	if _, err := u.Repo.Movie.GetMovieByID(ctx, req.MovieID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.ShowtimeResponse{}, ErrMovieNotFound
+		}
+		return dto.ShowtimeResponse{}, err
+	}
+
+	showtime := entity.Showtime{
+		StudioID: req.StudioID,
+		MovieID:  req.MovieID,
+		ShowDate: req.ShowDate,
+		ShowTime: req.ShowTime,
+		Price:    req.Price,
+	}
+	if err := requireFutureStart(showtime); err != nil {
+		return dto.ShowtimeResponse{}, err
+	}
+
+	created, err := u.Repo.Showtime.CreateShowtime(ctx, showtime, u.bufferMinutes())
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The studio was checked above, so the movie was deleted meanwhile
+		return dto.ShowtimeResponse{}, ErrMovieNotFound
+	}
+	if err != nil {
+		return dto.ShowtimeResponse{}, err
+	}
+
+	return u.getShowtimeResponse(ctx, created.ID)
+}
+
+// RescheduleShowtime moves a showtime and tells the holders of its bookings
+func (u *ShowtimeUseCase) RescheduleShowtime(ctx context.Context, id int, req dto.RescheduleShowtimeRequest) (dto.ShowtimeResponse, error) {
+	showtime := entity.Showtime{
+		ID:       id,
+		ShowDate: req.ShowDate,
+		ShowTime: req.ShowTime,
+		Price:    req.Price,
+	}
+	if err := requireFutureStart(showtime); err != nil {
+		return dto.ShowtimeResponse{}, err
+	}
+
+	_, bookings, err := u.Repo.Showtime.RescheduleShowtime(ctx, showtime, u.bufferMinutes())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.ShowtimeResponse{}, ErrShowtimeNotFound
+	}
+	if err != nil {
+		return dto.ShowtimeResponse{}, err
+	}
+
+	response, err := u.getShowtimeResponse(ctx, id)
+	if err != nil {
+		return dto.ShowtimeResponse{}, err
+	}
+
+	// GOROUTINE: Non-blocking schedule change notices
+	for _, b := range bookings {
+		user, _ := u.Repo.Auth.GetUserByID(ctx, b.UserID)
+		if user.Email != "" {
+			go u.sendRescheduleNotice(user.Email, user.Username, response.Movie.Title, b.ID, response.ShowDate, response.ShowTime)
+		}
+	}
+
+	return response, nil
+}
+
+// CancelShowtime cancels a showtime with its bookings, refunds paid bookings and notifies their holders
+func (u *ShowtimeUseCase) CancelShowtime(ctx context.Context, id int, req dto.CancelShowtimeRequest) (dto.CancelShowtimeResponse, error) {
+	showtime, err := u.Repo.Seat.GetShowtimeByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.CancelShowtimeResponse{}, ErrShowtimeNotFound
+	}
+	if err != nil {
+		return dto.CancelShowtimeResponse{}, err
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = defaultCancelShowtimeReason
+	}
+
+	bookings, refunds, err := u.Repo.Showtime.CancelShowtime(ctx, id, reason)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Already cancelled
+		return dto.CancelShowtimeResponse{}, ErrShowtimeNotFound
+	}
+	if err != nil {
+		return dto.CancelShowtimeResponse{}, err
+	}
+
+	u.SeatHub.Publish(realtime.SeatEvent{ShowtimeID: id, Reason: realtime.ReasonShowtimeCancelled})
+
+	response := dto.CancelShowtimeResponse{
+		ShowtimeID:        id,
+		Status:            entity.ShowtimeCancelled,
+		CancelledBookings: len(bookings),
+		Refunds:           make([]dto.RefundResponse, 0, len(refunds)),
+	}
+
+	refundByBooking := make(map[int]*dto.RefundResponse, len(refunds))
+	for _, rf := range refunds {
+		status := rf.Status
+		payment, err := u.Repo.Payment.GetPaymentByBookingID(ctx, rf.BookingID)
+		if err == nil && payment.ID == rf.PaymentID {
+			status = processRefund(ctx, u.Repo, u.Gateways, rf.ID, payment, rf.Amount)
+		}
+
+		response.Refunds = append(response.Refunds, dto.RefundResponse{
+			ID:        rf.ID,
+			Amount:    rf.Amount,
+			Status:    status,
+			CreatedAt: time.Now(),
+		})
+		refundByBooking[rf.BookingID] = &response.Refunds[len(response.Refunds)-1]
+	}
+
+	// GOROUTINE: Non-blocking cancellation notices
+	for _, b := range bookings {
+		user, _ := u.Repo.Auth.GetUserByID(ctx, b.UserID)
+		if user.Email != "" {
+			go u.sendShowtimeCancellationNotice(user.Email, user.Username, showtime.Movie.Title, b.ID, showtime.ShowDate, showtime.ShowTime, refundByBooking[b.ID])
+		}
+	}
+
+	return response, nil
+}
+
+// SearchShowtimes lists upcoming showtimes across studios matching the search
+func (u *ShowtimeUseCase) SearchShowtimes(ctx context.Context, req dto.ShowtimeSearchRequest) ([]dto.ShowtimeResponse, error) {
+	filter, err := showtimeFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	showtimes, err := u.Repo.Showtime.SearchShowtimes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.ShowtimeResponse, 0, len(showtimes))
+	for _, st := range showtimes {
+		response = append(response, toShowtimeResponse(st))
+	}
+
+	return response, nil
+}
+
+// getShowtimeResponse loads a showtime with its movie and studio for the response
+func (u *ShowtimeUseCase) getShowtimeResponse(ctx context.Context, id int) (dto.ShowtimeResponse, error) {
+	showtime, err := u.Repo.Seat.GetShowtimeByID(ctx, id)
+	if err != nil {
+		return dto.ShowtimeResponse{}, err
+	}
+
+	return toShowtimeResponse(showtime), nil
+}
+
+// toShowtimeResponse maps a showtime with its optional movie and studio to the response
+func toShowtimeResponse(st entity.Showtime) dto.ShowtimeResponse {
+	response := dto.ShowtimeResponse{
+		ID:       st.ID,
+		CinemaID: st.CinemaID,
+		ShowDate: st.ShowDate,
+		ShowTime: st.ShowTime,
+		Price:    st.Price,
+		Status:   st.Status,
+	}
+	if st.Movie != nil {
+		response.Movie = dto.MovieResponse{
+			ID:              st.Movie.ID,
+			Title:           st.Movie.Title,
+			PosterURL:       st.Movie.PosterURL,
+			Genres:          st.Movie.Genres,
+			Rating:          st.Movie.Rating,
+			DurationMinutes: st.Movie.DurationMinutes,
+		}
+	}
+	if st.Studio != nil {
+		response.Studio = dto.StudioResponse{
+			ID:         st.Studio.ID,
+			Name:       st.Studio.Name,
+			TotalSeats: st.Studio.TotalSeats,
+		}
+	}
+	return response
+}
+
+// showtimeFilter validates the search dates and times and normalizes them for the repository
+func showtimeFilter(req dto.ShowtimeSearchRequest) (entity.ShowtimeFilter, error) {
+	filter := entity.ShowtimeFilter{CinemaID: req.CinemaID, MovieID: req.MovieID}
+
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return filter, ErrInvalidShowDate
+		}
+		filter.ShowDate = date.Format("2006-01-02")
+	}
+
+	for _, field := range []struct {
+		value string
+		dest  *string
+	}{
+		{req.Time, &filter.ShowTime},
+		{req.TimeFrom, &filter.TimeFrom},
+		{req.TimeTo, &filter.TimeTo},
+	} {
+		if field.value == "" {
+			continue
+		}
+		clock, err := parseShowTime(field.value)
+		if err != nil {
+			return filter, err
+		}
+		*field.dest = clock
+	}
+
+	return filter, nil
+}
+
+// parseShowTime accepts HH:MM or HH:MM:SS and returns HH:MM:SS
+func parseShowTime(value string) (string, error) {
+	clock, err := time.Parse("15:04:05", value)
+	if err != nil {
+		clock, err = time.Parse("15:04", value)
+	}
+	if err != nil {
+		return "", ErrInvalidShowTime
+	}
+	return clock.Format("15:04:05"), nil
+}
+
+// requireFutureStart rejects showtimes that would already have started
+func requireFutureStart(showtime entity.Showtime) error {
+	start, err := showtimeStart(showtime)
+	if err != nil {
+		return err
+	}
+	if !start.After(time.Now()) {
+		return ErrShowtimeInPast
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/gateway"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Showtime
+// =====================
+
+type MockShowtimeRepo struct {
+	mock.Mock
+}
+
+func (m *MockShowtimeRepo) CreateShowtime(ctx context.Context, showtime entity.Showtime, bufferMinutes int) (entity.Showtime, error) {
+	args := m.Called(ctx, showtime, bufferMinutes)
+	return args.Get(0).(entity.Showtime), args.Error(1)
+}
+
+func (m *MockShowtimeRepo) RescheduleShowtime(ctx context.Context, showtime entity.Showtime, bufferMinutes int) (entity.Showtime, []entity.Booking, error) {
+	args := m.Called(ctx, showtime, bufferMinutes)
+	return args.Get(0).(entity.Showtime), args.Get(1).([]entity.Booking), args.Error(2)
+}
+
+func (m *MockShowtimeRepo) CancelShowtime(ctx context.Context, id int, reason string) ([]entity.Booking, []entity.Refund, error) {
+	args := m.Called(ctx, id, reason)
+	return args.Get(0).([]entity.Booking), args.Get(1).([]entity.Refund), args.Error(2)
+}
+
+func (m *MockShowtimeRepo) SearchShowtimes(ctx context.Context, filter entity.ShowtimeFilter) ([]entity.Showtime, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]entity.Showtime), args.Error(1)
+}
+
+func (m *MockShowtimeRepo) GetMovieSchedule(ctx context.Context, movieID int, filter entity.MovieScheduleFilter) ([]entity.ShowtimeListing, error) {
+	args := m.Called(ctx, movieID, filter)
+	return args.Get(0).([]entity.ShowtimeListing), args.Error(1)
+}
+
+// newScheduledShowtime builds a showtime with movie and studio starting at the given time
+func newScheduledShowtime(id int, start time.Time) entity.Showtime {
+	return entity.Showtime{
+		ID:       id,
+		StudioID: 1,
+		MovieID:  1,
+		ShowDate: start.Format("2006-01-02"),
+		ShowTime: start.Format("15:04:05"),
+		Price:    50000,
+		Status:   entity.ShowtimeScheduled,
+		Movie:    &entity.Movie{ID: 1, Title: "Test Movie", DurationMinutes: 120},
+		Studio:   &entity.Studio{ID: 1, Name: "Studio 1", TotalSeats: 100},
+	}
+}
+
+// =====================
+// Test CreateShowtime
+// =====================
+
+func TestShowtimeUseCase_CreateShowtime_Success(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	mockMovieRepo := new(MockMovieRepo)
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+
+	repo := &repository.Repository{
+		Cinema:   mockCinemaRepo,
+		Movie:    mockMovieRepo,
+		Seat:     mockSeatRepo,
+		Showtime: mockShowtimeRepo,
+	}
+	usecase := &ShowtimeUseCase{Repo: repo, CleaningBuffer: 15}
+
+	start := time.Now().Add(48 * time.Hour)
+	req := dto.ShowtimeRequest{
+		MovieID:  1,
+		StudioID: 1,
+		ShowDate: start.Format("2006-01-02"),
+		ShowTime: start.Format("15:04"),
+		Price:    50000,
+	}
+
+	mockCinemaRepo.On("GetStudioByID", mock.Anything, 1).Return(entity.Studio{ID: 1, CinemaID: 1}, nil)
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+	mockShowtimeRepo.On("CreateShowtime", mock.Anything, mock.MatchedBy(func(st entity.Showtime) bool {
+		return st.StudioID == 1 && st.MovieID == 1 && st.ShowTime == req.ShowTime
+	}), 15).Return(entity.Showtime{ID: 11}, nil)
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 11).Return(newScheduledShowtime(11, start), nil)
+
+	result, err := usecase.CreateShowtime(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, result.ID)
+	assert.Equal(t, entity.ShowtimeScheduled, result.Status)
+	assert.Equal(t, "Test Movie", result.Movie.Title)
+	mockShowtimeRepo.AssertExpectations(t)
+}
+
+func TestShowtimeUseCase_CreateShowtime_StudioNotFound(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo, Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo}
+
+	mockCinemaRepo.On("GetStudioByID", mock.Anything, 999).Return(entity.Studio{}, pgx.ErrNoRows)
+
+	_, err := usecase.CreateShowtime(context.Background(), dto.ShowtimeRequest{StudioID: 999, MovieID: 1})
+
+	assert.ErrorIs(t, err, ErrStudioNotFound)
+	mockShowtimeRepo.AssertNotCalled(t, "CreateShowtime", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShowtimeUseCase_CreateShowtime_InPast(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	mockMovieRepo := new(MockMovieRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo, Movie: mockMovieRepo, Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo}
+
+	start := time.Now().Add(-time.Hour)
+	mockCinemaRepo.On("GetStudioByID", mock.Anything, 1).Return(entity.Studio{ID: 1}, nil)
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+
+	_, err := usecase.CreateShowtime(context.Background(), dto.ShowtimeRequest{
+		MovieID:  1,
+		StudioID: 1,
+		ShowDate: start.Format("2006-01-02"),
+		ShowTime: start.Format("15:04"),
+		Price:    50000,
+	})
+
+	assert.ErrorIs(t, err, ErrShowtimeInPast)
+	mockShowtimeRepo.AssertNotCalled(t, "CreateShowtime", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShowtimeUseCase_CreateShowtime_Conflict(t *testing.T) {
+	mockCinemaRepo := new(MockCinemaRepo)
+	mockMovieRepo := new(MockMovieRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Cinema: mockCinemaRepo, Movie: mockMovieRepo, Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo, CleaningBuffer: 15}
+
+	start := time.Now().Add(48 * time.Hour)
+	mockCinemaRepo.On("GetStudioByID", mock.Anything, 1).Return(entity.Studio{ID: 1}, nil)
+	mockMovieRepo.On("GetMovieByID", mock.Anything, 1).Return(entity.Movie{ID: 1}, nil)
+	mockShowtimeRepo.On("CreateShowtime", mock.Anything, mock.Anything, 15).
+		Return(entity.Showtime{}, fmt.Errorf("%w: showtime 3 at 2030-01-01 19:00:00", repository.ErrShowtimeConflict))
+
+	_, err := usecase.CreateShowtime(context.Background(), dto.ShowtimeRequest{
+		MovieID:  1,
+		StudioID: 1,
+		ShowDate: start.Format("2006-01-02"),
+		ShowTime: start.Format("15:04"),
+		Price:    50000,
+	})
+
+	assert.ErrorIs(t, err, ErrShowtimeConflict)
+	assert.Contains(t, err.Error(), "showtime 3")
+}
+
+// =====================
+// Test RescheduleShowtime
+// =====================
+
+func TestShowtimeUseCase_RescheduleShowtime_Success(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	mockAuthRepo := new(MockAuthRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo, Auth: mockAuthRepo}
+	usecase := &ShowtimeUseCase{Repo: repo, CleaningBuffer: -5}
+
+	start := time.Now().Add(72 * time.Hour)
+	req := dto.RescheduleShowtimeRequest{ShowDate: start.Format("2006-01-02"), ShowTime: start.Format("15:04")}
+	bookings := []entity.Booking{{ID: 5, UserID: 1, ShowtimeID: 4, Status: "paid"}}
+
+	// A negative buffer is treated as none
+	mockShowtimeRepo.On("RescheduleShowtime", mock.Anything,
+		entity.Showtime{ID: 4, ShowDate: req.ShowDate, ShowTime: req.ShowTime}, 0).
+		Return(entity.Showtime{ID: 4}, bookings, nil)
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 4).Return(newScheduledShowtime(4, start), nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+
+	result, err := usecase.RescheduleShowtime(context.Background(), 4, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.ID)
+	mockShowtimeRepo.AssertExpectations(t)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestShowtimeUseCase_RescheduleShowtime_NotFound(t *testing.T) {
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo}
+
+	start := time.Now().Add(72 * time.Hour)
+	mockShowtimeRepo.On("RescheduleShowtime", mock.Anything, mock.Anything, 0).
+		Return(entity.Showtime{}, []entity.Booking(nil), pgx.ErrNoRows)
+
+	_, err := usecase.RescheduleShowtime(context.Background(), 999, dto.RescheduleShowtimeRequest{
+		ShowDate: start.Format("2006-01-02"),
+		ShowTime: start.Format("15:04"),
+	})
+
+	assert.ErrorIs(t, err, ErrShowtimeNotFound)
+}
+
+// =====================
+// Test CancelShowtime
+// =====================
+
+func TestShowtimeUseCase_CancelShowtime_CascadesAndRefunds(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	mockPaymentRepo := new(MockPaymentRepo)
+	mockAuthRepo := new(MockAuthRepo)
+
+	repo := &repository.Repository{
+		Seat:     mockSeatRepo,
+		Showtime: mockShowtimeRepo,
+		Payment:  mockPaymentRepo,
+		Auth:     mockAuthRepo,
+	}
+	gateways := gateway.NewDefaultRegistry(gateway.ModeSuccess)
+	usecase := &ShowtimeUseCase{Repo: repo, Gateways: gateways}
+
+	// Charge through the simulator so the refund has a transaction to reverse
+	gw, _ := gateways.Get(gateway.SimulatorName)
+	charge, _ := gw.Charge(context.Background(), gateway.ChargeRequest{PaymentID: 9, BookingID: 5, Amount: 100000})
+
+	bookings := []entity.Booking{
+		{ID: 5, UserID: 1, ShowtimeID: 4, Status: "paid", TotalAmount: 100000},
+		{ID: 6, UserID: 2, ShowtimeID: 4, Status: "pending", TotalAmount: 50000},
+	}
+	refunds := []entity.Refund{{ID: 3, PaymentID: 9, BookingID: 5, Amount: 100000, Status: "pending"}}
+	payment := entity.Payment{ID: 9, BookingID: 5, PaymentMethodID: 1, TransactionID: charge.TransactionID}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 4).Return(newScheduledShowtime(4, time.Now().Add(24*time.Hour)), nil)
+	mockShowtimeRepo.On("CancelShowtime", mock.Anything, 4, defaultCancelShowtimeReason).Return(bookings, refunds, nil)
+	mockPaymentRepo.On("GetPaymentByBookingID", mock.Anything, 5).Return(payment, nil)
+	mockPaymentRepo.On("GetPaymentMethodByID", mock.Anything, 1).Return(entity.PaymentMethod{ID: 1, Gateway: gateway.SimulatorName}, nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 3, "processing").Return(nil)
+	mockPaymentRepo.On("UpdateRefundStatus", mock.Anything, 3, "completed").Return(nil)
+	mockPaymentRepo.On("UpdatePaymentStatus", mock.Anything, 9, "refunded").Return(nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 1).Return(entity.User{ID: 1}, nil)
+	mockAuthRepo.On("GetUserByID", mock.Anything, 2).Return(entity.User{ID: 2}, nil)
+
+	result, err := usecase.CancelShowtime(context.Background(), 4, dto.CancelShowtimeRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ShowtimeCancelled, result.Status)
+	assert.Equal(t, 2, result.CancelledBookings)
+	assert.Len(t, result.Refunds, 1)
+	assert.Equal(t, "completed", result.Refunds[0].Status)
+	mockPaymentRepo.AssertExpectations(t)
+	mockAuthRepo.AssertExpectations(t)
+}
+
+func TestShowtimeUseCase_CancelShowtime_AlreadyCancelled(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 4).Return(newScheduledShowtime(4, time.Now().Add(24*time.Hour)), nil)
+	mockShowtimeRepo.On("CancelShowtime", mock.Anything, 4, "projector failure").
+		Return([]entity.Booking(nil), []entity.Refund(nil), pgx.ErrNoRows)
+
+	_, err := usecase.CancelShowtime(context.Background(), 4, dto.CancelShowtimeRequest{Reason: "projector failure"})
+
+	assert.ErrorIs(t, err, ErrShowtimeNotFound)
+}
+
+func TestShowtimeUseCase_CancelShowtime_NotFound(t *testing.T) {
+	mockSeatRepo := new(MockSeatRepo)
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Seat: mockSeatRepo, Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo}
+
+	mockSeatRepo.On("GetShowtimeByID", mock.Anything, 999).Return(entity.Showtime{}, pgx.ErrNoRows)
+
+	_, err := usecase.CancelShowtime(context.Background(), 999, dto.CancelShowtimeRequest{})
+
+	assert.ErrorIs(t, err, ErrShowtimeNotFound)
+	mockShowtimeRepo.AssertNotCalled(t, "CancelShowtime", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// =====================
+// Test SearchShowtimes
+// =====================
+
+func TestShowtimeUseCase_SearchShowtimes_Success(t *testing.T) {
+	mockShowtimeRepo := new(MockShowtimeRepo)
+	repo := &repository.Repository{Showtime: mockShowtimeRepo}
+	usecase := &ShowtimeUseCase{Repo: repo}
+
+	showtimes := []entity.Showtime{
+		newScheduledShowtime(1, time.Date(2030, 1, 15, 19, 0, 0, 0, time.Local)),
+		newScheduledShowtime(2, time.Date(2030, 1, 15, 19, 0, 0, 0, time.Local)),
+	}
+	filter := entity.ShowtimeFilter{CinemaID: 1, ShowDate: "2030-01-15", TimeFrom: "18:30:00"}
+	mockShowtimeRepo.On("SearchShowtimes", mock.Anything, filter).Return(showtimes, nil)
+
+	result, err := usecase.SearchShowtimes(context.Background(), dto.ShowtimeSearchRequest{
+		CinemaID: 1,
+		Date:     "2030-01-15",
+		TimeFrom: "18:30",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, 2, result[1].ID)
+	mockShowtimeRepo.AssertExpectations(t)
+}
+
+func TestShowtimeFilter(t *testing.T) {
+	t.Run("Times With Or Without Seconds", func(t *testing.T) {
+		filter, err := showtimeFilter(dto.ShowtimeSearchRequest{Time: "19:00", TimeTo: "23:30:15"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "19:00:00", filter.ShowTime)
+		assert.Equal(t, "23:30:15", filter.TimeTo)
+	})
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		_, err := showtimeFilter(dto.ShowtimeSearchRequest{Date: "15-01-2030"})
+
+		assert.ErrorIs(t, err, ErrInvalidShowDate)
+	})
+
+	t.Run("Invalid Time", func(t *testing.T) {
+		_, err := showtimeFilter(dto.ShowtimeSearchRequest{TimeFrom: "25:00"})
+
+		assert.ErrorIs(t, err, ErrInvalidShowTime)
+	})
+}
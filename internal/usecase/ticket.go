@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"project-app-bioskop/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/skip2/go-qrcode"
+)
+
+// defaultEntryOpens is how long before the showtime starts tickets are admitted when not configured
+const defaultEntryOpens = time.Hour
+
+// ticketQRSize is the width and height in pixels of a ticket's QR code
+const ticketQRSize = 256
+
+// MinTicketSecretLength is the shortest signing secret e-tickets are issued and admitted with
+const MinTicketSecretLength = 32
+
+type TicketUseCaseInterface interface {
+	GetTickets(ctx context.Context, userID, bookingID int) ([]dto.TicketResponse, error)
+	GetTicketQR(ctx context.Context, userID, ticketID int) ([]byte, error)
+	ScanTicket(ctx context.Context, staffID int, req dto.TicketScanRequest) (dto.TicketScanResponse, error)
+}
+
+type TicketUseCase struct {
+	Repo       *repository.Repository
+	Secret     string
+	EntryOpens time.Duration
+}
+
+func NewTicketUseCase(repo *repository.Repository, config utils.Configuration) TicketUseCaseInterface {
+	return &TicketUseCase{
+		Repo:       repo,
+		Secret:     config.Ticket.SigningSecret,
+		EntryOpens: time.Duration(config.Ticket.EntryOpensMinutes) * time.Minute,
+	}
+}
+
+// TicketsEnabled reports whether secret is long enough to sign e-tickets with. Without
+// one tickets are neither issued nor admitted, so a missing or guessable secret cannot
+// be used to forge them.
+func TicketsEnabled(secret string) bool {
+	return len(secret) >= MinTicketSecretLength
+}
+
+// entryOpens returns how long before the showtime starts its tickets are admitted
+func (u *TicketUseCase) entryOpens() time.Duration {
+	if u.EntryOpens <= 0 {
+		return defaultEntryOpens
+	}
+	return u.EntryOpens
+}
+
+// GetTickets lists the e-tickets of a user's paid booking, one per seat
+func (u *TicketUseCase) GetTickets(ctx context.Context, userID, bookingID int) ([]dto.TicketResponse, error) {
+	booking, err := u.Repo.Booking.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, ErrBookingNotFound
+	}
+	if booking.UserID != userID {
+		return nil, ErrBookingNotOwned
+	}
+	if booking.Status != "paid" {
+		return nil, ErrTicketsNotIssued
+	}
+	if !TicketsEnabled(u.Secret) {
+		return nil, ErrTicketsDisabled
+	}
+
+	tickets, err := u.Repo.Ticket.GetTicketsByBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.TicketResponse{}
+	for _, t := range tickets {
+		response = append(response, dto.TicketResponse{
+			ID:         t.ID,
+			BookingID:  t.BookingID,
+			SeatCode:   t.SeatCode,
+			MovieTitle: t.Showtime.Movie.Title,
+			StudioName: t.Showtime.Studio.Name,
+			ShowDate:   t.Showtime.ShowDate,
+			ShowTime:   t.Showtime.ShowTime,
+			Token:      signTicket(u.Secret, t.ID, t.BookingID),
+			QRCodeURL:  fmt.Sprintf("/api/tickets/%d/qr", t.ID),
+			AdmittedAt: t.AdmittedAt,
+		})
+	}
+	return response, nil
+}
+
+// GetTicketQR renders the token of a user's ticket as a QR code PNG
+func (u *TicketUseCase) GetTicketQR(ctx context.Context, userID, ticketID int) ([]byte, error) {
+	ticket, err := u.Repo.Ticket.GetTicketByID(ctx, ticketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTicketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ticket.UserID != userID {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.BookingStatus != "paid" {
+		return nil, ErrTicketsNotIssued
+	}
+	if !TicketsEnabled(u.Secret) {
+		return nil, ErrTicketsDisabled
+	}
+
+	return qrcode.Encode(signTicket(u.Secret, ticket.ID, ticket.BookingID), qrcode.Medium, ticketQRSize)
+}
+
+// ScanTicket admits the holder of a ticket at the door. The token must carry a valid
+// signature, its booking must still be paid and the scan must fall between entry opening
+// before the showtime and the end of the showing. A ticket admits only once.
+func (u *TicketUseCase) ScanTicket(ctx context.Context, staffID int, req dto.TicketScanRequest) (dto.TicketScanResponse, error) {
+	if !TicketsEnabled(u.Secret) {
+		return dto.TicketScanResponse{}, ErrTicketsDisabled
+	}
+	ticketID, bookingID, ok := verifyTicket(u.Secret, strings.TrimSpace(req.Token))
+	if !ok {
+		return dto.TicketScanResponse{}, ErrInvalidTicket
+	}
+
+	ticket, err := u.Repo.Ticket.GetTicketByID(ctx, ticketID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.TicketScanResponse{}, ErrInvalidTicket
+	}
+	if err != nil {
+		return dto.TicketScanResponse{}, err
+	}
+	if ticket.BookingID != bookingID {
+		return dto.TicketScanResponse{}, ErrInvalidTicket
+	}
+	if ticket.BookingStatus != "paid" || ticket.Showtime.Status == entity.ShowtimeCancelled {
+		return dto.TicketScanResponse{}, ErrTicketVoid
+	}
+	if ticket.AdmittedAt != nil {
+		return dto.TicketScanResponse{}, fmt.Errorf("%w at %s", ErrTicketAlreadyAdmitted, ticket.AdmittedAt.Local().Format("15:04"))
+	}
+	if err := u.checkEntryWindow(ticket.Showtime, time.Now()); err != nil {
+		return dto.TicketScanResponse{}, err
+	}
+
+	admittedAt, err := u.Repo.Ticket.AdmitTicket(ctx, ticket.ID, staffID)
+	if err != nil {
+		return dto.TicketScanResponse{}, err
+	}
+
+	return dto.TicketScanResponse{
+		TicketID:   ticket.ID,
+		BookingID:  ticket.BookingID,
+		SeatCode:   ticket.SeatCode,
+		MovieTitle: ticket.Showtime.Movie.Title,
+		StudioName: ticket.Showtime.Studio.Name,
+		ShowDate:   ticket.Showtime.ShowDate,
+		ShowTime:   ticket.Showtime.ShowTime,
+		AdmittedAt: admittedAt,
+	}, nil
+}
+
+// checkEntryWindow refuses a scan before entry opens or after the showing has ended
+func (u *TicketUseCase) checkEntryWindow(showtime entity.Showtime, now time.Time) error {
+	start, err := showtimeStart(showtime)
+	if err != nil {
+		return err
+	}
+	opens := start.Add(-u.entryOpens())
+	if now.Before(opens) {
+		return fmt.Errorf("%w: entry opens at %s", ErrTicketOutsideEntryWindow, opens.Format("2006-01-02 15:04"))
+	}
+	ends := start
+	if showtime.Movie != nil {
+		ends = start.Add(time.Duration(showtime.Movie.DurationMinutes) * time.Minute)
+	}
+	if now.After(ends) {
+		return fmt.Errorf("%w: the showing ended at %s", ErrTicketOutsideEntryWindow, ends.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// signTicket builds the token of a ticket: its ID and booking ID followed by their
+// hex encoded HMAC-SHA256 under secret
+func signTicket(secret string, ticketID, bookingID int) string {
+	payload := strconv.Itoa(ticketID) + "." + strconv.Itoa(bookingID)
+	return payload + "." + ticketSignature(secret, payload)
+}
+
+// verifyTicket returns the ticket and booking IDs of a token whose signature matches,
+// comparing in constant time
+func verifyTicket(secret, token string) (ticketID, bookingID int, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	expected, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	actual, _ := hex.DecodeString(ticketSignature(secret, parts[0]+"."+parts[1]))
+	if !hmac.Equal(actual, expected) {
+		return 0, 0, false
+	}
+
+	ticketID, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	bookingID, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return ticketID, bookingID, true
+}
+
+func ticketSignature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"project-app-bioskop/internal/data/entity"
+	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// =====================
+// Mock Repository untuk Ticket
+// =====================
+
+type MockTicketRepo struct {
+	mock.Mock
+}
+
+func (m *MockTicketRepo) GetTicketsByBooking(ctx context.Context, bookingID int) ([]entity.Ticket, error) {
+	args := m.Called(ctx, bookingID)
+	return args.Get(0).([]entity.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepo) GetTicketByID(ctx context.Context, id int) (entity.Ticket, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepo) AdmitTicket(ctx context.Context, id, staffID int) (time.Time, error) {
+	args := m.Called(ctx, id, staffID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+// =====================
+// Ticket UseCase Tests
+// =====================
+
+const testTicketSecret = "0123456789abcdef0123456789abcdef"
+
+// testTicket returns a paid ticket for a showtime starting at start
+func testTicket(start time.Time) entity.Ticket {
+	return entity.Ticket{
+		ID: 10, BookingID: 1, UserID: 1, BookingStatus: "paid", SeatID: 5, SeatCode: "A5",
+		Showtime: entity.Showtime{
+			ID:       3,
+			ShowDate: start.Format("2006-01-02"),
+			ShowTime: start.Format("15:04:05"),
+			Status:   entity.ShowtimeScheduled,
+			Movie:    &entity.Movie{ID: 4, Title: "Avengers", DurationMinutes: 120},
+			Studio:   &entity.Studio{ID: 2, Name: "Studio 2"},
+		},
+	}
+}
+
+func TestTicketToken(t *testing.T) {
+	token := signTicket(testTicketSecret, 10, 1)
+
+	ticketID, bookingID, ok := verifyTicket(testTicketSecret, token)
+	assert.True(t, ok)
+	assert.Equal(t, 10, ticketID)
+	assert.Equal(t, 1, bookingID)
+
+	_, _, ok = verifyTicket("other-secret", token)
+	assert.False(t, ok)
+
+	_, _, ok = verifyTicket(testTicketSecret, "11.1."+token[len("10.1."):])
+	assert.False(t, ok)
+
+	_, _, ok = verifyTicket(testTicketSecret, "not-a-ticket")
+	assert.False(t, ok)
+}
+
+func TestTicketUseCase_Disabled(t *testing.T) {
+	for _, secret := range []string{"", "too-short-secret"} {
+		mockBookingRepo := new(MockBookingRepo)
+		mockTicketRepo := new(MockTicketRepo)
+		usecase := &TicketUseCase{
+			Repo:   &repository.Repository{Booking: mockBookingRepo, Ticket: mockTicketRepo},
+			Secret: secret,
+		}
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(entity.Booking{ID: 1, UserID: 1, Status: "paid"}, nil)
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now()), nil)
+
+		_, err := usecase.GetTickets(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrTicketsDisabled)
+
+		_, err = usecase.GetTicketQR(context.Background(), 1, 10)
+		assert.ErrorIs(t, err, ErrTicketsDisabled)
+
+		_, err = usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: signTicket(secret, 10, 1)})
+		assert.ErrorIs(t, err, ErrTicketsDisabled)
+		mockTicketRepo.AssertNotCalled(t, "AdmitTicket", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestTicketUseCase_GetTickets(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockBookingRepo := new(MockBookingRepo)
+		mockTicketRepo := new(MockTicketRepo)
+		usecase := &TicketUseCase{
+			Repo:   &repository.Repository{Booking: mockBookingRepo, Ticket: mockTicketRepo},
+			Secret: testTicketSecret,
+		}
+
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(entity.Booking{ID: 1, UserID: 1, Status: "paid"}, nil)
+		mockTicketRepo.On("GetTicketsByBooking", mock.Anything, 1).Return([]entity.Ticket{testTicket(time.Now())}, nil)
+
+		result, err := usecase.GetTickets(context.Background(), 1, 1)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "A5", result[0].SeatCode)
+		assert.Equal(t, "/api/tickets/10/qr", result[0].QRCodeURL)
+		ticketID, _, ok := verifyTicket(testTicketSecret, result[0].Token)
+		assert.True(t, ok)
+		assert.Equal(t, 10, ticketID)
+	})
+
+	t.Run("Error - Booking Not Paid", func(t *testing.T) {
+		mockBookingRepo := new(MockBookingRepo)
+		mockTicketRepo := new(MockTicketRepo)
+		usecase := &TicketUseCase{
+			Repo:   &repository.Repository{Booking: mockBookingRepo, Ticket: mockTicketRepo},
+			Secret: testTicketSecret,
+		}
+
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(entity.Booking{ID: 1, UserID: 1, Status: "pending"}, nil)
+
+		_, err := usecase.GetTickets(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrTicketsNotIssued)
+		mockTicketRepo.AssertNotCalled(t, "GetTicketsByBooking", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Not Owned", func(t *testing.T) {
+		mockBookingRepo := new(MockBookingRepo)
+		usecase := &TicketUseCase{Repo: &repository.Repository{Booking: mockBookingRepo}, Secret: testTicketSecret}
+
+		mockBookingRepo.On("GetBookingByID", mock.Anything, 1).Return(entity.Booking{ID: 1, UserID: 2, Status: "paid"}, nil)
+
+		_, err := usecase.GetTickets(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrBookingNotOwned)
+	})
+}
+
+func TestTicketUseCase_GetTicketQR(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockTicketRepo := new(MockTicketRepo)
+		usecase := &TicketUseCase{Repo: &repository.Repository{Ticket: mockTicketRepo}, Secret: testTicketSecret}
+
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now()), nil)
+
+		png, err := usecase.GetTicketQR(context.Background(), 1, 10)
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+	})
+
+	t.Run("Error - Another User's Ticket", func(t *testing.T) {
+		mockTicketRepo := new(MockTicketRepo)
+		usecase := &TicketUseCase{Repo: &repository.Repository{Ticket: mockTicketRepo}, Secret: testTicketSecret}
+
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now()), nil)
+
+		_, err := usecase.GetTicketQR(context.Background(), 2, 10)
+		assert.ErrorIs(t, err, ErrTicketNotFound)
+	})
+}
+
+func TestTicketUseCase_ScanTicket(t *testing.T) {
+	token := signTicket(testTicketSecret, 10, 1)
+
+	newUseCase := func() (*TicketUseCase, *MockTicketRepo) {
+		mockTicketRepo := new(MockTicketRepo)
+		return &TicketUseCase{
+			Repo:       &repository.Repository{Ticket: mockTicketRepo},
+			Secret:     testTicketSecret,
+			EntryOpens: 30 * time.Minute,
+		}, mockTicketRepo
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		admittedAt := time.Now()
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now().Add(10*time.Minute)), nil)
+		mockTicketRepo.On("AdmitTicket", mock.Anything, 10, 7).Return(admittedAt, nil)
+
+		result, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, result.TicketID)
+		assert.Equal(t, "Avengers", result.MovieTitle)
+		assert.Equal(t, admittedAt, result.AdmittedAt)
+		mockTicketRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid Signature", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: signTicket("forged", 10, 1)})
+		assert.ErrorIs(t, err, ErrInvalidTicket)
+		mockTicketRepo.AssertNotCalled(t, "GetTicketByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Unknown Ticket", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(entity.Ticket{}, pgx.ErrNoRows)
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.ErrorIs(t, err, ErrInvalidTicket)
+	})
+
+	t.Run("Error - Booking Cancelled", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		ticket := testTicket(time.Now())
+		ticket.BookingStatus = "cancelled"
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(ticket, nil)
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.ErrorIs(t, err, ErrTicketVoid)
+	})
+
+	t.Run("Error - Already Admitted", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		ticket := testTicket(time.Now())
+		admittedAt := time.Now().Add(-5 * time.Minute)
+		ticket.AdmittedAt = &admittedAt
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(ticket, nil)
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.ErrorIs(t, err, ErrTicketAlreadyAdmitted)
+		mockTicketRepo.AssertNotCalled(t, "AdmitTicket", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Entry Not Open Yet", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now().Add(2*time.Hour)), nil)
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.ErrorIs(t, err, ErrTicketOutsideEntryWindow)
+	})
+
+	t.Run("Error - Showing Ended", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now().Add(-3*time.Hour)), nil)
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.ErrorIs(t, err, ErrTicketOutsideEntryWindow)
+	})
+
+	t.Run("Error - Scanned Twice At Once", func(t *testing.T) {
+		usecase, mockTicketRepo := newUseCase()
+		mockTicketRepo.On("GetTicketByID", mock.Anything, 10).Return(testTicket(time.Now()), nil)
+		mockTicketRepo.On("AdmitTicket", mock.Anything, 10, 7).Return(time.Time{}, repository.ErrTicketAlreadyAdmitted)
+
+		_, err := usecase.ScanTicket(context.Background(), 7, dto.TicketScanRequest{Token: token})
+		assert.ErrorIs(t, err, ErrTicketAlreadyAdmitted)
+	})
+}
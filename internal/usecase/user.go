@@ -2,13 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/dto"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type UserUseCaseInterface interface {
 	GetUserByID(ctx context.Context, userID int) (entity.User, error)
-	GetUserProfile(ctx context.Context, userID int) (entity.User, error)
+	GetUserProfile(ctx context.Context, userID int) (dto.ResponseUser, error)
+	UpdateProfile(ctx context.Context, userID int, req dto.UpdateProfileRequest) (dto.ResponseUser, error)
 }
 
 type UserUseCase struct {
@@ -22,6 +28,9 @@ func NewUserUseCase(repo *repository.Repository) UserUseCaseInterface {
 // GetUserByID retrieves user by ID
 func (u *UserUseCase) GetUserByID(ctx context.Context, userID int) (entity.User, error) {
 	user, err := u.Repo.Auth.GetUserByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.User{}, ErrUserNotFound
+	}
 	if err != nil {
 		return entity.User{}, err
 	}
@@ -29,6 +38,63 @@ func (u *UserUseCase) GetUserByID(ctx context.Context, userID int) (entity.User,
 }
 
 // GetUserProfile retrieves user profile
-func (u *UserUseCase) GetUserProfile(ctx context.Context, userID int) (entity.User, error) {
-	return u.GetUserByID(ctx, userID)
+func (u *UserUseCase) GetUserProfile(ctx context.Context, userID int) (dto.ResponseUser, error) {
+	user, err := u.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.ResponseUser{}, err
+	}
+	return toResponseUser(user), nil
+}
+
+// UpdateProfile sets the caller's date of birth
+func (u *UserUseCase) UpdateProfile(ctx context.Context, userID int, req dto.UpdateProfileRequest) (dto.ResponseUser, error) {
+	dateOfBirth, err := parseBirthDate(req.DateOfBirth)
+	if err != nil {
+		return dto.ResponseUser{}, err
+	}
+	if dateOfBirth == nil {
+		return dto.ResponseUser{}, ErrInvalidBirthDate
+	}
+
+	err = u.Repo.Auth.UpdateUserBirthDate(ctx, userID, *dateOfBirth)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.ResponseUser{}, ErrUserNotFound
+	}
+	if err != nil {
+		return dto.ResponseUser{}, err
+	}
+
+	return u.GetUserProfile(ctx, userID)
+}
+
+// parseBirthDate parses an optional YYYY-MM-DD date of birth, which must lie in the past
+func parseBirthDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	dateOfBirth, err := time.Parse("2006-01-02", value)
+	if err != nil || !dateOfBirth.Before(time.Now()) {
+		return nil, ErrInvalidBirthDate
+	}
+	return &dateOfBirth, nil
+}
+
+// formatBirthDate formats a date of birth for responses, empty when unknown
+func formatBirthDate(dateOfBirth *time.Time) string {
+	if dateOfBirth == nil {
+		return ""
+	}
+	return dateOfBirth.Format("2006-01-02")
+}
+
+func toResponseUser(user entity.User) dto.ResponseUser {
+	return dto.ResponseUser{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		IsVerified:  user.IsVerified,
+		Role:        user.Role,
+		DateOfBirth: formatBirthDate(user.DateOfBirth),
+		CreatedAt:   user.CreatedAt,
+	}
 }
@@ -2,9 +2,12 @@ package wire
 
 import (
 	"project-app-bioskop/internal/adaptor"
+	"project-app-bioskop/internal/data/entity"
 	"project-app-bioskop/internal/data/repository"
 	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/gateway"
 	"project-app-bioskop/pkg/middleware"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
 
 	"github.com/go-chi/chi/v5"
@@ -12,7 +15,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func Wiring(repo *repository.Repository, config utils.Configuration, logger *zap.Logger) *chi.Mux {
+func Wiring(repo *repository.Repository, config utils.Configuration, logger *zap.Logger, gateways *gateway.Registry, seatHub *realtime.Hub) *chi.Mux {
 	router := chi.NewRouter()
 
 	// Chi built-in middleware
@@ -25,12 +28,15 @@ func Wiring(repo *repository.Repository, config utils.Configuration, logger *zap
 	router.Use(mw.Logging)
 
 	// Initialize all adaptors
-	adaptors := adaptor.NewAdaptor(repo, config)
+	adaptors := adaptor.NewAdaptor(repo, config, gateways, seatHub, logger)
 
 	// Create auth usecase for middleware
 	authUseCase := usecase.NewAuthUseCase(repo)
 	authMiddleware := middleware.NewAuthMiddleware(authUseCase)
 
+	// Idempotency keys for retry-safe booking and payment
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(usecase.NewIdempotencyUseCase(repo, config))
+
 	// Mount API routes
 	router.Route("/api", func(r chi.Router) {
 		// Public routes - Authentication
@@ -42,16 +48,28 @@ func Wiring(repo *repository.Repository, config utils.Configuration, logger *zap
 		// Public routes - Movies
 		r.Get("/movies", adaptors.MovieAdaptor.GetAll)
 		r.Get("/movies/{movieId}", adaptors.MovieAdaptor.GetByID)
+		r.Get("/movies/{movieId}/showtimes", adaptors.MovieAdaptor.GetSchedule)
+		r.Get("/movies/{movieId}/reviews", adaptors.ReviewAdaptor.GetByMovie)
 
 		// Public routes - Cinema
 		r.Get("/cinemas", adaptors.CinemaAdaptor.GetAll)
 		r.Get("/cinemas/{cinemaId}", adaptors.CinemaAdaptor.GetByID)
 		r.Get("/cinemas/{cinemaId}/showtimes", adaptors.SeatAdaptor.GetShowtimes)
 		r.Get("/cinemas/{cinemaId}/seats", adaptors.SeatAdaptor.GetAvailability)
+		r.Get("/cinemas/{cinemaId}/concessions", adaptors.ConcessionAdaptor.GetByCinema)
+
+		// Public routes - Showtimes
+		r.Get("/showtimes", adaptors.ShowtimeAdaptor.Search)
+		r.Get("/showtimes/{showtimeId}/seats", adaptors.SeatAdaptor.GetSeatMap)
+		r.Get("/showtimes/{showtimeId}/best-seats", adaptors.SeatAdaptor.SuggestSeats)
+		r.Get("/showtimes/{showtimeId}/seats/stream", adaptors.SeatAdaptor.StreamSeats)
 
 		// Public routes - Payment Methods
 		r.Get("/payment-methods", adaptors.PaymentAdaptor.GetMethods)
 
+		// Public routes - Payment provider callbacks, authenticated by signature
+		r.Post("/payments/webhook/{methodId}", adaptors.PaymentAdaptor.Webhook)
+
 		// Protected routes - require authentication
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
@@ -60,11 +78,74 @@ func Wiring(repo *repository.Repository, config utils.Configuration, logger *zap
 			r.Post("/logout", adaptors.AuthAdaptor.Logout)
 
 			// Booking
-			r.Post("/booking", adaptors.BookingAdaptor.Create)
-			r.Post("/pay", adaptors.PaymentAdaptor.ProcessPayment)
+			r.With(idempotencyMiddleware.Idempotent).Post("/booking", adaptors.BookingAdaptor.Create)
+			r.Post("/bookings/{id}/cancel", adaptors.BookingAdaptor.Cancel)
+			r.Get("/bookings/{id}/tickets", adaptors.TicketAdaptor.GetByBooking)
+			r.Get("/tickets/{ticketId}/qr", adaptors.TicketAdaptor.GetQR)
+			r.With(idempotencyMiddleware.Idempotent).Post("/pay", adaptors.PaymentAdaptor.ProcessPayment)
+
+			// Reviews
+			r.Post("/movies/{movieId}/reviews", adaptors.ReviewAdaptor.Create)
+			r.Put("/reviews/{reviewId}", adaptors.ReviewAdaptor.Update)
+			r.Delete("/reviews/{reviewId}", adaptors.ReviewAdaptor.Delete)
 
 			// User routes
 			r.Get("/user/bookings", adaptors.BookingAdaptor.GetUserBookings)
+			r.Get("/user/profile", adaptors.UserAdaptor.GetProfile)
+			r.Put("/user/profile", adaptors.UserAdaptor.UpdateProfile)
+			r.Get("/user/points", adaptors.LoyaltyAdaptor.GetBalance)
+			r.Get("/user/points/history", adaptors.LoyaltyAdaptor.GetHistory)
+		})
+
+		// Staff routes - require cinema staff or admin role
+		r.Route("/staff", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Use(authMiddleware.RequireRole(entity.RoleCinemaStaff, entity.RoleAdmin))
+
+			// Ticket entry
+			r.Post("/tickets/scan", adaptors.TicketAdaptor.Scan)
+		})
+
+		// Admin routes - require admin role
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Use(authMiddleware.RequireRole(entity.RoleAdmin))
+
+			// Users
+			r.Put("/users/{id}/role", adaptors.AuthAdaptor.UpdateRole)
+
+			// Movies
+			r.Post("/movies", adaptors.MovieAdaptor.Create)
+			r.Put("/movies/{movieId}", adaptors.MovieAdaptor.Update)
+			r.Delete("/movies/{movieId}", adaptors.MovieAdaptor.Delete)
+
+			// Cinemas, studios and seat layouts
+			r.Post("/cinemas", adaptors.CinemaAdaptor.Create)
+			r.Put("/cinemas/{cinemaId}", adaptors.CinemaAdaptor.Update)
+			r.Post("/cinemas/{cinemaId}/studios", adaptors.CinemaAdaptor.CreateStudio)
+			r.Put("/studios/{studioId}", adaptors.CinemaAdaptor.UpdateStudio)
+			r.Put("/studios/{studioId}/seats", adaptors.CinemaAdaptor.GenerateSeats)
+
+			// Concessions
+			r.Get("/cinemas/{cinemaId}/concessions", adaptors.ConcessionAdaptor.GetAllByCinema)
+			r.Post("/cinemas/{cinemaId}/concessions", adaptors.ConcessionAdaptor.Create)
+			r.Put("/concessions/{concessionId}", adaptors.ConcessionAdaptor.Update)
+
+			// Showtimes
+			r.Post("/showtimes", adaptors.ShowtimeAdaptor.Create)
+			r.Put("/showtimes/{showtimeId}", adaptors.ShowtimeAdaptor.Reschedule)
+			r.Post("/showtimes/{showtimeId}/cancel", adaptors.ShowtimeAdaptor.Cancel)
+
+			// Promotions
+			r.Get("/promotions", adaptors.PromotionAdaptor.GetAll)
+			r.Post("/promotions", adaptors.PromotionAdaptor.Create)
+			r.Put("/promotions/{promotionId}", adaptors.PromotionAdaptor.Update)
+
+			// Pricing rules
+			r.Get("/pricing-rules", adaptors.PricingAdaptor.GetAll)
+			r.Post("/pricing-rules", adaptors.PricingAdaptor.Create)
+			r.Put("/pricing-rules/{ruleId}", adaptors.PricingAdaptor.Update)
+			r.Delete("/pricing-rules/{ruleId}", adaptors.PricingAdaptor.Delete)
 		})
 	})
 
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
 	"project-app-bioskop/cmd"
 	"project-app-bioskop/internal/data/repository"
+	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/internal/wire"
 	"project-app-bioskop/pkg/database"
+	"project-app-bioskop/pkg/gateway"
+	"project-app-bioskop/pkg/realtime"
 	"project-app-bioskop/pkg/utils"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -30,12 +39,41 @@ func main() {
 	}
 	defer db.Close()
 
+	if !usecase.TicketsEnabled(config.Ticket.SigningSecret) {
+		logger.Warn("e-tickets are disabled, TICKET_SIGNING_SECRET must be at least 32 characters")
+	}
+
 	// Initialize repository
 	repo := repository.NewRepository(db)
 
+	// Initialize payment gateways
+	gateways := gateway.NewDefaultRegistry(config.Payment.SimulatorMode)
+
+	// Shared hub so seat changes from the API and the sweeper reach live seat maps
+	seatHub := realtime.NewHub()
+
 	// Wire all dependencies and routes
-	route := wire.Wiring(repo, config, logger)
+	route := wire.Wiring(repo, config, logger, gateways, seatHub)
+
+	// Cancel background workers and the server on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start booking sweeper to settle stale payments and release seats of unpaid bookings
+	var wg sync.WaitGroup
+	bookingUseCase := usecase.NewBookingUseCase(repo, config, gateways, seatHub)
+	paymentUseCase := usecase.NewPaymentUseCase(repo, config, gateways, seatHub, logger)
+	sweepInterval := time.Duration(config.Booking.SweepIntervalSeconds) * time.Second
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cmd.RunBookingSweeper(ctx, bookingUseCase, paymentUseCase, sweepInterval, logger)
+	}()
 
 	// Start HTTP server
-	cmd.APiserver(route)
+	cmd.APiserver(ctx, route, seatHub)
+
+	// Wait for background workers to finish
+	stop()
+	wg.Wait()
 }
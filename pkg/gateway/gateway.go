@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Payment statuses reported by a gateway
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusRefunded  = "refunded"
+)
+
+// ErrGatewayNotFound is returned when a payment method points to an unregistered gateway
+var ErrGatewayNotFound = errors.New("payment gateway not available")
+
+// ChargeRequest holds the data a gateway needs to charge a booking
+type ChargeRequest struct {
+	PaymentID int
+	BookingID int
+	Amount    float64
+	Details   string
+}
+
+// Result is the outcome of a gateway call
+type Result struct {
+	TransactionID string
+	Status        string
+	Message       string
+}
+
+// PaymentGateway abstracts a payment provider
+type PaymentGateway interface {
+	Charge(ctx context.Context, req ChargeRequest) (Result, error)
+	QueryStatus(ctx context.Context, transactionID string) (Result, error)
+	Refund(ctx context.Context, transactionID string, amount float64) (Result, error)
+}
+
+// Registry maps the gateway name stored on payment_methods rows to an implementation
+type Registry struct {
+	mu       sync.RWMutex
+	gateways map[string]PaymentGateway
+}
+
+// NewRegistry creates an empty gateway registry
+func NewRegistry() *Registry {
+	return &Registry{gateways: make(map[string]PaymentGateway)}
+}
+
+// Register adds or replaces a gateway under the given name
+func (r *Registry) Register(name string, gw PaymentGateway) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gateways[name] = gw
+}
+
+// Get returns the gateway registered under name
+func (r *Registry) Get(name string) (PaymentGateway, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	gw, ok := r.gateways[name]
+	if !ok {
+		return nil, ErrGatewayNotFound
+	}
+	return gw, nil
+}
+
+// NewDefaultRegistry creates a registry with the built-in simulator registered
+func NewDefaultRegistry(simulatorMode string) *Registry {
+	registry := NewRegistry()
+	registry.Register(SimulatorName, NewSimulator(simulatorMode))
+	return registry
+}
@@ -0,0 +1,105 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Simulator modes
+const (
+	ModeSuccess = "success"
+	ModeFail    = "fail"
+	ModePending = "pending"
+)
+
+// SimulatorName is the gateway name used by payment methods backed by the simulator
+const SimulatorName = "simulator"
+
+// ErrTransactionNotFound is returned when the simulator has no record of a transaction
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// Simulator is a local PaymentGateway that resolves charges according to its mode
+type Simulator struct {
+	Mode string
+
+	mu           sync.Mutex
+	transactions map[string]Result
+}
+
+// NewSimulator creates a simulator that succeeds, fails or leaves charges pending
+func NewSimulator(mode string) *Simulator {
+	switch mode {
+	case ModeFail, ModePending:
+	default:
+		mode = ModeSuccess
+	}
+	return &Simulator{
+		Mode:         mode,
+		transactions: make(map[string]Result),
+	}
+}
+
+// Charge records a simulated transaction with a status based on the simulator mode
+func (s *Simulator) Charge(ctx context.Context, req ChargeRequest) (Result, error) {
+	result := Result{TransactionID: "SIM-" + uuid.New().String()}
+	switch s.Mode {
+	case ModeFail:
+		result.Status = StatusFailed
+		result.Message = "payment declined by simulator"
+	case ModePending:
+		result.Status = StatusPending
+		result.Message = "awaiting provider confirmation"
+	default:
+		result.Status = StatusCompleted
+	}
+
+	s.mu.Lock()
+	s.transactions[result.TransactionID] = result
+	s.mu.Unlock()
+	return result, nil
+}
+
+// QueryStatus returns the last known status of a simulated transaction
+func (s *Simulator) QueryStatus(ctx context.Context, transactionID string) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.transactions[transactionID]
+	if !ok {
+		return Result{}, ErrTransactionNotFound
+	}
+	return result, nil
+}
+
+// Refund marks a known simulated transaction as refunded whatever its status, ignoring the
+// amount. In fail mode the refund is declined with a failed result and a nil error.
+func (s *Simulator) Refund(ctx context.Context, transactionID string, amount float64) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.transactions[transactionID]
+	if !ok {
+		return Result{}, ErrTransactionNotFound
+	}
+	if s.Mode == ModeFail {
+		return Result{TransactionID: transactionID, Status: StatusFailed, Message: "refund declined by simulator"}, nil
+	}
+	result.Status = StatusRefunded
+	result.Message = ""
+	s.transactions[transactionID] = result
+	return result, nil
+}
+
+// Resolve sets the final status of a pending simulated transaction
+func (s *Simulator) Resolve(transactionID, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.transactions[transactionID]
+	if !ok {
+		return ErrTransactionNotFound
+	}
+	result.Status = status
+	s.transactions[transactionID] = result
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"project-app-bioskop/internal/usecase"
 	"project-app-bioskop/pkg/utils"
+	"slices"
 )
 
 // AuthMiddleware provides token-based authentication
@@ -17,7 +18,7 @@ func NewAuthMiddleware(authUseCase usecase.AuthUseCaseInterface) *AuthMiddleware
 	return &AuthMiddleware{AuthUseCase: authUseCase}
 }
 
-// RequireAuth validates token and injects user ID into context
+// RequireAuth validates token and injects user ID and role into context
 func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("Authorization")
@@ -38,10 +39,31 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		}
 
 		ctx := context.WithValue(r.Context(), "userID", user.ID)
+		ctx = context.WithValue(ctx, "role", user.Role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// RequireRole only lets through users whose role is one of roles, it must run after RequireAuth
+func (m *AuthMiddleware) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := r.Context().Value("role").(string)
+			if !ok {
+				utils.ResponseUnauthorized(w, "unauthorized")
+				return
+			}
+
+			if !slices.Contains(roles, role) {
+				utils.ResponseForbidden(w, "insufficient permissions")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Auth is a legacy middleware for backward compatibility
 func Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"project-app-bioskop/internal/usecase"
+	"project-app-bioskop/pkg/utils"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client generated key
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader marks responses served from the idempotency store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware replays stored responses for repeated requests carrying the same Idempotency-Key
+type IdempotencyMiddleware struct {
+	UseCase usecase.IdempotencyUseCaseInterface
+}
+
+// NewIdempotencyMiddleware creates a new idempotency middleware instance
+func NewIdempotencyMiddleware(useCase usecase.IdempotencyUseCaseInterface) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{UseCase: useCase}
+}
+
+// responseRecorder captures the status and body written by the wrapped handler
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Idempotent must run after RequireAuth, keys are scoped per user.
+// Requests without the header are passed through unchanged.
+func (m *IdempotencyMiddleware) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "idempotency key is too long", nil)
+			return
+		}
+
+		userID, ok := r.Context().Value("userID").(int)
+		if !ok {
+			utils.ResponseUnauthorized(w, "unauthorized")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseBadRequest(w, http.StatusBadRequest, "invalid request body", nil)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, replay, err := m.UseCase.Begin(r.Context(), userID, key, hashRequest(r, body))
+		switch {
+		case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+			utils.ResponseError(w, http.StatusUnprocessableEntity, err.Error(), nil)
+			return
+		case errors.Is(err, usecase.ErrIdempotencyInProgress):
+			utils.ResponseConflict(w, err.Error())
+			return
+		case err != nil:
+			utils.ResponseInternalError(w, "failed to process idempotency key")
+			return
+		}
+
+		if replay {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(record.StatusCode)
+			w.Write(record.ResponseBody)
+			return
+		}
+
+		// Server errors and panics are not stored so the client may retry with the same key
+		stored := false
+		defer func() {
+			if !stored {
+				m.UseCase.Release(context.WithoutCancel(r.Context()), record.ID)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			return
+		}
+		// Keep the key held even if storing fails, releasing it could let a retry run twice
+		stored = true
+		m.UseCase.Complete(context.WithoutCancel(r.Context()), record.ID, rec.status, rec.body.Bytes())
+	})
+}
+
+// hashRequest fingerprints the method, path and body so a reused key with another request is detected
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
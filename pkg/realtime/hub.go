@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+)
+
+// Reasons a seat event is published
+const (
+	ReasonBookingCreated    = "booking_created"
+	ReasonBookingPaid       = "booking_paid"
+	ReasonBookingCancelled  = "booking_cancelled"
+	ReasonBookingExpired    = "booking_expired"
+	ReasonShowtimeCancelled = "showtime_cancelled"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is dropped
+const subscriberBuffer = 32
+
+// SeatEvent reports that seats of a showtime moved to a new state
+type SeatEvent struct {
+	ShowtimeID int       `json:"showtime_id"`
+	SeatIDs    []int     `json:"seat_ids,omitempty"`
+	State      string    `json:"state,omitempty"`
+	Reason     string    `json:"reason"`
+	At         time.Time `json:"at"`
+}
+
+// Subscription receives the seat events of one showtime until it is closed
+type Subscription struct {
+	C <-chan SeatEvent
+
+	ch         chan SeatEvent
+	hub        *Hub
+	showtimeID int
+}
+
+// Hub fans seat events out to the subscribers of each showtime
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[int]map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[int]map[*Subscription]struct{})}
+}
+
+// Subscribe registers a listener for a showtime. The caller must Close it when done.
+// Once the hub is closed the subscription comes back already closed.
+func (h *Hub) Subscribe(showtimeID int) *Subscription {
+	ch := make(chan SeatEvent, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, hub: h, showtimeID: showtimeID}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return sub
+	}
+	if h.subscribers[showtimeID] == nil {
+		h.subscribers[showtimeID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[showtimeID][sub] = struct{}{}
+	return sub
+}
+
+// Close unregisters the subscription and closes its channel. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// CloseAll closes every subscription and any made afterwards, so open streams end when
+// the server shuts down. A nil hub is a no-op.
+func (h *Hub) CloseAll() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// HasSubscribers reports whether anyone listens to a showtime. A nil hub has none.
+func (h *Hub) HasSubscribers(showtimeID int) bool {
+	if h == nil {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers[showtimeID]) > 0
+}
+
+// Publish delivers an event to every subscriber of its showtime without blocking.
+// A subscriber whose buffer is full is closed, so its client reconnects and reloads
+// the seat map instead of silently missing changes. Publishing on a nil hub is a no-op.
+func (h *Hub) Publish(event SeatEvent) {
+	if h == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers[event.ShowtimeID] {
+		select {
+		case sub.ch <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// remove drops a subscription; the caller must hold h.mu
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subscribers[sub.showtimeID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.showtimeID)
+	}
+}
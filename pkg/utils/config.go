@@ -12,6 +12,42 @@ type Configuration struct {
 	Limit       int
 	PathLogging string
 	DB          DatabaseCofig
+	Booking     BookingConfig
+	Payment     PaymentConfig
+	Idempotency IdempotencyConfig
+	Showtime    ShowtimeConfig
+	Loyalty     LoyaltyConfig
+	Ticket      TicketConfig
+}
+
+type BookingConfig struct {
+	HoldMinutes          int
+	SweepIntervalSeconds int
+	CancelCutoffMinutes  int
+	PaymentGraceMinutes  int
+}
+
+type PaymentConfig struct {
+	SimulatorMode string
+}
+
+type IdempotencyConfig struct {
+	TTLHours int
+}
+
+type ShowtimeConfig struct {
+	CleaningBufferMinutes int
+}
+
+type LoyaltyConfig struct {
+	AmountPerPoint float64
+	PointValue     float64
+	ExpiryDays     int
+}
+
+type TicketConfig struct {
+	SigningSecret     string
+	EntryOpensMinutes int
 }
 
 type DatabaseCofig struct {
@@ -55,6 +91,30 @@ func ReadConfiguration() (Configuration, error) {
 			Port:     viper.GetString("DATABASE_PORT"),
 			MaxConn:  viper.GetInt32("DATABASE_MAX_CONN"),
 		},
+		Booking: BookingConfig{
+			HoldMinutes:          viper.GetInt("BOOKING_HOLD_MINUTES"),
+			SweepIntervalSeconds: viper.GetInt("BOOKING_SWEEP_INTERVAL_SECONDS"),
+			CancelCutoffMinutes:  viper.GetInt("BOOKING_CANCEL_CUTOFF_MINUTES"),
+			PaymentGraceMinutes:  viper.GetInt("BOOKING_PAYMENT_GRACE_MINUTES"),
+		},
+		Payment: PaymentConfig{
+			SimulatorMode: viper.GetString("PAYMENT_SIMULATOR_MODE"),
+		},
+		Idempotency: IdempotencyConfig{
+			TTLHours: viper.GetInt("IDEMPOTENCY_TTL_HOURS"),
+		},
+		Showtime: ShowtimeConfig{
+			CleaningBufferMinutes: viper.GetInt("SHOWTIME_CLEANING_BUFFER_MINUTES"),
+		},
+		Loyalty: LoyaltyConfig{
+			AmountPerPoint: viper.GetFloat64("LOYALTY_AMOUNT_PER_POINT"),
+			PointValue:     viper.GetFloat64("LOYALTY_POINT_VALUE"),
+			ExpiryDays:     viper.GetInt("LOYALTY_EXPIRY_DAYS"),
+		},
+		Ticket: TicketConfig{
+			SigningSecret:     viper.GetString("TICKET_SIGNING_SECRET"),
+			EntryOpensMinutes: viper.GetInt("TICKET_ENTRY_OPENS_MINUTES"),
+		},
 	}, nil
 
 }
@@ -67,6 +67,11 @@ func ResponseNotFound(w http.ResponseWriter, message string) {
 	ResponseError(w, http.StatusNotFound, message, nil)
 }
 
+// ResponseConflict returns 409 Conflict response
+func ResponseConflict(w http.ResponseWriter, message string) {
+	ResponseError(w, http.StatusConflict, message, nil)
+}
+
 // ResponseInternalError returns 500 Internal Server Error response
 func ResponseInternalError(w http.ResponseWriter, message string) {
 	ResponseError(w, http.StatusInternalServerError, message, nil)